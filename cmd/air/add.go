@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add <plan>",
+	Short: "Add an agent to the active run now",
+	Long: `Prepares a plan's worktree and launch script and opens a window for its
+agent in the active run's tmux session, leaving the agents already at work
+alone. 'air run' would replace the whole session instead.
+
+The agent launches right away with the flags the run started with, and waits
+on its channels in its window like the others. To launch it only once its
+channels are signaled and an agent slot is free, use 'air enqueue'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAdd,
+}
+
+func runAdd(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	name := args[0]
+	if _, err := os.Stat(planPath(name)); err != nil {
+		return errPlanNotFound(name)
+	}
+	if err := requireActiveRun(); err != nil {
+		return err
+	}
+	session := runSession()
+	if findAgentWindow(session, name) != "" {
+		if state := agentBarState(name); state != "done" && state != "failed" {
+			return fmt.Errorf("%s is already running in tmux session '%s'", name, session)
+		}
+	}
+
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+	plans, validationErrs := ValidatePlansWithMode(info)
+	if len(validationErrs) > 0 {
+		fmt.Println("Dependency validation failed:")
+		for _, err := range validationErrs {
+			fmt.Printf("  ✗ %s\n", err)
+		}
+		return withCode(codeValidationFailed, fmt.Errorf("invalid dependency graph"))
+	}
+	var pd PlanDependencies
+	for _, p := range plans {
+		if p.Name == name {
+			pd = p
+		}
+	}
+	if len(skipPausedPlans([]string{name})) == 0 {
+		return nil
+	}
+
+	contextContent, err := os.ReadFile(getContextPath())
+	if err != nil {
+		return fmt.Errorf("failed to read context: %w", err)
+	}
+	if record, _ := readRunRecord(); record != nil {
+		record.Flags.use()
+	}
+
+	agent, runAgent, err := prepareAgent(info, pd, plans, contextContent)
+	if err != nil {
+		return err
+	}
+	if err := recordRun(info, []RunAgent{runAgent}); err != nil {
+		return fmt.Errorf("failed to write run.json: %w", err)
+	}
+	if err := addAgentsToSession(info, []worktreeInfo{agent}); err != nil {
+		return err
+	}
+	// It's launched, so the dashboard mustn't launch it again
+	dequeue(name)
+
+	fmt.Printf("Added %s to tmux session '%s' (worktree %s)\n", name, session, agent.wtPath)
+	fmt.Printf("Switch to it with: tmux select-window -t %s:%s\n", session, name)
+	return nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt <branch-or-path>",
+	Short: "Make an existing worktree or branch a plan's agent",
+	Long: `Registers work started outside air (a worktree or branch created by hand, or
+left by a run that crashed) as the agent of --plan, so it can be launched,
+monitored, and cleaned like any other.
+
+Given a worktree path, the worktree is moved to where air keeps the plan's
+worktree; given a branch, a worktree is created for it there. Either way the
+branch is renamed to air/<plan>, and the agent gets its directory, briefing,
+and launch script, with its base set to where the branch forked from the base
+branch. Start it with 'air launch <plan>', or 'air add <plan>' during a run.
+
+--plan defaults to <plan> for a branch named air/<plan>.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAdopt,
+}
+
+var adoptPlan string
+
+func init() {
+	adoptCmd.Flags().StringVar(&adoptPlan, "plan", "", "Plan the work belongs to (default: from an air/<plan> branch)")
+}
+
+func runAdopt(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	target := args[0]
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+
+	// A worktree path names its branch; anything else is a branch
+	fromPath := false
+	branch := target
+	if fi, err := os.Stat(target); err == nil && fi.IsDir() {
+		fromPath = true
+		if target, err = filepath.Abs(target); err != nil {
+			return err
+		}
+		out, err := exec.Command("git", "-C", target, "symbolic-ref", "--short", "HEAD").Output()
+		if err != nil {
+			return fmt.Errorf("%s isn't a git worktree with a branch checked out", target)
+		}
+		branch = strings.TrimSpace(string(out))
+	}
+
+	name := adoptPlan
+	if name == "" {
+		name = strings.TrimPrefix(branch, "air/")
+		if name == branch {
+			return withCode(codeUsage, fmt.Errorf("name the plan with --plan (branch %s isn't air/<plan>)", branch))
+		}
+	}
+	if _, err := os.Stat(planPath(name)); err != nil {
+		return errPlanNotFound(name)
+	}
+	plans, _ := ValidatePlansWithMode(info)
+	var pd PlanDependencies
+	for _, p := range plans {
+		if p.Name == name {
+			pd = p
+		}
+	}
+	if pd.Name == "" {
+		return fmt.Errorf("failed to read plan %s (see 'air plan validate')", name)
+	}
+
+	repoName, repoPath, wtPath := agentPaths(info, pd)
+	if _, err := os.Stat(wtPath); err == nil {
+		return fmt.Errorf("%s already has a worktree at %s", name, wtPath)
+	}
+	if fromPath {
+		common := gitCommonDir(target)
+		if common == "" || common != gitCommonDir(repoPath) {
+			return fmt.Errorf("%s isn't a worktree of %s, %s's repository", target, repoPath, name)
+		}
+		// Only linked worktrees have a git directory of their own
+		out, _ := exec.Command("git", "-C", target, "rev-parse", "--show-toplevel", "--absolute-git-dir").Output()
+		top, gitDir, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+		if gitDir == common {
+			return fmt.Errorf("%s is the repository's main checkout, not a worktree", top)
+		}
+		target = top
+	} else if exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch).Run() != nil {
+		return fmt.Errorf("no branch %s in %s (pass a worktree path, or a branch of %s's repository)", branch, repoPath, name)
+	}
+
+	// air finds an agent's branch by its plan
+	airBranch := "air/" + name
+	if branch != airBranch {
+		if exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+airBranch).Run() == nil {
+			return fmt.Errorf("branch %s already exists; delete it or adopt it instead", airBranch)
+		}
+		if out, err := exec.Command("git", "-C", repoPath, "branch", "-m", branch, airBranch).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to rename %s to %s: %s", branch, airBranch, strings.TrimSpace(string(out)))
+		}
+		fmt.Printf("Renamed branch %s to %s\n", branch, airBranch)
+	}
+
+	os.MkdirAll(filepath.Dir(wtPath), 0755)
+	worktreeArgs := []string{"-C", repoPath, "worktree", "add", wtPath, airBranch}
+	if fromPath {
+		worktreeArgs = []string{"-C", repoPath, "worktree", "move", target, wtPath}
+	}
+	if out, err := exec.Command("git", worktreeArgs...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set up the worktree of %s: %s", name, strings.TrimSpace(string(out)))
+	}
+
+	// The base is where the branch forked, not the base branch's tip now
+	agentDir := filepath.Join(getAgentsDir(), name)
+	if err := os.MkdirAll(agentDir, 0755); err != nil {
+		return fmt.Errorf("failed to create agent directory: %w", err)
+	}
+	base := resolveAgentBase(repoPath, info.baseBranch(repoName))
+	if out, err := exec.Command("git", "-C", repoPath, "merge-base", base.SHA, airBranch).Output(); err == nil {
+		base.SHA = strings.TrimSpace(string(out))
+	}
+	if err := writeAgentBase(agentDir, base); err != nil {
+		return fmt.Errorf("failed to record base for %s: %w", name, err)
+	}
+
+	contextContent, err := os.ReadFile(getContextPath())
+	if err != nil {
+		return fmt.Errorf("failed to read context: %w", err)
+	}
+	if record, _ := readRunRecord(); record != nil {
+		record.Flags.use()
+	}
+	if err := os.MkdirAll(getChannelsDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create channels directory: %w", err)
+	}
+	_, runAgent, err := prepareAgent(info, pd, plans, contextContent)
+	if err != nil {
+		return err
+	}
+	if err := recordRun(info, []RunAgent{runAgent}); err != nil {
+		return fmt.Errorf("failed to write run.json: %w", err)
+	}
+
+	fmt.Printf("Adopted %s as %s (worktree %s, based on %s at %s)\n", branch, name, wtPath, base.Branch, shortRef(base.SHA))
+	fmt.Printf("Start it with 'air launch %s', or 'air add %s' during a run.\n", name, name)
+	return nil
+}
+
+// gitCommonDir returns the repository directory shared by all worktrees of
+// the repo at path, or "" if path isn't in one
+func gitCommonDir(path string) string {
+	out, err := exec.Command("git", "-C", path, "rev-parse", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
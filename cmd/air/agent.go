@@ -3,9 +3,15 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -13,13 +19,62 @@ import (
 
 // ChannelPayload represents the data written to a channel file when signaled
 type ChannelPayload struct {
-	SHA       string    `json:"sha"`
-	Branch    string    `json:"branch"`
-	Worktree  string    `json:"worktree"`
-	Agent     string    `json:"agent"`
-	Repo      string    `json:"repo,omitempty"`      // Source repo (workspace mode only)
-	Workspace string    `json:"workspace,omitempty"` // Workspace name (workspace mode only)
-	Timestamp time.Time `json:"timestamp"`
+	SHA       string     `json:"sha"`
+	Branch    string     `json:"branch"`
+	Worktree  string     `json:"worktree"`
+	Agent     string     `json:"agent"`
+	Repo      string     `json:"repo,omitempty"`      // Source repo (workspace mode only)
+	Workspace string     `json:"workspace,omitempty"` // Workspace name (workspace mode only)
+	Timestamp time.Time  `json:"timestamp"`
+	Message   string     `json:"message,omitempty"`    // Free-form note (e.g. a task for queue consumers)
+	Summary   string     `json:"summary,omitempty"`    // What the agent accomplished (done channels only)
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Signal is ignored after this time (set via --ttl)
+
+	// Diffstat and ChangedFiles describe the branch relative to its recorded base (done channels only)
+	Diffstat     *Diffstat `json:"diffstat,omitempty"`
+	ChangedFiles []string  `json:"changed_files,omitempty"`
+
+	// Verified records the verify command passing on SHA (done channels only)
+	Verified *Verification `json:"verified,omitempty"`
+
+	// Unsignaled lists the channels the agent's plan still owed when it gave up (failed channels only)
+	Unsignaled []string `json:"unsignaled,omitempty"`
+
+	// ConsumedBy is the agent that popped this entry (queue channels only)
+	ConsumedBy string `json:"consumed_by,omitempty"`
+
+	// History holds earlier signals of this channel, oldest first (set via --force)
+	History []ChannelPayload `json:"history,omitempty"`
+}
+
+// Diffstat summarizes a branch's changes against its base commit
+type Diffstat struct {
+	Base       string `json:"base"`
+	Files      int    `json:"files"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+}
+
+// expired reports whether the signal has passed its own expiry or the global
+// TTL from AIR_CHANNEL_TTL. Expired channels are treated as not signaled.
+func (p *ChannelPayload) expired(now time.Time) bool {
+	if p.ExpiresAt != nil && now.After(*p.ExpiresAt) {
+		return true
+	}
+	if ttl := channelTTL(); ttl > 0 && now.Sub(p.Timestamp) > ttl {
+		return true
+	}
+	return false
+}
+
+// channelTTL returns the global channel TTL from AIR_CHANNEL_TTL (0 = never expire)
+func channelTTL() time.Duration {
+	if v := os.Getenv("AIR_CHANNEL_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return 0
 }
 
 var agentCmd = &cobra.Command{
@@ -31,40 +86,122 @@ var agentCmd = &cobra.Command{
 var agentSignalCmd = &cobra.Command{
 	Use:   "signal <channel>",
 	Short: "Signal a channel with the current commit",
-	Long:  `Signals a channel to notify waiting agents. Captures the current HEAD commit SHA and writes it to the channel file.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runAgentSignal,
+	Long: `Signals a channel to notify waiting agents. Captures the current HEAD commit SHA and writes it to the channel file.
+
+A channel can only be signaled once. Use --force (or --update) to re-signal after
+fixing a bug in a dependency; earlier signals are kept in the payload's history.
+
+With --queue, the channel is a queue: each signal appends an entry and each
+'air agent wait --consume' pops exactly one.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentSignal,
 }
 
+var signalTTL time.Duration
+var signalForce bool
+var signalQueue bool
+var signalMessage string
+
 var agentWaitCmd = &cobra.Command{
 	Use:   "wait <channel>",
 	Short: "Wait for a channel to be signaled",
-	Long:  `Blocks until the specified channel is signaled, then prints the channel payload.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runAgentWait,
+	Long: `Blocks until the specified channel is signaled, then prints the channel payload.
+
+With --merge, merges the signaled branch as soon as the wait completes (same as
+running 'air agent merge' next). Cross-repo signals are not merged.
+
+With --consume, waits on a queue channel and pops exactly one entry. Each entry
+goes to a single consumer, so several workers can share a queue.
+
+With --timeout, stops waiting if the channel isn't signaled in time and applies
+--on-timeout:
+  fail     exit with status 1 (default)
+  proceed  exit successfully without the dependency (nothing is merged)
+  ask      record that a human is needed (shown in 'air status') and keep waiting
+
+If the agent that would signal the channel runs 'air agent fail', the wait stops
+early: it exits with an error, or succeeds without the dependency under
+--on-timeout proceed.
+
+Use --format json or --format env to print only the payload for scripts, e.g.
+  eval "$(air agent wait schema-ready --format env)"   # sets DEP_SHA, DEP_BRANCH, ...
+Progress messages then go to stderr.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentWait,
 }
 
+var waitConsume bool
+var waitMerge bool
+var waitFormat string
+var waitTimeout time.Duration
+var waitOnTimeout string
+
 var agentMergeCmd = &cobra.Command{
 	Use:   "merge <channel>",
 	Short: "Merge changes from a signaled channel's branch",
-	Long:  `Reads the branch from a signaled channel and merges it into the current worktree. This brings in all commits from the dependency, including any transitive dependencies.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runAgentMerge,
+	Long: `Reads the branch from a signaled channel and merges it into the current worktree. This brings in all commits from the dependency, including any transitive dependencies.
+
+Merges exactly the signaled commit, even if the producer's branch has moved on
+since. Use --allow-newer to merge the branch tip instead (it must still contain
+the signaled commit).
+
+Cross-repo signals (workspace mode) can't be merged. With --fetch, the signaled
+commit is fetched from the producer's worktree into refs/air/deps/<channel> so
+its files can be read with 'git show', without merging anything.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentMerge,
 }
 
+var mergeAllowNewer bool
+var mergeFetch bool
+
 var agentDoneCmd = &cobra.Command{
 	Use:   "done",
 	Short: "Signal that this agent is complete",
-	Long:  `Signals completion by writing to the done/<agent-id> channel.`,
-	Args:  cobra.NoArgs,
-	RunE:  runAgentDone,
+	Long: `Signals completion by writing to the done/<agent-id> channel.
+
+Pass --summary (or --summary-file) to record what was accomplished; it is shown
+in 'air status' and given to the integration session.
+
+If the repo sets a verify command ('git config air.verify "make test"'), it
+runs in the worktree first, and the agent isn't done until it passes.`,
+	Args: cobra.NoArgs,
+	RunE: runAgentDone,
 }
 
+var doneSummary string
+var doneSummaryFile string
+
+// doneChanges and doneVerified are computed by 'air agent done' and embedded
+// in its payload
+var doneChanges *Diffstat
+var doneChangedFiles []string
+var doneVerified *Verification
+
 func init() {
 	agentCmd.AddCommand(agentSignalCmd)
 	agentCmd.AddCommand(agentWaitCmd)
 	agentCmd.AddCommand(agentMergeCmd)
 	agentCmd.AddCommand(agentDoneCmd)
+
+	agentSignalCmd.Flags().DurationVar(&signalTTL, "ttl", 0, "Expire the signal after this duration (default: AIR_CHANNEL_TTL, or never)")
+	agentSignalCmd.Flags().BoolVar(&signalForce, "force", false, "Overwrite an existing signal, keeping it in the channel history")
+	agentSignalCmd.Flags().BoolVar(&signalForce, "update", false, "Alias for --force")
+	agentSignalCmd.Flags().BoolVar(&signalQueue, "queue", false, "Append an entry to a queue channel instead of signaling once")
+	agentSignalCmd.Flags().StringVarP(&signalMessage, "message", "m", "", "Attach a message to the signal")
+
+	agentWaitCmd.Flags().BoolVar(&waitConsume, "consume", false, "Pop one entry from a queue channel")
+	agentWaitCmd.Flags().BoolVar(&waitMerge, "merge", false, "Merge the signaled branch once the wait completes")
+	agentWaitCmd.Flags().StringVar(&waitFormat, "format", "text", "Output format: text, json, or env")
+	agentWaitCmd.Flags().DurationVar(&waitTimeout, "timeout", 0, "Give up after waiting this long (default: wait forever)")
+	agentWaitCmd.Flags().StringVar(&waitOnTimeout, "on-timeout", "fail", "What to do on timeout: fail, proceed, or ask")
+	agentWaitCmd.Flags().BoolVar(&mergeAllowNewer, "allow-newer", false, "With --merge, merge the branch tip even if it moved past the signaled commit")
+
+	agentMergeCmd.Flags().BoolVar(&mergeAllowNewer, "allow-newer", false, "Merge the branch tip even if it moved past the signaled commit")
+	agentMergeCmd.Flags().BoolVar(&mergeFetch, "fetch", false, "For a cross-repo signal, fetch the signaled commit instead of failing")
+
+	agentDoneCmd.Flags().StringVar(&doneSummary, "summary", "", "Summary of what was accomplished")
+	agentDoneCmd.Flags().StringVar(&doneSummaryFile, "summary-file", "", "Read the summary from a file")
 }
 
 // getChannelPath returns the full path to a channel file
@@ -109,10 +246,98 @@ func writeChannel(channel string, payload *ChannelPayload) error {
 	return nil
 }
 
-// channelExists checks if a channel has been signaled
+// ChannelAck records that a consumer picked up a signal
+type ChannelAck struct {
+	Agent     string    `json:"agent"`
+	SHA       string    `json:"sha"`    // SHA of the signal that was consumed
+	Merged    bool      `json:"merged"` // true once merged via 'air agent merge'
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// getAckDir returns the directory holding per-consumer acks for a channel
+func getAckDir(channel string) string {
+	return filepath.Join(getChannelsDir(), "acks", channel)
+}
+
+// writeChannelAck records that the current agent consumed the channel's signal.
+// A no-op outside an agent (AIR_AGENT_ID unset). Acks are updated under the
+// channel's ack lock, and a merge of the signal stays recorded when a later
+// wait acks it again.
+func writeChannelAck(channel string, payload *ChannelPayload, merged bool) error {
+	agentID := os.Getenv("AIR_AGENT_ID")
+	if agentID == "" {
+		return nil
+	}
+
+	dir := getAckDir(channel)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create ack directory: %w", err)
+	}
+	unlock, err := lockFile(dir + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := filepath.Join(dir, agentID+".json")
+	if data, err := os.ReadFile(path); err == nil {
+		var prev ChannelAck
+		if json.Unmarshal(data, &prev) == nil && prev.SHA == payload.SHA && prev.Merged {
+			merged = true
+		}
+	}
+	ack := ChannelAck{Agent: agentID, SHA: payload.SHA, Merged: merged, Timestamp: time.Now().UTC()}
+	data, err := json.MarshalIndent(ack, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ack: %w", err)
+	}
+	// Write then rename so readers never see a partial file
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write ack: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write ack: %w", err)
+	}
+	return nil
+}
+
+// readChannelAcks returns the acks for the channel's current signal, keyed by agent.
+// Acks of earlier signals (before a --force re-signal) are ignored.
+func readChannelAcks(channel string, payload *ChannelPayload) map[string]ChannelAck {
+	acks := make(map[string]ChannelAck)
+	entries, err := os.ReadDir(getAckDir(channel))
+	if err != nil {
+		return acks
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(getAckDir(channel), e.Name()))
+		if err != nil {
+			continue
+		}
+		var ack ChannelAck
+		if err := json.Unmarshal(data, &ack); err != nil || ack.SHA != payload.SHA {
+			continue
+		}
+		acks[ack.Agent] = ack
+	}
+	return acks
+}
+
+// channelExists checks if a channel has been signaled and the signal hasn't expired
 func channelExists(channel string) bool {
-	_, err := os.Stat(getChannelPath(channel))
-	return err == nil
+	if _, err := os.Stat(getChannelPath(channel)); err != nil {
+		return false
+	}
+	payload, err := readChannel(channel)
+	if err != nil {
+		// Unreadable (e.g. mid-write) - treat as signaled, matching plain existence
+		return true
+	}
+	return !payload.expired(time.Now())
 }
 
 // getCurrentSHA returns the current HEAD commit SHA
@@ -154,9 +379,20 @@ func runAgentSignal(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("AIR_AGENT_ID environment variable is required")
 	}
 
-	// Check if channel already signaled
-	if channelExists(channel) {
-		return fmt.Errorf("channel '%s' has already been signaled", channel)
+	// Check if channel already signaled. An expired signal needs no --force to
+	// replace, but like a forced one it's kept in the history.
+	var previous *ChannelPayload
+	if !signalQueue && channelExists(channel) {
+		if !signalForce {
+			return fmt.Errorf("channel '%s' has already been signaled (use --force to re-signal)", channel)
+		}
+		p, err := readChannel(channel)
+		if err != nil {
+			return err
+		}
+		previous = p
+	} else if !signalQueue {
+		previous, _ = readChannel(channel)
 	}
 
 	// Get current HEAD SHA
@@ -194,13 +430,40 @@ func runAgentSignal(cmd *cobra.Command, args []string) error {
 		Repo:      repo,
 		Workspace: workspace,
 		Timestamp: time.Now().UTC(),
+		Message:   signalMessage,
+		Summary:   doneSummary,
+	}
+	payload.Diffstat, payload.ChangedFiles, payload.Verified = doneChanges, doneChangedFiles, doneVerified
+	ttl := signalTTL
+	if ttl == 0 {
+		ttl = channelTTL()
+	}
+	if ttl > 0 {
+		expires := payload.Timestamp.Add(ttl)
+		payload.ExpiresAt = &expires
+	}
+	appendHistory(payload, previous)
+
+	if signalQueue {
+		if err := enqueueChannel(channel, payload); err != nil {
+			return err
+		}
+		pending, _ := pendingQueueEntries(channel)
+		fmt.Printf("Queued entry on '%s' (sha: %s, %d pending)\n", channel, sha[:8], len(pending))
+		return nil
 	}
 
 	if err := writeChannel(channel, payload); err != nil {
 		return err
 	}
 
-	if repo != "" {
+	if previous != nil {
+		prevSHA := previous.SHA
+		if len(prevSHA) > 8 {
+			prevSHA = prevSHA[:8]
+		}
+		fmt.Printf("Re-signaled channel '%s' (branch: %s, sha: %s, previous sha: %s)\n", channel, branch, sha[:8], prevSHA)
+	} else if repo != "" {
 		fmt.Printf("Signaled channel '%s' (repo: %s, branch: %s, sha: %s)\n", channel, repo, branch, sha[:8])
 	} else {
 		fmt.Printf("Signaled channel '%s' (branch: %s, sha: %s)\n", channel, branch, sha[:8])
@@ -208,21 +471,75 @@ func runAgentSignal(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// appendHistory records previous (if any) in payload's history, flattened so
+// each entry is a single signal
+func appendHistory(payload, previous *ChannelPayload) {
+	if previous == nil {
+		return
+	}
+	payload.History = append(previous.History, *previous)
+	payload.History[len(payload.History)-1].History = nil
+}
+
 func runAgentWait(cmd *cobra.Command, args []string) error {
 	channel := args[0]
 
-	fmt.Printf("Waiting for channel '%s'...\n", channel)
+	if waitFormat != "text" && waitFormat != "json" && waitFormat != "env" {
+		return fmt.Errorf("invalid --format '%s' (expected text, json, or env)", waitFormat)
+	}
+	// Keep stdout clean for machine-readable formats
+	progress := io.Writer(os.Stdout)
+	if waitFormat != "text" {
+		progress = os.Stderr
+	}
 
-	// Poll until channel exists (interval configurable via AIR_POLL_INTERVAL for testing)
-	pollInterval := 2 * time.Second
-	if envInterval := os.Getenv("AIR_POLL_INTERVAL"); envInterval != "" {
-		if d, err := time.ParseDuration(envInterval); err == nil {
-			pollInterval = d
+	if !contains(waitFallbacks, waitOnTimeout) {
+		return fmt.Errorf("invalid --on-timeout '%s' (expected %s)", waitOnTimeout, strings.Join(waitFallbacks, ", "))
+	}
+
+	if waitConsume {
+		if waitMerge {
+			return fmt.Errorf("--merge cannot be used with --consume")
 		}
+		if waitOnTimeout != "fail" {
+			return fmt.Errorf("--on-timeout cannot be used with --consume")
+		}
+		return runAgentConsume(channel, progress)
 	}
+
+	fmt.Fprintf(progress, "Waiting for channel '%s'...\n", channel)
+
+	// Poll until channel exists
+	interval := pollInterval()
+	start := time.Now()
+	timedOut := false
 	for !channelExists(channel) {
-		time.Sleep(pollInterval)
+		if f := failedProducer(channel); f != nil {
+			if waitOnTimeout == "proceed" {
+				fmt.Fprintf(progress, "Producer '%s' of channel '%s' failed (%s); proceeding without it.\n", f.Agent, channel, f.Message)
+				return nil
+			}
+			notifyHuman(fmt.Sprintf("air: %s is blocked: '%s' won't be signaled, %s failed", os.Getenv("AIR_AGENT_ID"), channel, f.Agent))
+			return withCode(codeDependencyMissing, fmt.Errorf("channel '%s' won't be signaled: its producer '%s' failed: %s", channel, f.Agent, f.Message))
+		}
+		if waitTimeout > 0 && !timedOut && time.Since(start) > waitTimeout {
+			recordWaitTimeout(channel)
+			switch waitOnTimeout {
+			case "proceed":
+				fmt.Fprintf(progress, "Timed out after %s waiting for channel '%s'; proceeding without it.\n", waitTimeout, channel)
+				return nil
+			case "ask":
+				fmt.Fprintf(progress, "Timed out after %s waiting for channel '%s'; asked a human to step in. Still waiting...\n", waitTimeout, channel)
+				notifyHuman(fmt.Sprintf("air: %s needs a human: '%s' not signaled after %s", os.Getenv("AIR_AGENT_ID"), channel, waitTimeout))
+				timedOut = true
+			default:
+				notifyHuman(fmt.Sprintf("air: %s is blocked: '%s' not signaled after %s", os.Getenv("AIR_AGENT_ID"), channel, waitTimeout))
+				return withCode(codeDependencyMissing, fmt.Errorf("timed out after %s waiting for channel '%s'", waitTimeout, channel))
+			}
+		}
+		time.Sleep(interval)
 	}
+	clearWaitTimeout(channel)
 
 	// Read and print payload
 	payload, err := readChannel(channel)
@@ -230,24 +547,181 @@ func runAgentWait(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Print human-readable output
-	fmt.Printf("\nChannel '%s' signaled by agent '%s'\n", channel, payload.Agent)
+	if err := writeChannelAck(channel, payload, false); err != nil {
+		return err
+	}
+
+	if err := writeWaitResult(fmt.Sprintf("Channel '%s' signaled by agent '%s'", channel, payload.Agent), channel, payload); err != nil {
+		return err
+	}
+
+	if err := propagateConsumed(payload, progress); err != nil {
+		return err
+	}
+
+	if waitMerge {
+		if currentRepo := os.Getenv("AIR_REPO"); payload.Repo != "" && currentRepo != "" && payload.Repo != currentRepo {
+			fmt.Fprintln(progress, "\nSkipping merge for cross-repo dependency.")
+			return nil
+		}
+		fmt.Fprintln(progress)
+		return mergeChannel(channel, payload, progress)
+	}
+	return nil
+}
+
+// writeWaitResult prints a received payload in the --format requested
+func writeWaitResult(header, channel string, payload *ChannelPayload) error {
+	switch waitFormat {
+	case "json":
+		data, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		fmt.Println(string(data))
+	case "env":
+		for _, kv := range [][2]string{
+			{"DEP_CHANNEL", channel},
+			{"DEP_SHA", payload.SHA},
+			{"DEP_BRANCH", payload.Branch},
+			{"DEP_WORKTREE", payload.Worktree},
+			{"DEP_AGENT", payload.Agent},
+			{"DEP_REPO", payload.Repo},
+			{"DEP_MESSAGE", payload.Message},
+		} {
+			fmt.Printf("export %s=%s\n", kv[0], shellQuote(kv[1]))
+		}
+	default:
+		fmt.Printf("\n%s\n", header)
+		printChannelPayload(channel, payload)
+	}
+	return nil
+}
+
+// WaitTimeout records that an agent's bounded wait on a channel ran out
+type WaitTimeout struct {
+	Agent     string    `json:"agent"`
+	Channel   string    `json:"channel"`
+	Timeout   string    `json:"timeout"`
+	Fallback  string    `json:"fallback"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// getWaitTimeoutsDir returns the directory holding timed-out wait records
+func getWaitTimeoutsDir() string {
+	return filepath.Join(getChannelsDir(), "timeouts")
+}
+
+// waitTimeoutPath returns the record file for an agent's wait on a channel
+func waitTimeoutPath(agent, channel string) string {
+	return filepath.Join(getWaitTimeoutsDir(), agent, url.PathEscape(channel)+".json")
+}
+
+// recordWaitTimeout notes that the current agent's wait on channel timed out
+// (best effort; a no-op outside an agent)
+func recordWaitTimeout(channel string) {
+	agentID := os.Getenv("AIR_AGENT_ID")
+	if agentID == "" {
+		return
+	}
+	path := waitTimeoutPath(agentID, channel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	record := WaitTimeout{Agent: agentID, Channel: channel, Timeout: waitTimeout.String(), Fallback: waitOnTimeout, Timestamp: time.Now().UTC()}
+	if data, err := json.MarshalIndent(record, "", "  "); err == nil {
+		os.WriteFile(path, data, 0644)
+	}
+}
+
+// notifyHuman flashes message on the tmux session and rings the bell in the
+// agent's window, so the human notices while focused on another window. Best
+// effort: a no-op outside tmux or with AIR_NOTIFY=0.
+func notifyHuman(message string) {
+	if os.Getenv("TMUX") == "" || os.Getenv("AIR_NOTIFY") == "0" {
+		return
+	}
+	exec.Command("tmux", "display-message", message).Run()
+
+	// Claude captures the command's output, so ring the bell on the pane's tty
+	pane := os.Getenv("TMUX_PANE")
+	if pane == "" {
+		return
+	}
+	out, err := exec.Command("tmux", "display-message", "-p", "-t", pane, "#{pane_tty}").Output()
+	if err != nil {
+		return
+	}
+	if tty, err := os.OpenFile(strings.TrimSpace(string(out)), os.O_WRONLY, 0); err == nil {
+		tty.WriteString("\a")
+		tty.Close()
+	}
+}
+
+// clearWaitTimeout removes the current agent's timeout record once the channel arrives
+func clearWaitTimeout(channel string) {
+	if agentID := os.Getenv("AIR_AGENT_ID"); agentID != "" {
+		os.Remove(waitTimeoutPath(agentID, channel))
+	}
+}
+
+// listWaitTimeouts returns all recorded wait timeouts, oldest first
+func listWaitTimeouts() []WaitTimeout {
+	var records []WaitTimeout
+	filepath.WalkDir(getWaitTimeoutsDir(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var record WaitTimeout
+		if json.Unmarshal(data, &record) == nil {
+			records = append(records, record)
+		}
+		return nil
+	})
+	sort.Slice(records, func(i, j int) bool { return records[i].Timestamp.Before(records[j].Timestamp) })
+	return records
+}
+
+// shellQuote single-quotes s for safe use in a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// pollInterval returns how often wait loops check for signals
+// (configurable via AIR_POLL_INTERVAL for testing)
+func pollInterval() time.Duration {
+	if envInterval := os.Getenv("AIR_POLL_INTERVAL"); envInterval != "" {
+		if d, err := time.ParseDuration(envInterval); err == nil {
+			return d
+		}
+	}
+	return 2 * time.Second
+}
+
+// printChannelPayload prints the human-readable details of a signal
+func printChannelPayload(channel string, payload *ChannelPayload) {
 	if payload.Repo != "" {
 		fmt.Printf("Repository: %s\n", payload.Repo)
 	}
 	fmt.Printf("Branch: %s\n", payload.Branch)
 	fmt.Printf("Worktree: %s\n", payload.Worktree)
 	fmt.Printf("SHA: %s\n", payload.SHA)
+	if payload.Message != "" {
+		fmt.Printf("Message: %s\n", payload.Message)
+	}
 
 	// If cross-repo, provide guidance
 	currentRepo := os.Getenv("AIR_REPO")
 	if payload.Repo != "" && currentRepo != "" && payload.Repo != currentRepo {
 		fmt.Printf("\nNOTE: This is a cross-repo dependency (%s -> %s).\n", payload.Repo, currentRepo)
 		fmt.Printf("You can read the changes at the worktree path above.\n")
-		fmt.Printf("Use 'air agent merge' only for same-repo dependencies.\n")
+		fmt.Printf("Use 'air agent merge --fetch %s' to fetch the commit without merging,\n", channel)
+		fmt.Printf("or 'air agent fetch' for artifacts the producer published.\n")
 	}
-
-	return nil
 }
 
 func runAgentMerge(cmd *cobra.Command, args []string) error {
@@ -257,41 +731,144 @@ func runAgentMerge(cmd *cobra.Command, args []string) error {
 	payload, err := readChannel(channel)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("channel '%s' has not been signaled yet", channel)
+			return withCode(codeDependencyMissing, fmt.Errorf("channel '%s' has not been signaled yet", channel))
 		}
 		return err
 	}
 
-	// Check for cross-repo merge attempt (not supported)
+	return mergeChannel(channel, payload, os.Stdout)
+}
+
+// mergeChannel merges a signaled channel's branch into the current worktree,
+// writing progress to out
+func mergeChannel(channel string, payload *ChannelPayload, out io.Writer) error {
+	// Cross-repo branches can't be merged; optionally fetch them for reading instead
 	currentRepo := os.Getenv("AIR_REPO")
 	if payload.Repo != "" && currentRepo != "" && payload.Repo != currentRepo {
+		if mergeFetch {
+			return fetchCrossRepo(channel, payload, out)
+		}
 		return fmt.Errorf(`cross-repo merge not supported: channel '%s' is from repo '%s', but you are in repo '%s'
 
 For cross-repo dependencies:
 - Use 'air agent wait %s' to know when the dependency is ready
-- Read files from the dependency's worktree: %s
+- Have the producer share build outputs with 'air agent publish <name> <path>',
+  then copy them into your worktree with 'air agent fetch <name>'
+- Or run 'air agent merge --fetch %s' to fetch the signaled commit as
+  %s and read files with 'git show %s:<path>'
 - Update your dependency version if needed (e.g., go get, npm update)
-- Do NOT use 'air agent merge' across repos
 
 'merge' is only for dependencies within the SAME repository.`,
-			channel, payload.Repo, currentRepo, channel, payload.Worktree)
+			channel, payload.Repo, currentRepo, channel, channel, crossRepoRef(channel), crossRepoRef(channel))
+	}
+
+	ref, err := resolveMergeRef(payload, out)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Merging branch %s from %s...\n", payload.Branch, payload.Agent)
+	fmt.Fprintf(out, "Merging branch %s from %s...\n", payload.Branch, payload.Agent)
 
 	// Merge the branch - this brings in all commits including transitive dependencies
-	mergeCmd := exec.Command("git", "merge", payload.Branch, "--no-edit", "-m", fmt.Sprintf("Merge %s from %s", payload.Branch, payload.Agent))
-	mergeCmd.Stdout = os.Stdout
+	mergeMsg := fmt.Sprintf("Merge %s from %s", payload.Branch, payload.Agent)
+	if ref != payload.Branch {
+		mergeMsg = fmt.Sprintf("Merge %s@%s from %s", payload.Branch, shortRef(ref), payload.Agent)
+	}
+	mergeCmd := exec.Command("git", "merge", ref, "--no-edit", "-m", mergeMsg)
+	mergeCmd.Stdout = out
 	mergeCmd.Stderr = os.Stderr
 
 	if err := mergeCmd.Run(); err != nil {
-		return fmt.Errorf("merge failed (you may need to resolve conflicts manually): %w", err)
+		if files := conflictedFiles("."); len(files) > 0 {
+			recordEvent(Event{Kind: eventConflict, Agent: os.Getenv("AIR_AGENT_ID"), With: []string{payload.Agent}, Files: files})
+		}
+		return withCode(codeMergeConflict, fmt.Errorf("merge failed (you may need to resolve conflicts manually): %w", err))
+	}
+
+	if err := writeChannelAck(channel, payload, true); err != nil {
+		return err
 	}
 
-	fmt.Printf("Successfully merged branch %s\n", payload.Branch)
+	fmt.Fprintf(out, "Successfully merged branch %s\n", payload.Branch)
 	return nil
 }
 
+// crossRepoRef is the local ref a cross-repo dependency is fetched into
+func crossRepoRef(channel string) string {
+	return "refs/air/deps/" + channel
+}
+
+// fetchCrossRepo fetches a cross-repo signal's branch from the producer's
+// worktree and points crossRepoRef at the signaled commit. Nothing is merged.
+func fetchCrossRepo(channel string, payload *ChannelPayload, out io.Writer) error {
+	if payload.Worktree == "" || payload.SHA == "" {
+		return fmt.Errorf("channel '%s' has no worktree or SHA to fetch from", channel)
+	}
+
+	fmt.Fprintf(out, "Fetching %s from %s (repo: %s)...\n", payload.Branch, payload.Agent, payload.Repo)
+	fetchCmd := exec.Command("git", "fetch", "--no-tags", payload.Worktree, payload.Branch)
+	fetchCmd.Stdout = out
+	fetchCmd.Stderr = os.Stderr
+	if err := fetchCmd.Run(); err != nil {
+		return fmt.Errorf("failed to fetch from %s: %w", payload.Worktree, err)
+	}
+
+	ref := crossRepoRef(channel)
+	if err := exec.Command("git", "update-ref", ref, payload.SHA).Run(); err != nil {
+		return fmt.Errorf("signaled commit %s was not fetched (was %s rebased?)", shortRef(payload.SHA), payload.Branch)
+	}
+
+	if err := writeChannelAck(channel, payload, false); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Fetched %s as %s (not merged)\n", shortRef(payload.SHA), ref)
+	fmt.Fprintf(out, "Read files with: git show %s:<path>\n", ref)
+	return nil
+}
+
+// resolveMergeRef picks what to merge for a payload: the signaled SHA, or the
+// branch tip with --allow-newer. Errors if the branch no longer contains the SHA.
+func resolveMergeRef(payload *ChannelPayload, out io.Writer) (string, error) {
+	if payload.SHA == "" {
+		// Payloads without a SHA can only be merged by branch
+		return payload.Branch, nil
+	}
+
+	tip, err := exec.Command("git", "rev-parse", "--verify", "--quiet", payload.Branch+"^{commit}").Output()
+	if err != nil {
+		if mergeAllowNewer {
+			return "", fmt.Errorf("branch %s not found", payload.Branch)
+		}
+		return payload.SHA, nil
+	}
+	if strings.TrimSpace(string(tip)) == payload.SHA {
+		return payload.Branch, nil
+	}
+
+	if err := exec.Command("git", "merge-base", "--is-ancestor", payload.SHA, payload.Branch).Run(); err != nil {
+		if mergeAllowNewer {
+			return "", fmt.Errorf("branch %s no longer contains signaled commit %s (was it rebased?)", payload.Branch, shortRef(payload.SHA))
+		}
+		fmt.Fprintf(out, "Warning: branch %s no longer contains signaled commit %s; merging the signaled commit\n", payload.Branch, shortRef(payload.SHA))
+		return payload.SHA, nil
+	}
+
+	if mergeAllowNewer {
+		return payload.Branch, nil
+	}
+	fmt.Fprintf(out, "Note: branch %s has moved past signaled commit %s; merging the signaled commit (use --allow-newer for the tip)\n", payload.Branch, shortRef(payload.SHA))
+	return payload.SHA, nil
+}
+
+// shortRef abbreviates a full SHA for display
+func shortRef(ref string) string {
+	if len(ref) > 8 {
+		return ref[:8]
+	}
+	return ref
+}
+
 func runAgentDone(cmd *cobra.Command, args []string) error {
 	// Require AIR_AGENT_ID
 	agentID := os.Getenv("AIR_AGENT_ID")
@@ -299,9 +876,103 @@ func runAgentDone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("AIR_AGENT_ID environment variable is required")
 	}
 
+	if doneSummaryFile != "" {
+		if doneSummary != "" {
+			return fmt.Errorf("--summary and --summary-file cannot be used together")
+		}
+		data, err := os.ReadFile(doneSummaryFile)
+		if err != nil {
+			return fmt.Errorf("failed to read summary file: %w", err)
+		}
+		doneSummary = string(data)
+	}
+	doneSummary = strings.TrimSpace(doneSummary)
+
+	// Describe the branch relative to the base recorded by 'air run' (or 'air sync')
+	if base := baseSHA(agentID); base != "" {
+		stat, files, err := branchChanges(base)
+		if err != nil {
+			fmt.Printf("Warning: could not compute changes since base: %v\n", err)
+		} else {
+			doneChanges, doneChangedFiles = stat, files
+		}
+	}
+
+	worktree := os.Getenv("AIR_WORKTREE")
+	if worktree == "" {
+		worktree, _ = os.Getwd()
+	}
+	verified, err := runVerification(worktree)
+	if err != nil {
+		return err
+	}
+	doneVerified = verified
+
 	// Signal done/<agent-id> channel
 	channel := "done/" + agentID
 
 	// Reuse signal logic
-	return runAgentSignal(cmd, []string{channel})
+	if err := runAgentSignal(cmd, []string{channel}); err != nil {
+		return err
+	}
+	notifyHuman(fmt.Sprintf("air: %s is done", agentID))
+
+	if err := markRunAgents([]string{agentID}, agentDone, "", time.Now().UTC()); err != nil {
+		fmt.Printf("Warning: failed to update run.json: %v\n", err)
+	}
+	usage := transcriptUsage(worktree)
+	recordEvent(Event{Kind: eventDone, Agent: agentID, Repo: os.Getenv("AIR_REPO"), Tokens: &usage})
+	return nil
+}
+
+// readDoneSummaries returns the completion summary of each done agent that left one
+func readDoneSummaries() map[string]string {
+	summaries := make(map[string]string)
+	doneDir := filepath.Join(getChannelsDir(), "done")
+	entries, err := os.ReadDir(doneDir)
+	if err != nil {
+		return summaries
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(doneDir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var payload ChannelPayload
+		if err := json.Unmarshal(data, &payload); err != nil || payload.Summary == "" {
+			continue
+		}
+		summaries[strings.TrimSuffix(e.Name(), ".json")] = payload.Summary
+	}
+	return summaries
+}
+
+// branchChanges computes the diffstat and changed files of HEAD against base
+func branchChanges(base string) (*Diffstat, []string, error) {
+	out, err := exec.Command("git", "diff", "--numstat", base, "HEAD").Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("git diff failed: %w", err)
+	}
+
+	stat := &Diffstat{Base: base}
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		// Binary files report "-" for both counts
+		if n, err := strconv.Atoi(fields[0]); err == nil {
+			stat.Insertions += n
+		}
+		if n, err := strconv.Atoi(fields[1]); err == nil {
+			stat.Deletions += n
+		}
+		files = append(files, fields[2])
+	}
+	stat.Files = len(files)
+	return stat, files, nil
 }
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var agentEnvCmd = &cobra.Command{
+	Use:   "env [plan]",
+	Short: "Show the environment an agent runs with, and what's wrong with it",
+	Long: `Prints the AIR_* variables (and extras such as SSH_AUTH_SOCK and the agent's
+git identity) a plan's agent runs with, resolved from the project's plans,
+run.json, and recorded base the same way 'air run' resolves them, not read back
+from launch.sh.
+
+Flags values that point nowhere: a worktree, project, or state directory that
+doesn't exist, or a base commit the repo doesn't have. Run inside an agent
+(no plan argument, AIR_AGENT_ID set), it also compares the resolved values with
+the agent's actual environment, since a mismatch there is what makes signals
+land where waits never look. Exits non-zero if anything is flagged.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAgentEnv,
+}
+
+func init() {
+	agentCmd.AddCommand(agentEnvCmd)
+	supportsOutput(agentEnvCmd)
+}
+
+// agentEnvReport is what 'air agent env' reports for a plan
+type agentEnvReport struct {
+	Plan     string        `json:"plan"`
+	InAgent  bool          `json:"in_agent"` // compared with this process's environment
+	Env      []agentEnvVar `json:"env"`
+	Problems []string      `json:"problems"`
+}
+
+// agentEnvVar is one resolved variable and what's wrong with it, if anything
+type agentEnvVar struct {
+	envVar
+	Actual  *string `json:"actual,omitempty"` // this process's value, when it differs (in an agent)
+	Problem string  `json:"problem,omitempty"`
+}
+
+// agentEnvPaths are the variables naming a directory that exists once the
+// agent is prepared (artifacts/ only appears with the first publish)
+var agentEnvPaths = map[string]bool{
+	"AIR_WORKTREE": true, "AIR_PROJECT_ROOT": true, "AIR_WORKSPACE_ROOT": true,
+	"AIR_CHANNELS_DIR": true, "AIR_AGENT_DIR": true,
+}
+
+func runAgentEnv(cmd *cobra.Command, args []string) error {
+	inAgent := len(args) == 0
+	name := os.Getenv("AIR_AGENT_ID")
+	if !inAgent {
+		name = args[0]
+		inAgent = name == os.Getenv("AIR_AGENT_ID")
+	} else if name == "" {
+		return withCode(codeUsage, fmt.Errorf("name a plan, or run this inside an agent (AIR_AGENT_ID is not set)"))
+	}
+
+	// Resolve from state alone: with AIR_* set, air's path helpers would
+	// return the agent's own values back
+	actual := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if k, v, _ := strings.Cut(kv, "="); strings.HasPrefix(k, "AIR_") {
+			actual[k] = v
+			os.Unsetenv(k)
+		}
+	}
+
+	// An agent's working directory is its worktree, outside the project,
+	// whichever plan it asks about
+	root := actual["AIR_WORKSPACE_ROOT"]
+	if root == "" {
+		root = actual["AIR_PROJECT_ROOT"]
+	}
+	if root == "" && len(args) == 0 {
+		return fmt.Errorf("can't find the project: neither AIR_WORKSPACE_ROOT nor AIR_PROJECT_ROOT is set")
+	}
+	if root != "" {
+		if err := os.Chdir(root); err != nil {
+			return fmt.Errorf("can't find the project: %w", err)
+		}
+	}
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	if _, err := os.Stat(planPath(name)); err != nil {
+		return errPlanNotFound(name)
+	}
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+	plans, _ := ValidatePlansWithMode(info)
+	var pd PlanDependencies
+	for _, p := range plans {
+		if p.Name == name {
+			pd = p
+		}
+	}
+	if pd.Name == "" {
+		return fmt.Errorf("failed to read plan %s (see 'air plan validate')", name)
+	}
+
+	// The run's --channel-ttl is part of the environment it launched agents with
+	if record, _ := readRunRecord(); record != nil && record.Flags.ChannelTTL != "" {
+		if d, err := time.ParseDuration(record.Flags.ChannelTTL); err == nil {
+			runChannelTTL = d
+		}
+	}
+	base := readAgentBase(name)
+	_, repoPath, _ := agentPaths(info, pd)
+
+	report := &agentEnvReport{Plan: name, InAgent: inAgent, Env: []agentEnvVar{}, Problems: []string{}}
+	resolved := make(map[string]bool)
+	for _, v := range agentEnv(info, pd, base) {
+		resolved[v.Name] = true
+		ev := agentEnvVar{envVar: v}
+		switch {
+		case v.Value == "":
+			ev.Problem = "empty"
+		case agentEnvPaths[v.Name]:
+			if _, err := os.Stat(v.Value); err != nil {
+				ev.Problem = "doesn't exist"
+			}
+		case v.Name == "AIR_BASE_SHA":
+			if exec.Command("git", "-C", repoPath, "cat-file", "-e", v.Value+"^{commit}").Run() != nil {
+				ev.Problem = "commit not found in " + repoPath
+			}
+		}
+		if inAgent {
+			if value, ok := actual[v.Name]; !ok && strings.HasPrefix(v.Name, "AIR_") {
+				ev.Problem = joinProblems(ev.Problem, "not set in this agent")
+			} else if ok && value != v.Value {
+				ev.Actual = &value
+				ev.Problem = joinProblems(ev.Problem, "this agent has "+value)
+			}
+		}
+		if ev.Problem != "" {
+			report.Problems = append(report.Problems, v.Name+": "+ev.Problem)
+		}
+		report.Env = append(report.Env, ev)
+	}
+	if base == nil {
+		report.Problems = append(report.Problems, "no recorded base (agents/"+name+"/base.json): AIR_BASE_SHA isn't set, so diffs are against the base branch's current tip")
+	}
+	if inAgent {
+		// Variables air no longer sets are left over from an older launch
+		var extra []string
+		for k := range actual {
+			if !resolved[k] {
+				extra = append(extra, k)
+			}
+		}
+		sort.Strings(extra)
+		for _, k := range extra {
+			report.Problems = append(report.Problems, k+": set in this agent, but air wouldn't set it")
+		}
+	}
+
+	if err := render(report, func() { printAgentEnv(report) }); err != nil {
+		return err
+	}
+	if len(report.Problems) > 0 {
+		cmd.SilenceUsage = true
+		return withCode(codeValidationFailed, fmt.Errorf("found problems with %s's environment", name))
+	}
+	return nil
+}
+
+// joinProblems adds problem b to a
+func joinProblems(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "; " + b
+}
+
+// printAgentEnv prints an environment report for humans
+func printAgentEnv(r *agentEnvReport) {
+	if r.InAgent {
+		fmt.Printf("Environment of %s (resolved from air's state, checked against this agent)\n\n", r.Plan)
+	} else {
+		fmt.Printf("Environment of %s (resolved from air's state)\n\n", r.Plan)
+	}
+	for _, v := range r.Env {
+		fmt.Printf("  %s=%s\n", v.Name, v.Value)
+		if v.Problem != "" {
+			fmt.Printf("    ✗ %s\n", v.Problem)
+		}
+	}
+	if len(r.Problems) == 0 {
+		fmt.Println("\n✓ No problems found")
+		return
+	}
+	fmt.Println("\nProblems")
+	for _, p := range r.Problems {
+		fmt.Printf("  ✗ %s\n", p)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The section of context.md written by 'air init --analyze' sits between
+// these markers, so rerunning it replaces the section instead of appending
+const (
+	analyzeBegin = "<!-- air analyze: begin -->"
+	analyzeEnd   = "<!-- air analyze: end -->"
+)
+
+// languageExtensions maps source file extensions to language names
+var languageExtensions = map[string]string{
+	".go": "Go", ".ts": "TypeScript", ".tsx": "TypeScript", ".js": "JavaScript", ".jsx": "JavaScript",
+	".mjs": "JavaScript", ".py": "Python", ".rs": "Rust", ".java": "Java", ".kt": "Kotlin",
+	".rb": "Ruby", ".php": "PHP", ".cs": "C#", ".c": "C", ".h": "C", ".cc": "C++", ".cpp": "C++",
+	".hpp": "C++", ".swift": "Swift", ".scala": "Scala", ".ex": "Elixir", ".exs": "Elixir",
+	".sh": "Shell", ".proto": "Protocol Buffers", ".sql": "SQL",
+}
+
+// makeTargetRegex matches the common targets of a Makefile
+var makeTargetRegex = regexp.MustCompile(`(?m)^(build|test|lint|check|fmt|generate):`)
+
+// projectFacts is what 'air init --analyze' learns about one repo or component
+type projectFacts struct {
+	Label     string
+	Languages []string // "Go (42 files)", most files first
+	Build     []string
+	Test      []string
+	Lint      []string
+	Layout    []string // top-level directories with file counts
+}
+
+// analyzeProject scans the project's repos (or components) and returns the
+// context.md section describing how to build and test them
+func analyzeProject(info *WorkspaceInfo) string {
+	type target struct{ label, repo, dir string }
+	var targets []target
+	switch info.Mode {
+	case ModeWorkspace:
+		for _, repo := range info.Repos {
+			targets = append(targets, target{repo, info.repoDir(repo), ""})
+		}
+	case ModeMonorepo:
+		for _, c := range info.Components {
+			targets = append(targets, target{c, info.Root, c})
+		}
+	default:
+		targets = append(targets, target{info.Name, info.Root, ""})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(analyzeBegin + "\n")
+	sb.WriteString("## Project Facts\n\n")
+	sb.WriteString("Found by scanning the code ('air init --analyze' refreshes this section). Use these commands to build and test instead of working them out.\n")
+	for _, t := range targets {
+		f := scanProject(t.repo, t.dir)
+		f.Label = t.label
+		sb.WriteString(formatProjectFacts(f, len(targets) > 1))
+	}
+	sb.WriteString(analyzeEnd + "\n")
+	return sb.String()
+}
+
+// scanProject gathers facts about dir (relative, "" for the whole repo) in repo
+func scanProject(repo, dir string) projectFacts {
+	var f projectFacts
+	args := []string{"-C", repo, "ls-files"}
+	if dir != "" {
+		args = append(args, "--", dir)
+	}
+	out, _ := exec.Command("git", args...).Output()
+
+	languages := make(map[string]int)
+	dirs := make(map[string]int)
+	for _, file := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		rel := strings.TrimPrefix(file, strings.TrimSuffix(dir, "/")+"/")
+		if file == "" {
+			continue
+		}
+		if lang, ok := languageExtensions[strings.ToLower(filepath.Ext(file))]; ok {
+			languages[lang]++
+		}
+		if top, _, nested := strings.Cut(rel, "/"); nested {
+			dirs[top]++
+		}
+	}
+	f.Languages = rankCounts(languages, 4, "files")
+	f.Layout = rankCounts(dirs, 10, "files")
+	for i := range f.Layout {
+		f.Layout[i] = strings.Replace(f.Layout[i], " (", "/ (", 1)
+	}
+	f.Build, f.Test, f.Lint = detectCommands(filepath.Join(repo, dir))
+	return f
+}
+
+// rankCounts formats the top n of counts as "name (count unit)", largest first
+func rankCounts(counts map[string]int, n int, unit string) []string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+	ranked := make([]string, len(names))
+	for i, name := range names {
+		u := unit
+		if counts[name] == 1 {
+			u = strings.TrimSuffix(unit, "s")
+		}
+		ranked[i] = fmt.Sprintf("%s (%d %s)", name, counts[name], u)
+	}
+	return ranked
+}
+
+// detectCommands works out the build, test, and lint commands for the project
+// in dir from its build files. Makefile targets come first: a repo with one
+// usually wraps its toolchain's commands in it.
+func detectCommands(dir string) (build, test, lint []string) {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "Makefile")); err == nil {
+		for _, m := range makeTargetRegex.FindAllStringSubmatch(string(data), -1) {
+			switch m[1] {
+			case "build", "generate":
+				build = append(build, "make "+m[1])
+			case "test", "check":
+				test = append(test, "make "+m[1])
+			default:
+				lint = append(lint, "make "+m[1])
+			}
+		}
+	}
+	if exists("go.mod") {
+		build = append(build, "go build ./...")
+		test = append(test, "go test ./...")
+		lint = append(lint, "go vet ./...")
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var pkg struct {
+			Scripts map[string]string `json:"scripts"`
+		}
+		json.Unmarshal(data, &pkg)
+		runner := "npm"
+		switch {
+		case exists("pnpm-lock.yaml"):
+			runner = "pnpm"
+		case exists("yarn.lock"):
+			runner = "yarn"
+		case exists("bun.lockb"):
+			runner = "bun"
+		}
+		if _, ok := pkg.Scripts["build"]; ok {
+			build = append(build, runner+" run build")
+		}
+		if _, ok := pkg.Scripts["test"]; ok {
+			test = append(test, runner+" test")
+		}
+		if _, ok := pkg.Scripts["lint"]; ok {
+			lint = append(lint, runner+" run lint")
+		}
+	}
+	if exists("Cargo.toml") {
+		build = append(build, "cargo build")
+		test = append(test, "cargo test")
+		lint = append(lint, "cargo clippy")
+	}
+	if exists("pyproject.toml") || exists("requirements.txt") || exists("setup.py") {
+		test = append(test, "pytest")
+	}
+	if exists("pom.xml") {
+		build = append(build, "mvn -q package -DskipTests")
+		test = append(test, "mvn -q test")
+	}
+	if exists("build.gradle") || exists("build.gradle.kts") {
+		gradle := "gradle"
+		if exists("gradlew") {
+			gradle = "./gradlew"
+		}
+		build = append(build, gradle+" build -x test")
+		test = append(test, gradle+" test")
+	}
+	return build, test, lint
+}
+
+// formatProjectFacts renders f as markdown, under its own heading if titled
+func formatProjectFacts(f projectFacts, titled bool) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	if titled {
+		fmt.Fprintf(&sb, "### %s\n\n", f.Label)
+	}
+	line := func(name string, items []string, code bool) {
+		if len(items) == 0 {
+			return
+		}
+		if code {
+			items = append([]string(nil), items...)
+			for i := range items {
+				items[i] = "`" + items[i] + "`"
+			}
+		}
+		fmt.Fprintf(&sb, "- **%s:** %s\n", name, strings.Join(items, ", "))
+	}
+	line("Languages", f.Languages, false)
+	line("Build", f.Build, true)
+	line("Test", f.Test, true)
+	line("Lint", f.Lint, true)
+	line("Layout", f.Layout, false)
+	if len(f.Build)+len(f.Test) == 0 {
+		sb.WriteString("- No build or test commands found: check the README before assuming any.\n")
+	}
+	return sb.String()
+}
+
+// withAnalysis returns context with its analyzed section replaced by section,
+// or section appended if it has none
+func withAnalysis(context, section string) string {
+	start := strings.Index(context, analyzeBegin)
+	end := strings.Index(context, analyzeEnd)
+	if start >= 0 && end > start {
+		rest := strings.TrimPrefix(context[end+len(analyzeEnd):], "\n")
+		return context[:start] + section + rest
+	}
+	return strings.TrimRight(context, "\n") + "\n\n" + section
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var agentPublishCmd = &cobra.Command{
+	Use:   "publish <name> <path>",
+	Short: "Publish a file or directory for other agents",
+	Long: `Copies a file or directory into the run's shared artifacts directory under
+<name>, so other agents can fetch it without reaching into this worktree.
+Publishing an existing name replaces it.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAgentPublish,
+}
+
+var agentFetchCmd = &cobra.Command{
+	Use:   "fetch <name> [dest]",
+	Short: "Fetch an artifact published by another agent",
+	Long: `Copies a published artifact into the current directory, or to dest if given.
+
+With --wait, blocks until the artifact is published.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runAgentFetch,
+}
+
+var fetchWait bool
+
+func init() {
+	agentCmd.AddCommand(agentPublishCmd)
+	agentCmd.AddCommand(agentFetchCmd)
+
+	agentFetchCmd.Flags().BoolVar(&fetchWait, "wait", false, "Block until the artifact is published")
+}
+
+// artifactMetaFile holds an artifact's metadata alongside its content
+const artifactMetaFile = "artifact.json"
+
+// ArtifactMeta describes a published artifact
+type ArtifactMeta struct {
+	Name      string    `json:"name"`
+	File      string    `json:"file"` // Basename of the published file or directory
+	Agent     string    `json:"agent"`
+	SHA       string    `json:"sha,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// validateArtifactName rejects names that would escape the artifacts directory
+func validateArtifactName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid artifact name '%s'", name)
+	}
+	return nil
+}
+
+// readArtifactMeta reads a published artifact's metadata
+func readArtifactMeta(name string) (*ArtifactMeta, error) {
+	data, err := os.ReadFile(filepath.Join(getArtifactsDir(), name, artifactMetaFile))
+	if err != nil {
+		return nil, err
+	}
+	var meta ArtifactMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse artifact %s: %w", name, err)
+	}
+	return &meta, nil
+}
+
+func runAgentPublish(cmd *cobra.Command, args []string) error {
+	name, src := args[0], args[1]
+	if err := validateArtifactName(name); err != nil {
+		return err
+	}
+
+	agentID := os.Getenv("AIR_AGENT_ID")
+	if agentID == "" {
+		return fmt.Errorf("AIR_AGENT_ID environment variable is required")
+	}
+
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("cannot publish %s: %w", args[1], err)
+	}
+
+	artifactsDir := getArtifactsDir()
+	if err := os.MkdirAll(artifactsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+
+	// Stage into a hidden temp dir, then swap in so fetchers never see a partial copy
+	staging, err := os.MkdirTemp(artifactsDir, "."+name+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	file := filepath.Base(src)
+	if err := copyPath(src, filepath.Join(staging, file)); err != nil {
+		return fmt.Errorf("failed to copy artifact: %w", err)
+	}
+
+	sha, _ := getCurrentSHA()
+	meta := ArtifactMeta{Name: name, File: file, Agent: agentID, SHA: sha, Timestamp: time.Now().UTC()}
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal artifact metadata: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(staging, artifactMetaFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write artifact metadata: %w", err)
+	}
+
+	dest := filepath.Join(artifactsDir, name)
+	if err := os.RemoveAll(dest); err != nil {
+		return fmt.Errorf("failed to replace artifact: %w", err)
+	}
+	if err := os.Rename(staging, dest); err != nil {
+		return fmt.Errorf("failed to publish artifact: %w", err)
+	}
+
+	fmt.Printf("Published artifact '%s' (%s)\n", name, file)
+	return nil
+}
+
+func runAgentFetch(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := validateArtifactName(name); err != nil {
+		return err
+	}
+
+	if fetchWait {
+		fmt.Printf("Waiting for artifact '%s'...\n", name)
+	}
+
+	interval := pollInterval()
+	var meta *ArtifactMeta
+	for {
+		m, err := readArtifactMeta(name)
+		if err == nil {
+			meta = m
+			break
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		if !fetchWait {
+			return withCode(codeDependencyMissing, fmt.Errorf("artifact '%s' has not been published yet", name))
+		}
+		time.Sleep(interval)
+	}
+
+	dest := meta.File
+	if len(args) == 2 {
+		dest = args[1]
+	}
+	if err := copyPath(filepath.Join(getArtifactsDir(), name, meta.File), dest); err != nil {
+		return fmt.Errorf("failed to fetch artifact: %w", err)
+	}
+
+	fmt.Printf("Fetched artifact '%s' from %s -> %s\n", name, meta.Agent, dest)
+	return nil
+}
+
+// copyPath copies a file or directory tree from src to dst, preserving file modes
+func copyPath(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		}
+		if !info.Mode().IsRegular() {
+			// Skip symlinks, sockets, etc.
+			return nil
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+// copyFile copies a single regular file, creating parent directories as needed
+func copyFile(src, dst string, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// propagateConsumed copies the paths this agent's plan consumes (AIR_CONSUMES,
+// from **Consumes:**) out of a cross-repo signal's commit into the worktree.
+// Paths are read at the signaled SHA, so later edits by the producer don't leak in.
+func propagateConsumed(payload *ChannelPayload, out io.Writer) error {
+	if payload.Repo == "" || payload.Repo == os.Getenv("AIR_REPO") {
+		return nil
+	}
+	for _, spec := range parseConsumeSpecs(os.Getenv("AIR_CONSUMES")) {
+		if spec.Repo != payload.Repo {
+			continue
+		}
+		n, err := extractCommitPath(payload.Worktree, payload.SHA, spec.Path, spec.Dest)
+		if err != nil {
+			return fmt.Errorf("failed to copy %s: %w", spec, err)
+		}
+		fmt.Fprintf(out, "Copied %s from %s (%d files) - review and commit it with your changes\n", spec, shortRef(payload.SHA), n)
+	}
+	return nil
+}
+
+// extractCommitPath writes path as of commit sha in repoDir to dest, replacing
+// whatever is there. Returns the number of files written.
+func extractCommitPath(repoDir, sha, path, dest string) (int, error) {
+	var stderr bytes.Buffer
+	archive := exec.Command("git", "-C", repoDir, "archive", "--format=tar", sha, "--", path)
+	archive.Stderr = &stderr
+	data, err := archive.Output()
+	if err != nil {
+		return 0, fmt.Errorf("git archive failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	if err := os.RemoveAll(dest); err != nil {
+		return 0, err
+	}
+
+	prefix := strings.TrimSuffix(filepath.ToSlash(path), "/")
+	files := 0
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return files, err
+		}
+		rel := strings.TrimPrefix(strings.TrimSuffix(hdr.Name, "/"), prefix)
+		target := filepath.Join(dest, filepath.FromSlash(rel))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return files, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return files, err
+			}
+			f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fs.FileMode(hdr.Mode).Perm())
+			if err != nil {
+				return files, err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return files, err
+			}
+			if err := f.Close(); err != nil {
+				return files, err
+			}
+			files++
+		}
+	}
+	return files, nil
+}
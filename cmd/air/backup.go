@@ -0,0 +1,270 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup [file]",
+	Short: "Archive the project's plans, context, channels, and run history",
+	Long: `Writes the project's state under ~/.air/<project>/ to a gzipped tar archive:
+plans (including archived ones), context, channels, agent data and failure
+snapshots, run.json, the event log, and pipeline state. Worktrees and published
+artifacts are left out; the branches live in the repository, and artifacts can
+be rebuilt. So are run.lock, heartbeats, and lock files, which name processes
+running on this machine.
+
+The archive defaults to air-<project>-<timestamp>.tar.gz in the current
+directory. Restore it with 'air restore'.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBackup,
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore project state from an 'air backup' archive",
+	Long: `Unpacks an 'air backup' archive into this project's ~/.air/<project>/,
+which needn't exist; restoring is how to recover from deleting ~/.air.
+
+If the project already has state, --force is required, and files in the
+archive overwrite their current versions. Files the archive doesn't contain
+are left alone. Worktrees aren't restored: recreate them with 'air run'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestore,
+}
+
+var restoreForce bool
+
+func init() {
+	restoreCmd.Flags().BoolVar(&restoreForce, "force", false, "Overwrite existing project state")
+}
+
+// backupManifestName is the archive entry describing the backup itself
+const backupManifestName = "backup.json"
+
+// backupExcluded are the top-level entries of a project directory a backup skips
+var backupExcluded = []string{"worktrees", "artifacts", runLockFile}
+
+// perProcessFile reports whether a file belongs to processes running on this
+// machine, naming their PIDs or half-written by them: heartbeats, lock files
+// like run.lock, and temp files. Restored elsewhere or later, a PID could match
+// an unrelated process, so backups skip them.
+func perProcessFile(name string) bool {
+	return name == "heartbeat.json" || strings.HasSuffix(name, ".lock") || strings.HasSuffix(name, ".break") || strings.HasSuffix(name, ".tmp")
+}
+
+// backupManifest describes where and when a backup was taken
+type backupManifest struct {
+	Project string    `json:"project"`
+	Created time.Time `json:"created"`
+	Version string    `json:"version"`
+
+	// Paths maps the placeholders of a portable export to the absolute paths
+	// they replaced on the exporting machine
+	Paths map[string]string `json:"paths,omitempty"`
+}
+
+func runBackup(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	airDir := mustGetAirDir()
+	project := filepath.Base(airDir)
+	now := time.Now()
+
+	path := fmt.Sprintf("air-%s-%s.tar.gz", project, now.Format("20060102-150405"))
+	if len(args) > 0 {
+		path = args[0]
+	}
+	files, err := writeBackup(path, airDir, backupManifest{Project: project, Created: now.UTC(), Version: version}, nil)
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	fmt.Printf("Backed up %d files from %s to %s\n", files, airDir, path)
+	return nil
+}
+
+// writeBackup archives airDir into path, except backupExcluded, returning how
+// many files it wrote. rewrite, if set, transforms each file's contents.
+func writeBackup(path, airDir string, manifest backupManifest, rewrite func([]byte) []byte) (int, error) {
+	out, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create backup: %w", err)
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: backupManifestName, Mode: 0644, Size: int64(len(data)), ModTime: manifest.Created}); err != nil {
+		return 0, err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return 0, err
+	}
+
+	files := 0
+	err = filepath.WalkDir(airDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(airDir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		if d.IsDir() && contains(backupExcluded, rel) {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && (contains(backupExcluded, rel) || perProcessFile(d.Name())) {
+			return nil
+		}
+		// Only directories and regular files: sockets, fifos, and links aren't state
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			hdr.Name += "/"
+			return tw.WriteHeader(hdr)
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if rewrite != nil {
+			data = rewrite(data)
+		}
+		hdr.Size = int64(len(data))
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+		files++
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to back up %s: %w", airDir, err)
+	}
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+	return files, out.Close()
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	airDir, err := getAirDir()
+	if err != nil {
+		return fmt.Errorf("failed to determine project: %w", err)
+	}
+	if entries, _ := os.ReadDir(airDir); len(entries) > 0 && !restoreForce {
+		return withCode(codeUsage, fmt.Errorf("%s already has project state; rerun with --force to overwrite it with the backup", airDir))
+	}
+
+	manifest, files, err := readBackup(args[0], airDir, nil)
+	if err != nil {
+		return err
+	}
+	from := ""
+	if manifest != nil {
+		from = fmt.Sprintf(" (backup of %s from %s)", manifest.Project, manifest.Created.Local().Format(time.RFC822))
+	}
+	fmt.Printf("Restored %d files to %s%s\n", files, airDir, from)
+	fmt.Println("Worktrees aren't part of a backup; 'air run' recreates them from the branches.")
+	return nil
+}
+
+// readBackup unpacks the archive at path into airDir, returning its manifest
+// (nil for an archive without one) and how many files it restored. rewrite,
+// if set, transforms each file's contents.
+func readBackup(path, airDir string, rewrite func([]byte) []byte) (*backupManifest, int, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer in.Close()
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s is not an air backup: %w", path, err)
+	}
+	tr := tar.NewReader(gz)
+
+	var manifest *backupManifest
+	files := 0
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, files, fmt.Errorf("failed to read backup: %w", err)
+		}
+		if hdr.Name == backupManifestName {
+			manifest = &backupManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, files, fmt.Errorf("invalid backup manifest: %w", err)
+			}
+			continue
+		}
+
+		// Refuse entries that would land outside the project directory
+		rel := filepath.FromSlash(strings.TrimSuffix(hdr.Name, "/"))
+		if !filepath.IsLocal(rel) {
+			return manifest, files, fmt.Errorf("backup entry %q escapes the project directory", hdr.Name)
+		}
+		target := filepath.Join(airDir, rel)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return manifest, files, err
+			}
+		case tar.TypeReg:
+			// Archives from before these were skipped may still hold them
+			if perProcessFile(filepath.Base(rel)) {
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return manifest, files, err
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return manifest, files, fmt.Errorf("failed to restore %s: %w", hdr.Name, err)
+			}
+			if rewrite != nil {
+				data = rewrite(data)
+			}
+			if err := os.WriteFile(target, data, fs.FileMode(hdr.Mode).Perm()); err != nil {
+				return manifest, files, err
+			}
+			files++
+		}
+	}
+	return manifest, files, nil
+}
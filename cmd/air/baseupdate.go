@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Base-update policies (air run --base-updates): what to do when an agent's
+// base branch gains commits during a run
+const (
+	baseUpdatesOff  = "off"  // ignore them
+	baseUpdatesWarn = "warn" // flag agents that are behind in 'air status'
+	baseUpdatesAuto = "auto" // also 'air sync' idle agents from the dashboard
+)
+
+// syncConflictFile records the base commit an automatic sync conflicted with,
+// so the dashboard doesn't retry it every refresh
+const syncConflictFile = "sync-conflict"
+
+// readBaseUpdatePolicy returns the run's --base-updates, defaulting to warn
+func readBaseUpdatePolicy() string {
+	record, _ := readRunRecord()
+	if record == nil || record.Flags.BaseUpdates == "" {
+		return baseUpdatesWarn
+	}
+	return record.Flags.BaseUpdates
+}
+
+// validBaseUpdatePolicy reports whether p is a --base-updates value
+func validBaseUpdatePolicy(p string) bool {
+	return p == baseUpdatesOff || p == baseUpdatesWarn || p == baseUpdatesAuto
+}
+
+// autoSyncIdle syncs the agents in report that are behind their base and idle:
+// waiting on a channel or at a prompt. syncAgent skips any with uncommitted
+// changes, and a base commit that conflicted once isn't tried again.
+func autoSyncIdle(report *statusReport, agents []worktreeInfo) []syncResult {
+	var results []syncResult
+	for _, status := range report.Agents {
+		if status.BaseUpdates == 0 || status.State != "running" {
+			continue
+		}
+		if state := agentBarState(status.Name); state != "waiting" && state != "ask" {
+			continue
+		}
+		for _, agent := range agents {
+			if agent.name != status.Name {
+				continue
+			}
+			base := readAgentBase(agent.name)
+			if base == nil {
+				continue
+			}
+			out, _ := exec.Command("git", "-C", agent.wtPath, "rev-parse", base.Branch).Output()
+			tip := strings.TrimSpace(string(out))
+			marker := filepath.Join(getAgentDir(agent.name), syncConflictFile)
+			if data, err := os.ReadFile(marker); err == nil && strings.TrimSpace(string(data)) == tip {
+				continue
+			}
+			r := syncAgent(agent)
+			r.Repo = status.Repo
+			if r.Outcome == "conflict" {
+				os.WriteFile(marker, []byte(tip+"\n"), 0644)
+				notifyHuman(fmt.Sprintf("air: auto-sync of %s with %s conflicts in %s", agent.name, base.Branch, strings.Join(r.Files, ", ")))
+			}
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// formatBaseUpdates describes the commits an agent's base branch gained since
+// it started, and what will happen about them under policy
+func formatBaseUpdates(agent agentStatus, policy string) string {
+	branch := "its base branch"
+	if base := readAgentBase(agent.Name); base != nil && base.Branch != "" {
+		branch = base.Branch
+	}
+	moved := fmt.Sprintf("%s has %d new commits", branch, agent.BaseUpdates)
+	if policy == baseUpdatesAuto {
+		return moved + "; syncs automatically once the agent is idle"
+	}
+	return fmt.Sprintf("%s; 'air sync %s' to merge them", moved, agent.Name)
+}
+
+// staleness describes how far an agent's branch has fallen behind its base
+// branch once that passes maxCommits commits or maxDays days (the age of the
+// oldest base commit it lacks); "" while it's fresh. A zero limit is off.
+// Branches already merged into their base aren't stale, whatever they lack.
+func staleness(wtPath string, base *agentBase, done bool, maxCommits, maxDays int, now time.Time) string {
+	if base == nil || base.Branch == "" || base.Branch == "HEAD" {
+		return ""
+	}
+	ahead, behind, ok := aheadBehind(wtPath, base.Branch)
+	if !ok || behind == 0 || (ahead == 0 && done) {
+		return ""
+	}
+	days := 0
+	out, _ := exec.Command("git", "-C", wtPath, "log", "--reverse", "--format=%ct", "HEAD.."+base.Branch).Output()
+	first, _, _ := strings.Cut(string(out), "\n")
+	if secs, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64); err == nil {
+		days = int(now.Sub(time.Unix(secs, 0)).Hours() / 24)
+	}
+	if (maxCommits <= 0 || behind < maxCommits) && (maxDays <= 0 || days < maxDays) {
+		return ""
+	}
+	return fmt.Sprintf("%d commits behind %s, the oldest from %d days ago", behind, base.Branch, days)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var agentBriefCmd = &cobra.Command{
+	Use:   "brief <plan>",
+	Short: "Regenerate an agent's context and assignment",
+	Long: `Rebuilds agents/<plan>/context and agents/<plan>/assignment from the current
+plan and context.md, the same way 'air run' writes them. Use it after editing a
+plan or the shared context mid-run: the agent's launch script reads these files
+each time Claude starts, so a relaunched agent gets the new briefing.
+
+With --deliver, also tells a running agent (in the project's tmux session) that its
+briefing changed and where to re-read it, so it can pick up the edit without a
+restart.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentBrief,
+}
+
+var briefDeliver bool
+
+func init() {
+	agentCmd.AddCommand(agentBriefCmd)
+	agentBriefCmd.Flags().BoolVar(&briefDeliver, "deliver", false, "Tell the running agent to re-read its briefing")
+}
+
+func runAgentBrief(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	if _, err := os.Stat(planPath(name)); err != nil {
+		return errPlanNotFound(name)
+	}
+
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+
+	// The coordination steps depend on the whole plan set, so it must still be valid
+	plans, validationErrs := ValidatePlansWithMode(info)
+	if len(validationErrs) > 0 {
+		fmt.Println("Dependency validation failed:")
+		for _, err := range validationErrs {
+			fmt.Printf("  ✗ %s\n", err)
+		}
+		return withCode(codeValidationFailed, fmt.Errorf("invalid dependency graph"))
+	}
+	var pd PlanDependencies
+	for _, p := range plans {
+		if p.Name == name {
+			pd = p
+		}
+	}
+
+	contextContent, err := os.ReadFile(getContextPath())
+	if err != nil {
+		return fmt.Errorf("failed to read context: %w", err)
+	}
+	if err := writeAgentBriefing(info, pd, plans, contextContent); err != nil {
+		return err
+	}
+	agentDir := filepath.Join(getAgentsDir(), name)
+	fmt.Printf("Regenerated briefing for %s in %s\n", name, agentDir)
+
+	if !briefDeliver {
+		fmt.Println("It takes effect the next time the agent starts (or pass --deliver to notify it now).")
+		return nil
+	}
+	session := runSession()
+	window := findAgentWindow(session, name)
+	if window == "" {
+		return fmt.Errorf("no window for agent '%s' in tmux session '%s'; the new briefing takes effect when it next starts", name, session)
+	}
+	message := fmt.Sprintf("Your briefing was updated after a plan change. Re-read %s and %s, then continue from where you are, following the updated Coordination steps.",
+		filepath.Join(agentDir, "context"), filepath.Join(agentDir, "assignment"))
+	// -l sends the text literally; Enter submits it as the agent's next prompt
+	if err := exec.Command("tmux", "send-keys", "-t", window, "-l", message).Run(); err != nil {
+		return fmt.Errorf("failed to deliver briefing: %w", err)
+	}
+	exec.Command("tmux", "send-keys", "-t", window, "Enter").Run()
+	fmt.Printf("Delivered to %s's window\n", name)
+	return nil
+}
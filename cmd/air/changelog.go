@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Summarize what merged agent branches shipped",
+	Long: `Assembles a markdown changelog entry from the agents whose branches have been
+merged into their base branch: each plan's objective and the summary its agent
+left with 'air agent done --summary', grouped by repository in workspace mode.
+Agents that aren't done, and branches not merged yet, are left out.
+
+Run it after 'air integrate' and before 'air clean' (which removes the plans
+and done channels it reads), e.g. 'air changelog >> CHANGELOG.md'.`,
+	Args: cobra.NoArgs,
+	RunE: runChangelog,
+}
+
+var changelogGroup string
+var changelogTitle string
+
+func init() {
+	supportsOutput(changelogCmd)
+	changelogCmd.Flags().StringVar(&changelogGroup, "group", "", "Only include plans in this group")
+	changelogCmd.Flags().StringVar(&changelogTitle, "title", "What the agents shipped", "Heading of the entry")
+}
+
+// changelogEntry is one merged plan in 'air changelog'
+type changelogEntry struct {
+	Plan      string    `json:"plan"`
+	Group     string    `json:"group,omitempty"`
+	Repo      string    `json:"repo,omitempty"`
+	Objective string    `json:"objective,omitempty"`
+	Summary   string    `json:"summary,omitempty"`
+	Diffstat  *Diffstat `json:"diffstat,omitempty"`
+}
+
+// changelogReport is the output of 'air changelog'
+type changelogReport struct {
+	Title   string           `json:"title"`
+	Entries []changelogEntry `json:"entries"`
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+	plans, err := loadAllPlanDependencies()
+	if err != nil {
+		return err
+	}
+
+	report := &changelogReport{Title: changelogTitle, Entries: []changelogEntry{}}
+	for _, pd := range plans {
+		if changelogGroup != "" && pd.Group != changelogGroup {
+			continue
+		}
+		repoName, repoPath, _ := agentPaths(info, pd)
+		done, err := readChannel("done/" + pd.Name)
+		if err != nil || !branchMerged(repoPath, "air/"+pd.Name, info.baseBranch(repoName)) {
+			continue
+		}
+		entry := changelogEntry{Plan: pd.Name, Group: pd.Group, Repo: repoName, Summary: done.Summary, Diffstat: done.Diffstat}
+		if content, err := os.ReadFile(planPath(pd.Name)); err == nil {
+			entry.Objective = parsePlanObjective(string(content))
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+	sort.SliceStable(report.Entries, func(i, j int) bool { return report.Entries[i].Repo < report.Entries[j].Repo })
+	return render(report, func() { printChangelog(report) })
+}
+
+// branchMerged reports whether branch exists in repoPath and is merged into
+// base (HEAD if "")
+func branchMerged(repoPath, branch, base string) bool {
+	if base == "" {
+		base = "HEAD"
+	}
+	if exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", branch).Run() != nil {
+		return false
+	}
+	return exec.Command("git", "-C", repoPath, "merge-base", "--is-ancestor", branch, base).Run() == nil
+}
+
+// printChangelog prints the entry as markdown, a section per repo if any
+func printChangelog(report *changelogReport) {
+	if len(report.Entries) == 0 {
+		fmt.Fprintln(os.Stderr, "No merged agent branches. Merge them with 'air integrate' first.")
+		return
+	}
+	fmt.Printf("## %s\n", report.Title)
+	repo := "-"
+	for _, e := range report.Entries {
+		if e.Repo != repo {
+			repo = e.Repo
+			fmt.Println()
+			if repo != "" {
+				fmt.Printf("### %s\n\n", repo)
+			}
+		}
+		line := "- **" + e.Plan + "**"
+		if e.Objective != "" {
+			line += ": " + e.Objective
+		}
+		if e.Diffstat != nil {
+			files := "files"
+			if e.Diffstat.Files == 1 {
+				files = "file"
+			}
+			line += fmt.Sprintf(" (%d %s, +%d -%d)", e.Diffstat.Files, files, e.Diffstat.Insertions, e.Diffstat.Deletions)
+		}
+		fmt.Println(line)
+		for _, l := range strings.Split(e.Summary, "\n") {
+			if l = strings.TrimRight(l, " "); l != "" {
+				fmt.Println("  " + l)
+			}
+		}
+	}
+}
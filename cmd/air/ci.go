@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// CI results of a pushed branch, as 'air integrate --auto --ci' sees them
+const (
+	ciPassed   = "passed"
+	ciFailed   = "failed"
+	ciPending  = "pending"
+	ciNone     = "none"     // no checks ran on the commit
+	ciUnpushed = "unpushed" // the branch's tip isn't on origin
+)
+
+// ciPollInterval is how often pending checks are polled
+const ciPollInterval = 30 * time.Second
+
+// ciProvider returns where repoPath's CI runs: the repo's 'git config air.ci'
+// (github or gitlab), else guessed from its origin URL, else ""
+func ciProvider(repoPath string) string {
+	out, _ := exec.Command("git", "-C", repoPath, "config", "--get", "air.ci").Output()
+	if provider := strings.TrimSpace(string(out)); provider != "" {
+		return provider
+	}
+	url, _ := exec.Command("git", "-C", repoPath, "remote", "get-url", "origin").Output()
+	switch {
+	case strings.Contains(string(url), "github"):
+		return "github"
+	case strings.Contains(string(url), "gitlab"):
+		return "gitlab"
+	}
+	return ""
+}
+
+// waitForCI polls the CI checks of branch's tip in repoPath until they finish
+// or timeout passes, returning the result and what's behind it. The tip must
+// have been pushed to origin: checks on an older commit say nothing about it.
+func waitForCI(repoPath, branch string, timeout time.Duration) (string, string) {
+	provider := ciProvider(repoPath)
+	if provider != "github" && provider != "gitlab" {
+		return ciNone, "CI unknown: origin isn't GitHub or GitLab (set git config air.ci)"
+	}
+	tip, err := exec.Command("git", "-C", repoPath, "rev-parse", branch).Output()
+	if err != nil {
+		return ciUnpushed, "branch not found"
+	}
+	sha := strings.TrimSpace(string(tip))
+	out, err := exec.Command("git", "-C", repoPath, "ls-remote", "origin", "refs/heads/"+branch).Output()
+	if err != nil {
+		return ciUnpushed, fmt.Sprintf("couldn't reach origin (%v)", err)
+	}
+	if pushed, _, _ := strings.Cut(string(out), "\t"); pushed != sha {
+		return ciUnpushed, "tip not pushed to origin"
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var state, detail string
+		if provider == "github" {
+			state, detail, err = githubChecks(repoPath, sha)
+		} else {
+			state, detail, err = gitlabPipeline(repoPath, sha)
+		}
+		if err != nil {
+			return ciNone, err.Error()
+		}
+		if state != ciPending || !time.Now().Before(deadline) {
+			return state, detail
+		}
+		time.Sleep(min(ciPollInterval, time.Until(deadline)))
+	}
+}
+
+// githubChecks combines the check runs and commit statuses of sha, read with
+// the gh CLI from the repository in repoPath
+func githubChecks(repoPath, sha string) (string, string, error) {
+	type checkRun struct {
+		Name       string `json:"name"`
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+	}
+	var runs []checkRun
+	var combined struct {
+		TotalCount int `json:"total_count"`
+		Statuses   []struct {
+			Context string `json:"context"`
+			State   string `json:"state"`
+		} `json:"statuses"`
+	}
+	// Check runs come 100 to a page at most; a failure on a later page still fails the branch
+	err := ghAPIPages(repoPath, "repos/{owner}/{repo}/commits/"+sha+"/check-runs?per_page=100", func(dec *json.Decoder) error {
+		var page struct {
+			CheckRuns []checkRun `json:"check_runs"`
+		}
+		if err := dec.Decode(&page); err != nil {
+			return err
+		}
+		runs = append(runs, page.CheckRuns...)
+		return nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if err := ghAPI(repoPath, "repos/{owner}/{repo}/commits/"+sha+"/status", &combined); err != nil {
+		return "", "", err
+	}
+
+	var failed, pending []string
+	for _, r := range runs {
+		switch {
+		case r.Status != "completed":
+			pending = append(pending, r.Name)
+		case r.Conclusion != "success" && r.Conclusion != "neutral" && r.Conclusion != "skipped":
+			failed = append(failed, r.Name)
+		}
+	}
+	for _, s := range combined.Statuses {
+		switch s.State {
+		case "pending":
+			pending = append(pending, s.Context)
+		case "failure", "error":
+			failed = append(failed, s.Context)
+		}
+	}
+	return combineChecks(len(runs)+combined.TotalCount, failed, pending)
+}
+
+// ghAPI GETs path from the GitHub API with the gh CLI into v
+func ghAPI(repoPath, path string, v any) error {
+	cmd := exec.Command("gh", "api", path)
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("gh api %s failed: %w", path, err)
+	}
+	return json.Unmarshal(out, v)
+}
+
+// ghAPIPages reads every page of path with the gh CLI, handing each page's
+// JSON document to decodePage in turn
+func ghAPIPages(repoPath, path string, decodePage func(*json.Decoder) error) error {
+	cmd := exec.Command("gh", "api", path, "--paginate")
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("gh api %s failed: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for dec.More() {
+		if err := decodePage(dec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gitlabPipeline reads the newest pipeline of sha with the glab CLI from the
+// project in repoPath
+func gitlabPipeline(repoPath, sha string) (string, string, error) {
+	cmd := exec.Command("glab", "api", "projects/:id/pipelines?sha="+sha)
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("glab api failed: %w", err)
+	}
+	var pipelines []struct {
+		ID     int    `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(out, &pipelines); err != nil {
+		return "", "", fmt.Errorf("unexpected glab reply: %w", err)
+	}
+	if len(pipelines) == 0 {
+		return combineChecks(0, nil, nil)
+	}
+	name := fmt.Sprintf("pipeline %d", pipelines[0].ID)
+	switch pipelines[0].Status {
+	case "success":
+		return combineChecks(1, nil, nil)
+	case "failed", "canceled":
+		return combineChecks(1, []string{name}, nil)
+	case "skipped":
+		return combineChecks(0, nil, nil)
+	}
+	return combineChecks(1, nil, []string{name})
+}
+
+// combineChecks is the result of total checks, of which failed failed and
+// pending haven't finished
+func combineChecks(total int, failed, pending []string) (string, string, error) {
+	switch {
+	case len(failed) > 0:
+		return ciFailed, "CI failed: " + strings.Join(failed, ", "), nil
+	case len(pending) > 0:
+		return ciPending, "CI still running: " + strings.Join(pending, ", "), nil
+	case total == 0:
+		return ciNone, "no CI checks ran", nil
+	}
+	return ciPassed, "", nil
+}
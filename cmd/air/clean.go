@@ -26,10 +26,12 @@ after error recovery.`,
 
 var cleanAll bool
 var keepPlans bool
+var cleanForce bool
 
 func init() {
 	cleanCmd.Flags().BoolVar(&cleanAll, "branches", false, "Also delete air/* branches")
 	cleanCmd.Flags().BoolVar(&keepPlans, "keep-plans", false, "Keep plans for rerunning (don't archive)")
+	cleanCmd.Flags().BoolVar(&cleanForce, "force", false, "Clean up even while a run is active")
 }
 
 // worktreeInfo holds info about a worktree for cleanup
@@ -132,23 +134,43 @@ func cleanWorkspaceWorktrees(worktrees []worktreeInfo, opts cleanOptions) error
 		} else if !opts.quiet {
 			fmt.Println("Cleared channels directory")
 		}
-		if err := os.RemoveAll(agentsDir); err != nil {
+		// Failure snapshots are kept for postmortems
+		if kept, err := clearAgentsDir(agentsDir); err != nil {
 			if !opts.quiet {
 				fmt.Printf("Warning: failed to remove agents directory: %v\n", err)
 			}
 		} else if !opts.quiet {
-			fmt.Println("Cleared agents directory")
+			if kept > 0 {
+				fmt.Printf("Cleared agents directory (kept failure snapshots for %d agents)\n", kept)
+			} else {
+				fmt.Println("Cleared agents directory")
+			}
+		}
+		if err := os.RemoveAll(getArtifactsDir()); err != nil {
+			if !opts.quiet {
+				fmt.Printf("Warning: failed to remove artifacts directory: %v\n", err)
+			}
 		}
+		os.Remove(getRunRecordPath())
 	} else {
-		// Cleaning specific items - remove their done/<name>.json and agent data
+		if err := dropRunAgents(names); err != nil && !opts.quiet {
+			fmt.Printf("Warning: failed to update run.json: %v\n", err)
+		}
+		// Cleaning specific items - remove their done/failed markers and agent
+		// data, keeping failure snapshots
 		for _, name := range names {
 			doneFile := filepath.Join(channelsDir, "done", name+".json")
 			if err := os.Remove(doneFile); err == nil && !opts.quiet {
 				fmt.Printf("Removed done channel: %s\n", name)
 			}
+			clearFailure(name)
 			agentDir := filepath.Join(agentsDir, name)
-			if err := os.RemoveAll(agentDir); err == nil && !opts.quiet {
-				fmt.Printf("Removed agent data: %s\n", name)
+			if kept, err := removeAgentData(agentDir); err == nil && !opts.quiet {
+				if kept {
+					fmt.Printf("Removed agent data: %s (kept failure snapshots)\n", name)
+				} else {
+					fmt.Printf("Removed agent data: %s\n", name)
+				}
 			}
 		}
 	}
@@ -163,8 +185,7 @@ func cleanWorkspaceWorktrees(worktrees []worktreeInfo, opts cleanOptions) error
 	} else if opts.deletePlans {
 		// Delete plans entirely
 		for _, name := range names {
-			planFile := filepath.Join(plansDir, name+".md")
-			if err := os.Remove(planFile); err != nil {
+			if err := os.Remove(planPath(name)); err != nil {
 				if !os.IsNotExist(err) && !opts.quiet {
 					fmt.Printf("Warning: failed to delete plan %s: %v\n", name, err)
 				}
@@ -173,17 +194,18 @@ func cleanWorkspaceWorktrees(worktrees []worktreeInfo, opts cleanOptions) error
 			}
 		}
 	} else {
-		// Archive plans
-		archivedDir := filepath.Join(plansDir, "archive")
-		if err := os.MkdirAll(archivedDir, 0755); err != nil {
-			return fmt.Errorf("failed to create archive directory: %w", err)
-		}
-
+		// Archive plans, keeping their group
 		for _, name := range names {
-			planFile := filepath.Join(plansDir, name+".md")
-			archivedFile := filepath.Join(archivedDir, name+".md")
+			plan, ok := findPlanFile(plansDir, name)
+			if !ok {
+				continue
+			}
+			archivedDir := filepath.Join(plansDir, archiveDirName, plan.group)
+			if err := os.MkdirAll(archivedDir, 0755); err != nil {
+				return fmt.Errorf("failed to create archive directory: %w", err)
+			}
 
-			if err := os.Rename(planFile, archivedFile); err != nil {
+			if err := os.Rename(plan.path, filepath.Join(archivedDir, name+".md")); err != nil {
 				if !os.IsNotExist(err) && !opts.quiet {
 					fmt.Printf("Warning: failed to archive plan %s: %v\n", name, err)
 				}
@@ -237,6 +259,52 @@ func cleanWorkspace(names []string, opts cleanOptions) error {
 	return cleanWorkspaceWorktrees(worktrees, opts)
 }
 
+// listWorktrees returns the agent worktrees for the current project.
+// In workspace mode they live at worktrees/<repo>/<plan>/, otherwise at worktrees/<plan>/.
+// Returns the underlying error (check os.IsNotExist) if the worktrees directory can't be read.
+func listWorktrees(info *WorkspaceInfo) ([]worktreeInfo, error) {
+	worktreesDir := getWorktreesDir()
+	entries, err := os.ReadDir(worktreesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var worktrees []worktreeInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		if info.Mode != ModeWorkspace {
+			worktrees = append(worktrees, worktreeInfo{
+				name:     entry.Name(),
+				repoPath: info.Root,
+				wtPath:   filepath.Join(worktreesDir, entry.Name()),
+			})
+			continue
+		}
+
+		repoName := entry.Name()
+		repoWorktreeDir := filepath.Join(worktreesDir, repoName)
+		planEntries, err := os.ReadDir(repoWorktreeDir)
+		if err != nil {
+			continue
+		}
+		for _, planEntry := range planEntries {
+			if !planEntry.IsDir() {
+				continue
+			}
+			worktrees = append(worktrees, worktreeInfo{
+				name:     planEntry.Name(),
+				repoName: repoName,
+				repoPath: info.repoDir(repoName),
+				wtPath:   filepath.Join(repoWorktreeDir, planEntry.Name()),
+			})
+		}
+	}
+	return worktrees, nil
+}
+
 // isDirEmpty returns true if the directory exists and contains no entries
 func isDirEmpty(path string) (bool, error) {
 	entries, err := os.ReadDir(path)
@@ -246,37 +314,33 @@ func isDirEmpty(path string) (bool, error) {
 	return len(entries) == 0, nil
 }
 
-// getExistingWorktrees returns the names of existing worktrees
+// getExistingWorktrees returns the names of the agents in run.json, and of
+// entries in the worktrees directory it doesn't know about
 func getExistingWorktrees() []string {
-	worktreesDir := getWorktreesDir()
-	entries, err := os.ReadDir(worktreesDir)
+	var names []string
+	if record, _ := readRunRecord(); record != nil {
+		for _, a := range record.Agents {
+			names = append(names, a.Name)
+		}
+	}
+	entries, err := os.ReadDir(getWorktreesDir())
 	if err != nil {
-		return nil
+		return names
 	}
-
-	var names []string
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if entry.IsDir() && !contains(names, entry.Name()) {
 			names = append(names, entry.Name())
 		}
 	}
 	return names
 }
 
-// getExistingPlans returns the names of existing plans (excluding archive/)
+// getExistingPlans returns the names of existing plans in all groups (excluding archive/)
 func getExistingPlans() []string {
-	plansDir := getPlansDir()
-	entries, err := os.ReadDir(plansDir)
-	if err != nil {
-		return nil
-	}
-
+	files, _ := listPlanFiles(getPlansDir())
 	var names []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
-			name := strings.TrimSuffix(entry.Name(), ".md")
-			names = append(names, name)
-		}
+	for _, f := range files {
+		names = append(names, f.name)
 	}
 	return names
 }
@@ -290,69 +354,23 @@ func runClean(cmd *cobra.Command, args []string) error {
 
 	worktreesDir := getWorktreesDir()
 
-	// Collect worktrees based on mode
-	var worktrees []worktreeInfo
+	// Collect worktrees from run.json, plus any on disk it doesn't know about
+	worktrees, err := runWorktrees(info)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read worktrees: %w", err)
+	}
 	existing := make(map[string]worktreeInfo)
-
-	if info.Mode == ModeWorkspace {
-		// Workspace mode: worktrees/<repo>/<plan>/
-		repoEntries, err := os.ReadDir(worktreesDir)
-		if err != nil {
-			if os.IsNotExist(err) {
-				fmt.Println("No worktrees to clean.")
-				return nil
-			}
-			return fmt.Errorf("failed to read worktrees: %w", err)
-		}
-
-		for _, repoEntry := range repoEntries {
-			if !repoEntry.IsDir() {
-				continue
-			}
-			repoName := repoEntry.Name()
-			repoPath := filepath.Join(info.Root, repoName)
-			repoWorktreeDir := filepath.Join(worktreesDir, repoName)
-
-			planEntries, err := os.ReadDir(repoWorktreeDir)
-			if err != nil {
-				continue
-			}
-
-			for _, planEntry := range planEntries {
-				if !planEntry.IsDir() {
-					continue
-				}
-				wt := worktreeInfo{
-					name:     planEntry.Name(),
-					repoName: repoName,
-					repoPath: repoPath,
-					wtPath:   filepath.Join(repoWorktreeDir, planEntry.Name()),
-				}
-				worktrees = append(worktrees, wt)
-				existing[planEntry.Name()] = wt
-			}
-		}
-	} else {
-		// Single mode: worktrees/<plan>/
-		entries, err := os.ReadDir(worktreesDir)
-		if err != nil {
-			if os.IsNotExist(err) {
-				fmt.Println("No worktrees to clean.")
-				return nil
-			}
-			return fmt.Errorf("failed to read worktrees: %w", err)
-		}
-
-		for _, entry := range entries {
-			if entry.IsDir() {
-				wt := worktreeInfo{
-					name:     entry.Name(),
-					repoPath: info.Root,
-					wtPath:   filepath.Join(worktreesDir, entry.Name()),
-				}
-				worktrees = append(worktrees, wt)
-				existing[entry.Name()] = wt
-			}
+	for _, wt := range worktrees {
+		existing[wt.name] = wt
+	}
+	listed, err := listWorktrees(info)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read worktrees: %w", err)
+	}
+	for _, wt := range listed {
+		if _, ok := existing[wt.name]; !ok {
+			existing[wt.name] = wt
+			worktrees = append(worktrees, wt)
 		}
 	}
 
@@ -395,6 +413,16 @@ func runClean(cmd *cobra.Command, args []string) error {
 		toClean = worktrees
 	}
 
+	// Cleaning kills the tmux session, so don't pull it out from under a live
+	// run. Taking the lock replaces the record of the run's session.
+	session := runSession()
+	if isInitialized() {
+		if err := acquireRunLock("clean", cleanForce); err != nil {
+			return err
+		}
+		defer releaseRunLock()
+	}
+
 	// Show what will be cleaned
 	if info.Mode == ModeWorkspace {
 		fmt.Printf("Workspace: %s\n\n", info.Name)
@@ -420,8 +448,8 @@ func runClean(cmd *cobra.Command, args []string) error {
 	}
 
 	// Kill tmux session if it exists
-	if err := exec.Command("tmux", "kill-session", "-t", "air").Run(); err == nil {
-		fmt.Println("Killed tmux session: air")
+	if err := exec.Command("tmux", "kill-session", "-t", "="+session).Run(); err == nil {
+		fmt.Println("Killed tmux session:", session)
 	}
 
 	// Perform cleanup
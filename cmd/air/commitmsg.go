@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var agentCheckCommitCmd = &cobra.Command{
+	Use:   "check-commit <message-file>",
+	Short: "Check a commit message against the plan's commit format",
+	Long: `Fails if the subject line of the commit message in message-file doesn't
+match --format. The commit-msg hook air installs in agent worktrees (with
+'git config air.commitHook true') runs this; merge, revert, and fixup commits
+are let through.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentCheckCommit,
+}
+
+var checkCommitPlan string
+var checkCommitFormat string
+
+func init() {
+	agentCmd.AddCommand(agentCheckCommitCmd)
+	agentCheckCommitCmd.Flags().StringVar(&checkCommitPlan, "plan", "", "Plan the commit belongs to (fills {plan})")
+	agentCheckCommitCmd.Flags().StringVar(&checkCommitFormat, "format", "", "Commit format to check against")
+}
+
+// conventionalCommitFormat is what air.commitFormat "conventional" stands for
+const conventionalCommitFormat = "<type>({plan}): <summary>"
+
+// conventionalTypes are the <type>s of a conventional commit
+var conventionalTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// commitPlaceholderRegex finds the {plan} and <...> placeholders of a format
+var commitPlaceholderRegex = regexp.MustCompile(`\{plan\}|<[^<>]+>`)
+
+// exemptCommitRegex matches subjects git writes itself, which no format covers
+var exemptCommitRegex = regexp.MustCompile(`^(Merge |Revert "|fixup! |squash! |amend! )`)
+
+// commitFormat returns the commit subject format for pd's agent in repoPath:
+// its **Commit format:**, else 'git config air.commitFormat', else "".
+func commitFormat(repoPath string, pd PlanDependencies) string {
+	format := pd.CommitFmt
+	if format == "" {
+		out, _ := exec.Command("git", "-C", repoPath, "config", "--get", "air.commitFormat").Output()
+		format = strings.TrimSpace(string(out))
+	}
+	if format == "conventional" {
+		return conventionalCommitFormat
+	}
+	return format
+}
+
+// compileCommitFormat turns format into a regexp for subject lines: {plan} is
+// the plan's name, <type> a conventional commit type, and any other <...>
+// free text
+func compileCommitFormat(format, plan string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	last := 0
+	for _, loc := range commitPlaceholderRegex.FindAllStringIndex(format, -1) {
+		sb.WriteString(regexp.QuoteMeta(format[last:loc[0]]))
+		switch token := format[loc[0]:loc[1]]; token {
+		case "{plan}":
+			sb.WriteString(regexp.QuoteMeta(plan))
+		case "<type>":
+			sb.WriteString("(" + strings.Join(conventionalTypes, "|") + ")")
+		default:
+			sb.WriteString(".+")
+		}
+		last = loc[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(format[last:]) + "$")
+	return regexp.MustCompile(sb.String())
+}
+
+// buildCommitInstructions tells the agent how to write commit messages
+func buildCommitInstructions(format, plan string, hook bool) string {
+	if format == "" {
+		return ""
+	}
+	example := strings.ReplaceAll(format, "{plan}", plan)
+	var sb strings.Builder
+	sb.WriteString("\n\n## Commit Messages\n\n")
+	fmt.Fprintf(&sb, "Write every commit's subject line in this format: `%s`", example)
+	if strings.Contains(format, "<type>") {
+		fmt.Fprintf(&sb, ", where <type> is one of %s", strings.Join(conventionalTypes, ", "))
+	}
+	sb.WriteString(".")
+	if hook {
+		sb.WriteString(" A commit-msg hook rejects commits that don't match; fix the message rather than bypassing the hook.")
+	}
+	return sb.String()
+}
+
+// commitHookEnabled reports whether repoPath opts into enforcing commit
+// formats with 'git config air.commitHook true'
+func commitHookEnabled(repoPath string) bool {
+	out, _ := exec.Command("git", "-C", repoPath, "config", "--type=bool", "--get", "air.commitHook").Output()
+	return strings.TrimSpace(string(out)) == "true"
+}
+
+// installCommitHook writes agents/<name>/hooks, where commit-msg checks format
+// and then runs the repository's own commit-msg. The repository's other hooks
+// are kept by wrappers that run them. The agent's git uses them through
+// commitHookEnv.
+func installCommitHook(wtPath, name, format string) error {
+	out, err := exec.Command("git", "-C", wtPath, "rev-parse", "--path-format=absolute", "--git-path", "hooks").Output()
+	if err != nil {
+		return fmt.Errorf("failed to find hooks for %s: %w", name, err)
+	}
+	repoHooks := strings.TrimSpace(string(out))
+	hooksDir := filepath.Join(getAgentDir(name), "hooks")
+	if repoHooks == hooksDir {
+		// Already installed: the repository's hooks are the ones wrapped before
+		repoHooks = ""
+		if data, err := os.ReadFile(filepath.Join(hooksDir, ".repo-hooks")); err == nil {
+			repoHooks = strings.TrimSpace(string(data))
+		}
+	}
+	os.RemoveAll(hooksDir)
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return err
+	}
+	os.WriteFile(filepath.Join(hooksDir, ".repo-hooks"), []byte(repoHooks+"\n"), 0644)
+
+	chain := func(hook string) string {
+		path := filepath.Join(repoHooks, hook)
+		return fmt.Sprintf("if [ -x %s ]; then exec %s \"$@\"; fi\n", shellQuote(path), shellQuote(path))
+	}
+	script := fmt.Sprintf("#!/bin/sh\n# Installed by air: commit subjects must match the plan's format\n%s agent check-commit --plan %s --format %s \"$1\" || exit 1\n%s",
+		shellQuote(airExecutable()), shellQuote(name), shellQuote(format), chain("commit-msg"))
+	if err := os.WriteFile(filepath.Join(hooksDir, "commit-msg"), []byte(script), 0755); err != nil {
+		return err
+	}
+	entries, _ := os.ReadDir(repoHooks)
+	for _, e := range entries {
+		if e.IsDir() || e.Name() == "commit-msg" || strings.HasSuffix(e.Name(), ".sample") {
+			continue
+		}
+		wrapper := "#!/bin/sh\n" + chain(e.Name())
+		if err := os.WriteFile(filepath.Join(hooksDir, e.Name()), []byte(wrapper), 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commitHookEnv returns the variables launch.sh sets to point the agent's git
+// at its hooks when the repo enforces its commit format: the environment's
+// form of 'git -c core.hooksPath=...'. Unlike config, it reaches only the
+// agent, and works the same in a bare hub's worktrees.
+func commitHookEnv(repoPath string, pd PlanDependencies) []envVar {
+	if commitFormat(repoPath, pd) == "" || !commitHookEnabled(repoPath) {
+		return nil
+	}
+	return []envVar{
+		{"GIT_CONFIG_COUNT", "1"},
+		{"GIT_CONFIG_KEY_0", "core.hooksPath"},
+		{"GIT_CONFIG_VALUE_0", filepath.Join(getAgentsDir(), pd.Name, "hooks")},
+	}
+}
+
+func runAgentCheckCommit(cmd *cobra.Command, args []string) error {
+	// Runs from a hook: the agent needs the reason, not the usage
+	cmd.SilenceUsage = true
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read commit message: %w", err)
+	}
+	subject := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			subject = line
+			break
+		}
+	}
+	if checkCommitFormat == "" || exemptCommitRegex.MatchString(subject) {
+		return nil
+	}
+	if !compileCommitFormat(checkCommitFormat, checkCommitPlan).MatchString(subject) {
+		example := strings.ReplaceAll(checkCommitFormat, "{plan}", checkCommitPlan)
+		return withCode(codeValidationFailed, fmt.Errorf("commit subject %q doesn't match this plan's format `%s`", subject, example))
+	}
+	return nil
+}
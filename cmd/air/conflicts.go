@@ -0,0 +1,348 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts [plans...]",
+	Short: "Show which agent branches conflict with each other",
+	Long: `Test-merges every pair of completed agent branches, and each branch into its
+target branch, with 'git merge-tree' (nothing is checked out or committed), and
+prints a matrix of which combinations conflict and on which files. Use it to
+plan the merge order before 'air integrate'.
+
+Pass plan names to check just those branches, finished or not. In workspace
+mode, branches are only compared with others in the same repository.
+
+With --live, compares agents still at work instead: the files each has
+changed since its base, committed or not, and which files two agents are both
+editing. Overlaps aren't necessarily conflicts, but they're where conflicts
+come from, and catching them early leaves time to steer one agent away.
+'air status' warns about the same overlaps.
+
+Requires git 2.38 or later.`,
+	RunE: runConflicts,
+}
+
+var conflictsLive bool
+
+func init() {
+	conflictsCmd.Flags().BoolVar(&conflictsLive, "live", false, "Compare the files in-progress agents are editing")
+	supportsOutput(conflictsCmd)
+}
+
+// conflictMatrix is the pairwise merge check of one repository's branches
+type conflictMatrix struct {
+	Repo   string         `json:"repo,omitempty"` // workspace mode
+	Target string         `json:"target"`         // branch the agents' work merges into
+	Plans  []string       `json:"plans"`
+	Pairs  []conflictPair `json:"pairs"` // every combination checked, including each plan against the target
+}
+
+// conflictPair is the outcome of test-merging two branches
+type conflictPair struct {
+	A         string   `json:"a"`
+	B         string   `json:"b"` // a plan, or the target branch
+	Conflicts bool     `json:"conflicts"`
+	Files     []string `json:"files,omitempty"`
+}
+
+// fileOverlap is the files two in-progress agents have both changed
+type fileOverlap struct {
+	Repo  string   `json:"repo,omitempty"` // workspace mode
+	A     string   `json:"a"`
+	B     string   `json:"b"`
+	Files []string `json:"files"`
+}
+
+// pair returns the outcome for a and b in either order, or nil if unchecked
+func (m *conflictMatrix) pair(a, b string) *conflictPair {
+	for i, p := range m.Pairs {
+		if (p.A == a && p.B == b) || (p.A == b && p.B == a) {
+			return &m.Pairs[i]
+		}
+	}
+	return nil
+}
+
+func runConflicts(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+	plans, err := loadAllPlanDependencies()
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]PlanDependencies)
+	for _, p := range plans {
+		byName[p.Name] = p
+	}
+	for _, name := range args {
+		if _, ok := byName[name]; !ok {
+			return errPlanNotFound(name)
+		}
+	}
+	if conflictsLive {
+		return runLiveConflicts(info, args)
+	}
+
+	// Group the branches to compare by repository, keeping plan order
+	doneDir := filepath.Join(getChannelsDir(), "done")
+	var repos []string
+	repoPlans := make(map[string][]string)
+	repoPaths := make(map[string]string)
+	for _, p := range plans {
+		if len(args) > 0 && !contains(args, p.Name) {
+			continue
+		}
+		if len(args) == 0 {
+			if _, err := os.Stat(filepath.Join(doneDir, p.Name+".json")); err != nil {
+				continue
+			}
+		}
+		repoName, repoPath, _ := agentPaths(info, p)
+		if exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", "air/"+p.Name).Run() != nil {
+			continue
+		}
+		if _, ok := repoPlans[repoName]; !ok {
+			repos = append(repos, repoName)
+			repoPaths[repoName] = repoPath
+		}
+		repoPlans[repoName] = append(repoPlans[repoName], p.Name)
+	}
+
+	matrices := []conflictMatrix{}
+	for _, repo := range repos {
+		m, err := buildConflictMatrix(repoPaths[repo], resolveAgentBase(repoPaths[repo], info.baseBranch(repo)), repoPlans[repo])
+		if err != nil {
+			return err
+		}
+		m.Repo = repo
+		matrices = append(matrices, *m)
+	}
+	return render(matrices, func() { printConflictMatrices(matrices, len(args) > 0) })
+}
+
+// runLiveConflicts reports the files agents still at work are both editing
+func runLiveConflicts(info *WorkspaceInfo, args []string) error {
+	agents, err := runWorktrees(info)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read worktrees: %w", err)
+	}
+	var active []worktreeInfo
+	for _, agent := range agents {
+		if len(args) > 0 && !contains(args, agent.name) {
+			continue
+		}
+		if len(args) == 0 && (channelExists("done/"+agent.name) || readFailure(agent.name) != nil) {
+			continue
+		}
+		active = append(active, agent)
+	}
+	overlaps := findOverlaps(active)
+	return render(overlaps, func() {
+		if len(active) < 2 {
+			fmt.Println("Fewer than two agents at work; nothing to compare.")
+			return
+		}
+		if len(overlaps) == 0 {
+			fmt.Printf("No overlapping edits between %d agents at work.\n", len(active))
+			return
+		}
+		for _, o := range overlaps {
+			fmt.Printf("  ⚠ %s\n", formatOverlap(o))
+		}
+		fmt.Println()
+		fmt.Println("These may conflict when both finish. Tell one agent to steer clear, or serialize them with a channel.")
+	})
+}
+
+// changedFiles lists the files an agent has changed since its base: committed,
+// uncommitted, and untracked. Returns nil if its base wasn't recorded.
+func changedFiles(agent worktreeInfo) []string {
+	base := readAgentBase(agent.name)
+	if base == nil || base.SHA == "" {
+		return nil
+	}
+	// Against the working tree, so uncommitted edits to tracked files count
+	diffOut, _ := exec.Command("git", "-C", agent.wtPath, "diff", "--name-only", base.SHA).Output()
+	untrackedOut, _ := exec.Command("git", "-C", agent.wtPath, "ls-files", "--others", "--exclude-standard").Output()
+	var files []string
+	for _, line := range strings.Split(string(diffOut)+string(untrackedOut), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !contains(files, line) {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
+// findOverlaps compares every pair of agents in the same repository for files
+// they have both changed
+func findOverlaps(agents []worktreeInfo) []fileOverlap {
+	changed := make(map[string][]string)
+	for _, agent := range agents {
+		changed[agent.name] = changedFiles(agent)
+	}
+	overlaps := []fileOverlap{}
+	for i, a := range agents {
+		for _, b := range agents[i+1:] {
+			if a.repoName != b.repoName {
+				continue
+			}
+			var files []string
+			for _, f := range changed[a.name] {
+				if contains(changed[b.name], f) {
+					files = append(files, f)
+				}
+			}
+			if len(files) > 0 {
+				overlaps = append(overlaps, fileOverlap{Repo: a.repoName, A: a.name, B: b.name, Files: files})
+			}
+		}
+	}
+	return overlaps
+}
+
+// formatOverlap describes an overlap, e.g. "api and web are both editing routes.go"
+func formatOverlap(o fileOverlap) string {
+	files := o.Files
+	more := ""
+	if len(files) > 5 {
+		files, more = files[:5], fmt.Sprintf(" (+%d more)", len(o.Files)-5)
+	}
+	prefix := ""
+	if o.Repo != "" {
+		prefix = o.Repo + ": "
+	}
+	return fmt.Sprintf("%s%s and %s are both editing %s%s", prefix, o.A, o.B, strings.Join(files, ", "), more)
+}
+
+// buildConflictMatrix test-merges each pair of plans' branches, and each
+// into target
+func buildConflictMatrix(repoPath string, target agentBase, plans []string) (*conflictMatrix, error) {
+	m := &conflictMatrix{Target: target.Branch, Plans: plans, Pairs: []conflictPair{}}
+	if m.Target == "" {
+		m.Target = "HEAD"
+	}
+	for i, a := range plans {
+		files, conflicts, err := mergeTreeConflicts(repoPath, target.SHA, "air/"+a)
+		if err != nil {
+			return nil, err
+		}
+		m.Pairs = append(m.Pairs, conflictPair{A: a, B: m.Target, Conflicts: conflicts, Files: files})
+		for _, b := range plans[i+1:] {
+			files, conflicts, err := mergeTreeConflicts(repoPath, "air/"+a, "air/"+b)
+			if err != nil {
+				return nil, err
+			}
+			m.Pairs = append(m.Pairs, conflictPair{A: a, B: b, Conflicts: conflicts, Files: files})
+		}
+	}
+	return m, nil
+}
+
+// mergeTreeConflicts test-merges two commits without touching any worktree,
+// returning whether they conflict and the conflicting files
+func mergeTreeConflicts(repoPath, a, b string) ([]string, bool, error) {
+	out, err := exec.Command("git", "-C", repoPath, "merge-tree", "--write-tree", "--name-only", "--no-messages", a, b).Output()
+	if err == nil {
+		return nil, false, nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		return nil, false, fmt.Errorf("git merge-tree %s %s failed in %s (requires git 2.38+): %w", a, b, repoPath, err)
+	}
+	// Exit status 1 means conflicts: the tree, then one conflicted file per line
+	var files []string
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for _, line := range lines[1:] {
+		if line = strings.TrimSpace(line); line != "" && !contains(files, line) {
+			files = append(files, line)
+		}
+	}
+	return files, true, nil
+}
+
+// printConflictMatrices prints each repository's matrix and the files behind
+// every conflict
+func printConflictMatrices(matrices []conflictMatrix, named bool) {
+	if len(matrices) == 0 {
+		if named {
+			fmt.Println("None of those plans have a branch yet.")
+		} else {
+			fmt.Println("No completed agent branches to compare.")
+		}
+		return
+	}
+
+	total := 0
+	for i, m := range matrices {
+		if i > 0 {
+			fmt.Println()
+		}
+		if m.Repo != "" {
+			fmt.Printf("%s:\n", m.Repo)
+		}
+		columns := append([]string{m.Target}, m.Plans...)
+		width := 0
+		for _, c := range columns {
+			width = max(width, len(c))
+		}
+
+		fmt.Printf("  %-*s", width, "")
+		for _, c := range columns {
+			fmt.Printf("  %-*s", width, c)
+		}
+		fmt.Println()
+		for _, row := range m.Plans {
+			fmt.Printf("  %-*s", width, row)
+			for _, col := range columns {
+				cell := "·"
+				if p := m.pair(row, col); p != nil {
+					cell = "✓"
+					if p.Conflicts {
+						cell = "✗"
+					}
+				}
+				fmt.Printf("  %-*s", width, cell)
+			}
+			fmt.Println()
+		}
+
+		var lines []string
+		for _, p := range m.Pairs {
+			if !p.Conflicts {
+				continue
+			}
+			files := strings.Join(p.Files, ", ")
+			if files == "" {
+				files = "(no file names reported)"
+			}
+			lines = append(lines, fmt.Sprintf("  ✗ %s × %s: %s", p.A, p.B, files))
+		}
+		if len(lines) > 0 {
+			fmt.Println()
+			fmt.Println(strings.Join(lines, "\n"))
+		}
+		total += len(lines)
+	}
+
+	fmt.Println()
+	if total == 0 {
+		fmt.Println("No conflicts: the branches merge cleanly in any order.")
+	} else {
+		fmt.Printf("%d conflicting combinations. Merge one side of each first and resolve the other against it, or rebase it before integrating.\n", total)
+	}
+}
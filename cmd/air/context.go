@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Inspect or customize the agent context",
+	Long: `The context (~/.air/<project>/context.md) holds the workflow instructions
+appended to every agent's system prompt. Additions for individual agents live in
+~/.air/<project>/context/ (see **Context files:** in plans).`,
+}
+
+var contextShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the agent context",
+	Args:  cobra.NoArgs,
+	RunE:  runContextShow,
+}
+
+var contextEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the agent context in $EDITOR",
+	Long: `Opens context.md in $VISUAL or $EDITOR (default: vi), then checks it.
+If the result is empty, the previous context is restored.`,
+	Args: cobra.NoArgs,
+	RunE: runContextEdit,
+}
+
+var contextShowPath bool
+
+func init() {
+	contextCmd.AddCommand(contextShowCmd)
+	contextCmd.AddCommand(contextEditCmd)
+	contextShowCmd.Flags().BoolVar(&contextShowPath, "path", false, "Print only the path to context.md")
+}
+
+func runContextShow(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+
+	contextPath := getContextPath()
+	if contextShowPath {
+		fmt.Println(contextPath)
+		return nil
+	}
+
+	content, err := os.ReadFile(contextPath)
+	if err != nil {
+		return fmt.Errorf("failed to read context: %w", err)
+	}
+	fmt.Print(string(content))
+	return nil
+}
+
+func runContextEdit(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+
+	contextPath := getContextPath()
+	previous, err := os.ReadFile(contextPath)
+	if err != nil {
+		return fmt.Errorf("failed to read context: %w", err)
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// Allow editors with arguments, e.g. EDITOR="code --wait"
+	fields := strings.Fields(editor)
+	editCmd := exec.Command(fields[0], append(fields[1:], contextPath)...)
+	editCmd.Stdin = os.Stdin
+	editCmd.Stdout = os.Stdout
+	editCmd.Stderr = os.Stderr
+	if err := editCmd.Run(); err != nil {
+		return fmt.Errorf("editor '%s' failed: %w", editor, err)
+	}
+
+	content, err := os.ReadFile(contextPath)
+	if err != nil {
+		return fmt.Errorf("failed to read context: %w", err)
+	}
+	if strings.TrimSpace(string(content)) == "" {
+		if err := os.WriteFile(contextPath, previous, 0644); err != nil {
+			return fmt.Errorf("context is empty and restoring it failed: %w", err)
+		}
+		return fmt.Errorf("context is empty; restored the previous version")
+	}
+
+	if string(content) == string(previous) {
+		fmt.Println("Context unchanged.")
+		return nil
+	}
+	for _, w := range contextWarnings(string(content)) {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	fmt.Printf("Updated %s (takes effect for agents launched from now on)\n", contextPath)
+	return nil
+}
+
+// contextWarnings flags workflow commands missing from a customized context
+// that Air relies on agents running
+func contextWarnings(content string) []string {
+	var warnings []string
+	if !strings.Contains(content, "air agent done") {
+		warnings = append(warnings, "context no longer mentions 'air agent done'; agents won't mark themselves complete and 'air status' won't see them finish")
+	}
+	if !strings.Contains(content, "air agent wait") || !strings.Contains(content, "air agent signal") {
+		warnings = append(warnings, "context no longer mentions 'air agent wait'/'air agent signal'; plans with dependencies won't coordinate")
+	}
+	return warnings
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package main
+
+// diskFree isn't implemented on Windows; capacity checks that need it are skipped
+func diskFree(path string) (free int64, ok bool) {
+	return 0, false
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -19,16 +21,31 @@ var doctorCmd = &cobra.Command{
 type checkResult struct {
 	name    string
 	ok      bool
+	warn    bool // passed, but message is worth acting on
 	version string
 	message string
 }
 
+func init() {
+	supportsOutput(doctorCmd)
+}
+
+// doctorCheck is one check in 'air doctor --output json|yaml'
+type doctorCheck struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Warning bool   `json:"warning,omitempty"`
+	Version string `json:"version,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
 func runDoctor(cmd *cobra.Command, args []string) error {
-	fmt.Println("Checking environment...")
-	fmt.Println()
+	if outputFormat == "text" {
+		fmt.Println("Checking environment...")
+		fmt.Println()
+	}
 
 	var results []checkResult
-	allOk := true
 
 	// Check git
 	results = append(results, checkGit())
@@ -48,9 +65,35 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// Check if air is initialized (optional context)
 	results = append(results, checkAirInit())
 
-	// Print results
+	// Check context.md is from the current template (only once initialized)
+	if isInitialized() {
+		results = append(results, checkContextTemplate())
+	}
+
+	// Check the machine can take the plans not yet run (only once initialized)
+	if isInitialized() {
+		if info, err := detectMode(); err == nil {
+			results = append(results, checkCapacity(info)...)
+			results = append(results, checkRunRecord(info))
+		}
+	}
+
+	checks := make([]doctorCheck, 0, len(results))
 	for _, r := range results {
-		if r.ok {
+		checks = append(checks, doctorCheck{Name: r.name, OK: r.ok, Warning: r.warn, Version: r.version, Message: r.message})
+	}
+
+	return render(checks, func() { printDoctorResults(results) })
+}
+
+// printDoctorResults prints check results for humans
+func printDoctorResults(results []checkResult) {
+	allOk, warned := true, false
+	for _, r := range results {
+		if r.ok && r.warn {
+			warned = true
+			fmt.Printf("  ⚠ %s - %s\n", r.name, r.message)
+		} else if r.ok {
 			if r.version != "" {
 				fmt.Printf("  ✓ %s %s\n", r.name, r.version)
 			} else {
@@ -63,13 +106,13 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Println()
-	if allOk {
+	if allOk && warned {
+		fmt.Println("All checks passed, with warnings above.")
+	} else if allOk {
 		fmt.Println("All checks passed!")
 	} else {
 		fmt.Println("Some checks failed. Fix the issues above to use air.")
 	}
-
-	return nil
 }
 
 func checkGit() checkResult {
@@ -196,3 +239,175 @@ func checkAirInit() checkResult {
 		version: "configured",
 	}
 }
+
+func checkContextTemplate() checkResult {
+	info, err := detectMode()
+	if err != nil {
+		return checkResult{name: "context template", ok: false, message: err.Error()}
+	}
+	content, err := os.ReadFile(getContextPath())
+	if err != nil {
+		return checkResult{name: "context template", ok: false, message: "context.md missing (run 'air init')"}
+	}
+
+	version, _ := readContextStamp(string(content))
+	if version != templateVersion(projectContextTemplate(info)) {
+		return checkResult{
+			name:    "context template",
+			ok:      false,
+			message: describeStaleTemplates(info),
+		}
+	}
+
+	return checkResult{
+		name:    "context template",
+		ok:      true,
+		version: version,
+	}
+}
+
+// checkRunRecord compares run.json, the registry of the run's agents, with
+// the worktrees on disk, which crashes and manual changes can set apart
+func checkRunRecord(info *WorkspaceInfo) checkResult {
+	result := checkResult{name: "run.json", ok: true}
+	record, err := readRunRecord()
+	if err != nil {
+		result.ok = false
+		result.message = err.Error()
+		return result
+	}
+	if record == nil {
+		result.version = "no run"
+		return result
+	}
+	result.version = fmt.Sprintf("%d agents", len(record.Agents))
+
+	var problems, missing, unknown []string
+	for _, a := range record.Agents {
+		if _, err := os.Stat(a.Worktree); os.IsNotExist(err) {
+			missing = append(missing, a.Name)
+		}
+	}
+	listed, _ := listWorktrees(info)
+	for _, wt := range listed {
+		if record.agent(wt.name) == nil {
+			unknown = append(unknown, wt.name)
+		}
+	}
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("worktrees of %s are gone ('air clean %s' forgets them)", strings.Join(missing, ", "), strings.Join(missing, " ")))
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, fmt.Sprintf("worktrees of %s aren't in run.json ('air clean %s' removes them)", strings.Join(unknown, ", "), strings.Join(unknown, " ")))
+	}
+	if len(problems) > 0 {
+		result.warn = true
+		result.message = strings.Join(problems, "; ")
+	}
+	return result
+}
+
+// doctorMaxWorktrees is how many worktrees or air/* branches a project can
+// pile up before doctor suggests cleaning up
+const doctorMaxWorktrees = 20
+
+// checkCapacity checks disk space for the worktrees of plans not yet run, how
+// many worktrees and branches are lying around, and how many agents would run
+// at once for the CPUs available
+func checkCapacity(info *WorkspaceInfo) []checkResult {
+	plans, _ := loadAllPlanDependencies()
+	worktrees, _ := runWorktrees(info)
+	if listed, err := listWorktrees(info); err == nil && len(listed) > len(worktrees) {
+		worktrees = listed
+	}
+	hasWorktree := make(map[string]bool)
+	active := 0
+	for _, wt := range worktrees {
+		hasWorktree[wt.name] = true
+		if !channelExists("done/"+wt.name) && readFailure(wt.name) == nil {
+			active++
+		}
+	}
+
+	// Size a new worktree by the checkout of its repo's HEAD
+	var planned []string
+	var needed int64
+	sizes := make(map[string]int64)
+	for _, p := range plans {
+		if hasWorktree[p.Name] || channelExists("done/"+p.Name) {
+			continue
+		}
+		planned = append(planned, p.Name)
+		_, repoPath, _ := agentPaths(info, p)
+		if _, ok := sizes[repoPath]; !ok {
+			sizes[repoPath] = checkoutSize(repoPath)
+		}
+		needed += sizes[repoPath]
+	}
+
+	var results []checkResult
+	disk := checkResult{name: "disk space", ok: true}
+	if free, ok := diskFree(mustGetAirDir()); !ok {
+		disk.version = "unknown on this platform"
+	} else {
+		disk.version = fmt.Sprintf("%s free, ~%s for %d planned worktrees", formatBytes(free), formatBytes(needed), len(planned))
+		// Builds and dependencies grow worktrees well past their checkout
+		if free < 2*needed {
+			disk.warn = true
+			disk.message = fmt.Sprintf("only %s free for %d planned worktrees of ~%s checked out, before builds and dependencies; free up space ('air du' shows what cleanup reclaims)",
+				formatBytes(free), len(planned), formatBytes(needed))
+		}
+	}
+	results = append(results, disk)
+
+	branches := 0
+	repos := []string{info.Root}
+	if info.Mode == ModeWorkspace {
+		repos = nil
+		for _, name := range info.Repos {
+			repos = append(repos, info.repoDir(name))
+		}
+	}
+	for _, repo := range repos {
+		out, _ := exec.Command("git", "-C", repo, "for-each-ref", "--format=%(refname)", "refs/heads/air/").Output()
+		if trimmed := strings.TrimSpace(string(out)); trimmed != "" {
+			branches += len(strings.Split(trimmed, "\n"))
+		}
+	}
+	clutter := checkResult{name: "worktrees", ok: true, version: fmt.Sprintf("%d worktrees, %d air/* branches", len(worktrees), branches)}
+	if len(worktrees) >= doctorMaxWorktrees || branches >= doctorMaxWorktrees {
+		clutter.warn = true
+		clutter.message = fmt.Sprintf("%d worktrees and %d air/* branches lying around; 'air clean --branches' removes finished ones", len(worktrees), branches)
+	}
+	results = append(results, clutter)
+
+	cpus := runtime.NumCPU()
+	agents := checkResult{name: "agents", ok: true, version: fmt.Sprintf("%d running, %d planned, %d CPUs", active, len(planned), cpus)}
+	if active+len(planned) > cpus {
+		agents.warn = true
+		agents.message = fmt.Sprintf("%d running and %d planned agents on %d CPUs; their builds and tests will compete, so consider running plans in groups or phases",
+			active, len(planned), cpus)
+	}
+	results = append(results, agents)
+	return results
+}
+
+// checkoutSize estimates a worktree of repoPath from the blob sizes at HEAD
+func checkoutSize(repoPath string) int64 {
+	out, err := exec.Command("git", "-C", repoPath, "ls-tree", "-r", "-l", "HEAD").Output()
+	if err != nil {
+		return 0
+	}
+	var total int64
+	for _, line := range strings.Split(string(out), "\n") {
+		// <mode> <type> <object> <size>\t<path>; submodules have size "-"
+		meta, _, _ := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if len(fields) == 4 {
+			if n, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+				total += n
+			}
+		}
+	}
+	return total
+}
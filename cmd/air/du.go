@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var duCmd = &cobra.Command{
+	Use:   "du",
+	Short: "Report disk usage across all Air projects",
+	Long: `Reports disk consumed under ~/.air/ per project: each worktree, agent data,
+and archived plans, followed by suggestions for what cleanup would reclaim.`,
+	Args: cobra.NoArgs,
+	RunE: runDu,
+}
+
+func init() {
+	supportsOutput(duCmd)
+}
+
+// projectUsage is the disk usage breakdown for one ~/.air/<project>/ directory
+type projectUsage struct {
+	name      string
+	dir       string
+	worktrees []namedSize
+	agents    int64
+	agentDirs int
+	archive   int64
+	archived  int
+	total     int64
+}
+
+// namedSize is a labeled size in bytes
+type namedSize struct {
+	name string
+	size int64
+}
+
+// worktreeTotal sums the sizes of all worktrees in the project
+func (p projectUsage) worktreeTotal() int64 {
+	var total int64
+	for _, wt := range p.worktrees {
+		total += wt.size
+	}
+	return total
+}
+
+func runDu(cmd *cobra.Command, args []string) error {
+	root, err := getAirRoot()
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", root, err)
+		}
+		if outputFormat == "text" {
+			fmt.Println("No Air projects found.")
+			return nil
+		}
+	}
+
+	var projects []projectUsage
+	var grandTotal int64
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		p := measureProject(e.Name(), filepath.Join(root, e.Name()))
+		projects = append(projects, p)
+		grandTotal += p.total
+	}
+
+	if len(projects) == 0 && outputFormat == "text" {
+		fmt.Println("No Air projects found.")
+		return nil
+	}
+
+	// Largest projects first
+	sort.Slice(projects, func(i, j int) bool { return projects[i].total > projects[j].total })
+
+	report := duReport{Root: root, Total: grandTotal, Projects: []duProject{}}
+	for _, p := range projects {
+		dp := duProject{Name: p.name, Total: p.total, Worktrees: []duEntry{}, Agents: p.agents, Archive: p.archive}
+		for _, wt := range p.worktrees {
+			dp.Worktrees = append(dp.Worktrees, duEntry{Name: wt.name, Size: wt.size})
+		}
+		report.Projects = append(report.Projects, dp)
+	}
+
+	return render(report, func() {
+		for _, p := range projects {
+			fmt.Printf("%-40s %8s\n", p.name, formatBytes(p.total))
+			fmt.Printf("  %-38s %8s\n", fmt.Sprintf("worktrees (%d)", len(p.worktrees)), formatBytes(p.worktreeTotal()))
+			for _, wt := range p.worktrees {
+				fmt.Printf("    %-36s %8s\n", wt.name, formatBytes(wt.size))
+			}
+			fmt.Printf("  %-38s %8s\n", fmt.Sprintf("agents (%d)", p.agentDirs), formatBytes(p.agents))
+			fmt.Printf("  %-38s %8s\n", fmt.Sprintf("archived plans (%d)", p.archived), formatBytes(p.archive))
+		}
+		fmt.Printf("\nTotal: %s in %s\n", formatBytes(grandTotal), root)
+
+		// Suggestions
+		var suggestions []string
+		for _, p := range projects {
+			if reclaim := p.worktreeTotal() + p.agents; len(p.worktrees) > 0 || p.agentDirs > 0 {
+				suggestions = append(suggestions, fmt.Sprintf("%s: 'air clean' would reclaim %s (%d worktrees, %d agent dirs)",
+					p.name, formatBytes(reclaim), len(p.worktrees), p.agentDirs))
+			}
+			if p.archived > 0 {
+				suggestions = append(suggestions, fmt.Sprintf("%s: %d archived plans (%s) in %s can be deleted if no longer needed",
+					p.name, p.archived, formatBytes(p.archive), filepath.Join(p.dir, "plans", "archive")))
+			}
+		}
+		if len(suggestions) > 0 {
+			fmt.Println("\nSuggestions:")
+			for _, s := range suggestions {
+				fmt.Printf("  %s\n", s)
+			}
+		}
+	})
+}
+
+// duReport is 'air du --output json|yaml': sizes in bytes per project
+type duReport struct {
+	Root     string      `json:"root"`
+	Total    int64       `json:"total"`
+	Projects []duProject `json:"projects"`
+}
+
+type duProject struct {
+	Name      string    `json:"name"`
+	Total     int64     `json:"total"`
+	Worktrees []duEntry `json:"worktrees"`
+	Agents    int64     `json:"agents"`
+	Archive   int64     `json:"archive"`
+}
+
+type duEntry struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// measureProject computes the disk usage breakdown for one project directory.
+// Worktrees are recognized by their .git file, so both the single-mode
+// (worktrees/<plan>) and workspace-mode (worktrees/<repo>/<plan>) layouts work.
+func measureProject(name, dir string) projectUsage {
+	p := projectUsage{name: name, dir: dir, total: dirSize(dir)}
+
+	worktreesDir := filepath.Join(dir, "worktrees")
+	if entries, err := os.ReadDir(worktreesDir); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			path := filepath.Join(worktreesDir, e.Name())
+			if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+				p.worktrees = append(p.worktrees, namedSize{e.Name(), dirSize(path)})
+				continue
+			}
+			// Workspace layout: descend into the repo directory
+			planEntries, _ := os.ReadDir(path)
+			for _, pe := range planEntries {
+				if pe.IsDir() {
+					p.worktrees = append(p.worktrees, namedSize{
+						fmt.Sprintf("%s [%s]", pe.Name(), e.Name()),
+						dirSize(filepath.Join(path, pe.Name())),
+					})
+				}
+			}
+		}
+	}
+
+	agentsDir := filepath.Join(dir, "agents")
+	if entries, err := os.ReadDir(agentsDir); err == nil {
+		for _, e := range entries {
+			if e.IsDir() {
+				p.agentDirs++
+			}
+		}
+		p.agents = dirSize(agentsDir)
+	}
+
+	archiveDir := filepath.Join(dir, "plans", "archive")
+	if entries, err := os.ReadDir(archiveDir); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") {
+				p.archived++
+			}
+		}
+		p.archive = dirSize(archiveDir)
+	}
+
+	return p
+}
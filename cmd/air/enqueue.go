@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var enqueueCmd = &cobra.Command{
+	Use:   "enqueue <plans...>",
+	Short: "Queue plans to join the active run",
+	Long: `Adds plans to the active run without restarting its tmux session. The run's
+dashboard ('air status --watch', in the session's dash window) launches each
+queued plan into the session once the channels it waits on are signaled and an
+agent slot is free (see 'air slots'), with the flags the run started with.
+
+Queued plans are listed in 'air status' with what they're waiting for.
+--cancel takes plans off the queue.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runEnqueue,
+}
+
+var enqueueCancel bool
+
+func init() {
+	enqueueCmd.Flags().BoolVar(&enqueueCancel, "cancel", false, "Take the plans off the queue")
+}
+
+// QueuedPlan is a plan waiting in run.json to join the run
+type QueuedPlan struct {
+	Name     string    `json:"name"`
+	Enqueued time.Time `json:"enqueued"`
+	Error    string    `json:"error,omitempty"` // why it couldn't be launched last time
+}
+
+// queuedStatus is a queued plan as 'air status' shows it
+type queuedStatus struct {
+	Plan    string   `json:"plan"`
+	Waiting []string `json:"waiting"` // channels it waits on that aren't signaled yet
+	Error   string   `json:"error,omitempty"`
+}
+
+func runEnqueue(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	if enqueueCancel {
+		return cancelQueued(args)
+	}
+	if err := requireActiveRun(); err != nil {
+		return err
+	}
+
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+	for _, name := range args {
+		if _, err := os.Stat(planPath(name)); err != nil {
+			return errPlanNotFound(name)
+		}
+	}
+	plans, validationErrs := ValidatePlansWithMode(info)
+	if len(validationErrs) > 0 {
+		fmt.Println("Dependency validation failed:")
+		for _, err := range validationErrs {
+			fmt.Printf("  ✗ %s\n", err)
+		}
+		return withCode(codeValidationFailed, fmt.Errorf("invalid dependency graph"))
+	}
+	planInfoMap := make(map[string]PlanDependencies)
+	for _, pd := range plans {
+		planInfoMap[pd.Name] = pd
+	}
+
+	var added, retried []string
+	err = updateRunRecord(func(r *RunRecord) *RunRecord {
+		if r == nil {
+			return nil
+		}
+		for _, name := range args {
+			if a := r.agent(name); a != nil && a.Launched != nil {
+				fmt.Printf("%s is already part of the run\n", name)
+				continue
+			}
+			if q := queued(r, name); q != nil {
+				// Enqueuing again retries a plan that failed to launch
+				if q.Error != "" {
+					q.Error = ""
+					retried = append(retried, name)
+				} else {
+					fmt.Printf("%s is already queued\n", name)
+				}
+				continue
+			}
+			r.Queue = append(r.Queue, QueuedPlan{Name: name, Enqueued: time.Now().UTC()})
+			added = append(added, name)
+		}
+		return r
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update run.json: %w", err)
+	}
+
+	for _, name := range append(added, retried...) {
+		if waiting := unmetWaits(planInfoMap[name], time.Now()); len(waiting) > 0 {
+			fmt.Printf("Queued %s: launches once %s signaled\n", name, strings.Join(waiting, ", "))
+		} else {
+			fmt.Printf("Queued %s: launches when an agent slot is free\n", name)
+		}
+	}
+	if len(added)+len(retried) > 0 {
+		fmt.Printf("The run's dashboard (the dash window of tmux session '%s') launches queued plans.\n", runSession())
+	}
+	return nil
+}
+
+// cancelQueued takes plans off the run's queue
+func cancelQueued(names []string) error {
+	var removed []string
+	err := updateRunRecord(func(r *RunRecord) *RunRecord {
+		if r == nil {
+			return nil
+		}
+		var kept []QueuedPlan
+		for _, q := range r.Queue {
+			if contains(names, q.Name) {
+				removed = append(removed, q.Name)
+			} else {
+				kept = append(kept, q)
+			}
+		}
+		r.Queue = kept
+		return r
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update run.json: %w", err)
+	}
+	for _, name := range names {
+		if contains(removed, name) {
+			fmt.Printf("Removed %s from the queue\n", name)
+		} else {
+			fmt.Printf("%s isn't queued\n", name)
+		}
+	}
+	return nil
+}
+
+// queued returns the plan's entry in the run's queue, or nil
+func queued(r *RunRecord, name string) *QueuedPlan {
+	for i := range r.Queue {
+		if r.Queue[i].Name == name {
+			return &r.Queue[i]
+		}
+	}
+	return nil
+}
+
+// unmetWaits returns the channels pd waits on (not optionally) that aren't
+// signaled yet. A queued plan launches once there are none, rather than
+// holding an agent slot while it waits.
+func unmetWaits(pd PlanDependencies, now time.Time) []string {
+	var unmet []string
+	for _, ch := range pd.WaitsOn {
+		if p, err := readChannel(ch); err != nil || p.expired(now) {
+			unmet = append(unmet, ch)
+		}
+	}
+	return unmet
+}
+
+// freeSlots returns how many more agents may start machine-wide (-1: no cap)
+func freeSlots() int {
+	path, err := getSlotsPath()
+	if err != nil {
+		return -1
+	}
+	t := readSlots(path)
+	switch {
+	case t.Backoff.active(time.Now()):
+		return 0
+	case t.Max == 0:
+		return -1
+	}
+	return max(t.Max-len(t.Holders), 0)
+}
+
+// launchQueued launches the queued plans that are ready into the run's tmux
+// session, as far as free agent slots allow, and returns their names. A plan
+// that fails to launch stays queued with the error, until enqueued again.
+func launchQueued(info *WorkspaceInfo) []string {
+	record, _ := readRunRecord()
+	if record == nil || len(record.Queue) == 0 {
+		return nil
+	}
+	if exec.Command("tmux", "has-session", "-t", "="+runSession()).Run() != nil {
+		return nil
+	}
+	plans, _ := ValidatePlansWithMode(info)
+	planInfoMap := make(map[string]PlanDependencies)
+	for _, pd := range plans {
+		planInfoMap[pd.Name] = pd
+	}
+	contextContent, err := os.ReadFile(getContextPath())
+	if err != nil {
+		return nil
+	}
+	record.Flags.use()
+
+	var launched []string
+	free := freeSlots()
+	now := time.Now()
+	for _, q := range record.Queue {
+		if free == 0 {
+			break
+		}
+		// Failed launches wait for 'air enqueue' to retry them
+		if q.Error != "" {
+			continue
+		}
+		pd, ok := planInfoMap[q.Name]
+		if !ok {
+			setQueuedError(q.Name, "plan not found, or it doesn't validate (see 'air plan validate')")
+			continue
+		}
+		if len(unmetWaits(pd, now)) > 0 {
+			continue
+		}
+		// Take it off the queue first, so a second dashboard can't launch it too
+		if !dequeue(q.Name) {
+			continue
+		}
+		agent, runAgent, err := prepareAgent(info, pd, plans, contextContent)
+		if err == nil {
+			err = recordRun(info, []RunAgent{runAgent})
+		}
+		if err == nil {
+			err = addAgentsToSession(info, []worktreeInfo{agent})
+		}
+		if err != nil {
+			requeue(QueuedPlan{Name: q.Name, Enqueued: q.Enqueued, Error: err.Error()})
+			continue
+		}
+		launched = append(launched, q.Name)
+		if free > 0 {
+			free--
+		}
+	}
+	return launched
+}
+
+// dequeue takes a plan off the run's queue, reporting whether it was there
+func dequeue(name string) bool {
+	found := false
+	updateRunRecord(func(r *RunRecord) *RunRecord {
+		if r == nil {
+			return nil
+		}
+		for i, q := range r.Queue {
+			if q.Name == name {
+				r.Queue = append(r.Queue[:i], r.Queue[i+1:]...)
+				found = true
+				break
+			}
+		}
+		return r
+	})
+	return found
+}
+
+// requeue puts a plan that failed to launch back at the front of the queue
+func requeue(q QueuedPlan) {
+	updateRunRecord(func(r *RunRecord) *RunRecord {
+		if r != nil && queued(r, q.Name) == nil {
+			r.Queue = append([]QueuedPlan{q}, r.Queue...)
+		}
+		return r
+	})
+}
+
+// setQueuedError records why a queued plan can't launch
+func setQueuedError(name, msg string) {
+	updateRunRecord(func(r *RunRecord) *RunRecord {
+		if r == nil {
+			return nil
+		}
+		if q := queued(r, name); q != nil {
+			q.Error = msg
+		}
+		return r
+	})
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// ErrorCode classifies a failure for scripts: a stable name (used by
+// --json-errors) and a distinct process exit code
+type ErrorCode struct {
+	Name string
+	Exit int
+}
+
+var (
+	codeGeneral           = ErrorCode{"error", 1}
+	codeUsage             = ErrorCode{"usage", 2}
+	codeNotInitialized    = ErrorCode{"not-initialized", 3}
+	codePlanNotFound      = ErrorCode{"plan-not-found", 4}
+	codeValidationFailed  = ErrorCode{"validation-failed", 5}
+	codeMergeConflict     = ErrorCode{"merge-conflict", 6}
+	codeDependencyMissing = ErrorCode{"dependency-missing", 7}
+	codeRunActive         = ErrorCode{"run-active", 8}
+	codeInterrupted       = ErrorCode{"interrupted", 130}
+)
+
+// codedError attaches an ErrorCode to an error
+type codedError struct {
+	code ErrorCode
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// withCode tags err with code; errors without a code exit with codeGeneral
+func withCode(code ErrorCode, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// errorCode returns the code attached anywhere in err's chain
+func errorCode(err error) ErrorCode {
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return codeGeneral
+}
+
+// errNotInitialized is returned by commands that need 'air init' first
+func errNotInitialized() error {
+	return withCode(codeNotInitialized, fmt.Errorf("not initialized (run 'air init' first)"))
+}
+
+// errPlanNotFound is returned when a named plan doesn't exist
+func errPlanNotFound(name string) error {
+	return withCode(codePlanNotFound, fmt.Errorf("plan '%s' not found", name))
+}
+
+var jsonErrors bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&jsonErrors, "json-errors", false, "Report failures as JSON on stderr ({\"error\", \"code\", \"exit\"})")
+
+	// Errors are reported by reportError so --json-errors can replace the text
+	rootCmd.SilenceErrors = true
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if jsonErrors {
+			cmd.SilenceUsage = true
+		}
+		return checkOutputFormat(cmd)
+	}
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return withCode(codeUsage, err)
+	})
+}
+
+// reportError writes err to w as text or, with --json-errors, as JSON, and
+// returns the exit code
+func reportError(w io.Writer, err error) int {
+	code := errorCode(err)
+	if jsonErrors {
+		data, _ := json.Marshal(struct {
+			Error string `json:"error"`
+			Code  string `json:"code"`
+			Exit  int    `json:"exit"`
+		}{err.Error(), code.Name, code.Exit})
+		fmt.Fprintln(w, string(data))
+	} else {
+		fmt.Fprintln(w, "Error:", err)
+	}
+	return code.Exit
+}
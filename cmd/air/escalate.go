@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultEscalateAfter is how long an agent may stay blocked before it is
+// escalated, when run.json doesn't say otherwise
+const defaultEscalateAfter = 30 * time.Minute
+
+// Escalation is an agent that has been blocked or stalled for longer than the
+// run's --escalate-after. Once notifications fire it is recorded under
+// channels/escalations/ so each blockage notifies once.
+type Escalation struct {
+	Agent     string     `json:"agent"`
+	Reason    string     `json:"reason"`
+	Since     time.Time  `json:"since"`
+	Notified  *time.Time `json:"notified,omitempty"`
+	Paused    []string   `json:"paused,omitempty"` // downstream plans held back (--pause-downstream)
+	Threshold string     `json:"threshold"`
+}
+
+// getEscalationsDir returns the directory holding fired escalations
+func getEscalationsDir() string {
+	return filepath.Join(getChannelsDir(), "escalations")
+}
+
+// escalationPolicy is the run's escalation settings from run.json
+type escalationPolicy struct {
+	after           time.Duration // 0 disables escalation
+	command         string
+	pauseDownstream bool
+}
+
+// readEscalationPolicy loads the policy recorded by 'air run'
+func readEscalationPolicy() escalationPolicy {
+	policy := escalationPolicy{after: defaultEscalateAfter}
+	record, _ := readRunRecord()
+	if record == nil {
+		return policy
+	}
+	if record.Flags.EscalateAfter != "" {
+		if d, err := time.ParseDuration(record.Flags.EscalateAfter); err == nil {
+			policy.after = d
+		}
+	}
+	policy.command = record.Flags.EscalateCmd
+	policy.pauseDownstream = record.Flags.PauseDownstream
+	return policy
+}
+
+// findEscalations returns the agents in report blocked for longer than after:
+// stopped or idle at a prompt, or holding a wait that timed out with fallback
+// fail or ask. Oldest blockage first.
+func findEscalations(report *statusReport, after time.Duration, now time.Time) []Escalation {
+	if after <= 0 {
+		return nil
+	}
+	blocked := make(map[string]Escalation)
+	note := func(agent, reason string, since time.Time) {
+		if now.Sub(since) < after {
+			return
+		}
+		if e, ok := blocked[agent]; ok && !since.Before(e.Since) {
+			return
+		}
+		blocked[agent] = Escalation{Agent: agent, Reason: reason, Since: since, Threshold: after.String()}
+	}
+
+	running := make(map[string]bool)
+	for _, a := range report.Agents {
+		if a.State == "done" || a.State == "failed" || a.Stopped != nil {
+			continue
+		}
+		running[a.Name] = true
+		if a.BlockedSince != nil {
+			note(a.Name, a.Attention, *a.BlockedSince)
+		}
+	}
+	for _, t := range report.Timeouts {
+		if running[t.Agent] && (t.Fallback == "fail" || t.Fallback == "ask") {
+			note(t.Agent, fmt.Sprintf("wait on '%s' timed out after %s (fallback: %s)", t.Channel, t.Timeout, t.Fallback), t.Timestamp)
+		}
+	}
+
+	var escalations []Escalation
+	for _, e := range blocked {
+		escalations = append(escalations, e)
+	}
+	sort.Slice(escalations, func(i, j int) bool { return escalations[i].Since.Before(escalations[j].Since) })
+	return escalations
+}
+
+// readEscalation returns an agent's fired escalation, or nil
+func readEscalation(agent string) *Escalation {
+	data, err := os.ReadFile(filepath.Join(getEscalationsDir(), agent+".json"))
+	if err != nil {
+		return nil
+	}
+	var e Escalation
+	if json.Unmarshal(data, &e) != nil {
+		return nil
+	}
+	return &e
+}
+
+// listEscalations returns all fired escalations
+func listEscalations() []Escalation {
+	entries, _ := os.ReadDir(getEscalationsDir())
+	var escalations []Escalation
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok {
+			if e := readEscalation(name); e != nil {
+				escalations = append(escalations, *e)
+			}
+		}
+	}
+	return escalations
+}
+
+// fireEscalations notifies once for each new escalation (tmux message and
+// bell, plus the run's --escalate-cmd), and clears records for agents that
+// are no longer blocked. Called by 'air status --watch'.
+func fireEscalations(current []Escalation, policy escalationPolicy, now time.Time) {
+	active := make(map[string]bool)
+	for _, e := range current {
+		active[e.Agent] = true
+		if readEscalation(e.Agent) != nil {
+			continue
+		}
+		notified := now.UTC()
+		e.Notified = &notified
+		if policy.pauseDownstream {
+			e.Paused = downstreamPlans(e.Agent)
+		}
+		if err := os.MkdirAll(getEscalationsDir(), 0755); err != nil {
+			continue
+		}
+		if data, err := json.MarshalIndent(e, "", "  "); err == nil {
+			os.WriteFile(filepath.Join(getEscalationsDir(), e.Agent+".json"), data, 0644)
+		}
+
+		notifyHuman(fmt.Sprintf("air: %s blocked for %s: %s", e.Agent, formatDuration(now.Sub(e.Since)), e.Reason))
+		if policy.command != "" {
+			cmd := exec.Command("sh", "-c", policy.command)
+			cmd.Env = append(os.Environ(),
+				"AIR_ESCALATION_AGENT="+e.Agent,
+				"AIR_ESCALATION_REASON="+e.Reason,
+				"AIR_ESCALATION_SINCE="+e.Since.Format(time.RFC3339),
+			)
+			cmd.Start()
+		}
+	}
+
+	// An agent that got unblocked can escalate again next time
+	for _, e := range listEscalations() {
+		if !active[e.Agent] {
+			os.Remove(filepath.Join(getEscalationsDir(), e.Agent+".json"))
+		}
+	}
+}
+
+// downstreamPlans returns the plans that transitively wait on channels
+// signaled by agent's plan
+func downstreamPlans(agent string) []string {
+	plans, err := loadAllPlanDependencies()
+	if err != nil {
+		return nil
+	}
+	return downstreamOf(agent, plans)
+}
+
+// downstreamOf returns the plans that transitively wait on agent's signals
+func downstreamOf(agent string, plans []PlanDependencies) []string {
+	signaledBy := make(map[string]string)
+	for _, p := range plans {
+		for _, ch := range p.Signals {
+			signaledBy[ch] = p.Name
+		}
+	}
+
+	blocked := map[string]bool{agent: true}
+	for changed := true; changed; {
+		changed = false
+		for _, p := range plans {
+			if blocked[p.Name] {
+				continue
+			}
+			for _, ch := range p.WaitsOn {
+				if blocked[signaledBy[ch]] {
+					blocked[p.Name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	var downstream []string
+	for _, p := range plans {
+		if p.Name != agent && blocked[p.Name] {
+			downstream = append(downstream, p.Name)
+		}
+	}
+	return downstream
+}
+
+// pausedPlans maps each plan held back by an escalation with
+// --pause-downstream to the escalated agent it waits on
+func pausedPlans() map[string]string {
+	paused := make(map[string]string)
+	for _, e := range listEscalations() {
+		for _, p := range e.Paused {
+			paused[p] = e.Agent
+		}
+	}
+	return paused
+}
+
+// skipPausedPlans drops plans held back by an escalation, saying why
+func skipPausedPlans(names []string) []string {
+	paused := pausedPlans()
+	var kept []string
+	for _, name := range names {
+		if agent, ok := paused[name]; ok {
+			fmt.Printf("⏸ %s paused: it waits on %s, which is escalated (unblock %s to release it)\n", name, agent, agent)
+			continue
+		}
+		kept = append(kept, name)
+	}
+	return kept
+}
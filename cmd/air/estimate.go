@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var planEstimateCmd = &cobra.Command{
+	Use:   "estimate [plan...]",
+	Short: "Estimate the size, time, and tokens of plans",
+	Long: `Sizes up each plan from the files under its **In scope:** paths (counted in
+its repository, or component in monorepo mode), rates it low, medium, or high
+complexity, and gives a rough agent time and token estimate for each plan and
+for the whole run.
+
+By default the rating comes from lines of code in scope. Pass --model (e.g.
+haiku) to have that model rate each plan instead, from the plan and its scope.
+The run's wall-clock time is the longest chain of plans waiting on each other's
+channels. Pass --price (USD per million tokens) to add an estimated cost.
+
+These are rough numbers for deciding how to split work, not a budget.`,
+	RunE: runPlanEstimate,
+}
+
+var estimateModel string
+var estimatePrice float64
+var estimateGroup string
+
+func init() {
+	planCmd.AddCommand(planEstimateCmd)
+	supportsOutput(planEstimateCmd)
+	planEstimateCmd.Flags().StringVar(&estimateModel, "model", "", "Have this model rate each plan's complexity (e.g. haiku)")
+	planEstimateCmd.Flags().Float64Var(&estimatePrice, "price", 0, "USD per million tokens, for estimated cost")
+	planEstimateCmd.Flags().StringVar(&estimateGroup, "group", "", "Estimate only the plans in this group")
+}
+
+// complexityCost is the rough agent time and tokens a plan of each complexity takes
+var complexityCost = map[string]struct {
+	time   time.Duration
+	tokens int64
+}{
+	"low":    {20 * time.Minute, 400_000},
+	"medium": {45 * time.Minute, 1_200_000},
+	"high":   {90 * time.Minute, 3_000_000},
+}
+
+// complexityRegex finds a rating in a model's reply
+var complexityRegex = regexp.MustCompile(`(?i)\b(low|medium|high)\b`)
+
+// planEstimate is one plan in 'air plan estimate'
+type planEstimate struct {
+	Plan       string  `json:"plan"`
+	Group      string  `json:"group,omitempty"`
+	Files      int     `json:"files"`
+	Lines      int     `json:"lines"`
+	Complexity string  `json:"complexity"`
+	RatedBy    string  `json:"rated_by"` // "size", or the model that rated it
+	Minutes    int     `json:"minutes"`
+	Tokens     int64   `json:"tokens"`
+	Cost       float64 `json:"cost,omitempty"`
+}
+
+// estimateReport is the output of 'air plan estimate'
+type estimateReport struct {
+	Plans        []planEstimate `json:"plans"`
+	AgentMinutes int            `json:"agent_minutes"`
+	WallMinutes  int            `json:"wall_minutes"` // the longest chain of waits
+	Tokens       int64          `json:"tokens"`
+	Cost         float64        `json:"cost,omitempty"`
+}
+
+func runPlanEstimate(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+	plans, err := loadAllPlanDependencies()
+	if err != nil {
+		return err
+	}
+	var selected []PlanDependencies
+	for _, p := range plans {
+		if (estimateGroup == "" || p.Group == estimateGroup) && (len(args) == 0 || contains(args, p.Name)) {
+			selected = append(selected, p)
+		}
+	}
+	for _, name := range args {
+		found := false
+		for _, p := range selected {
+			found = found || p.Name == name
+		}
+		if !found {
+			return errPlanNotFound(name)
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Println("No plans found.")
+		return nil
+	}
+
+	report := &estimateReport{}
+	minutes := make(map[string]int)
+	for _, p := range selected {
+		e := estimatePlan(info, p)
+		minutes[p.Group+"/"+p.Name] = e.Minutes
+		report.AgentMinutes += e.Minutes
+		report.Tokens += e.Tokens
+		report.Cost += e.Cost
+		report.Plans = append(report.Plans, e)
+	}
+	report.WallMinutes = longestWaitChain(selected, minutes)
+	return render(report, func() { printEstimate(report) })
+}
+
+// estimatePlan sizes p's scope and rates its complexity
+func estimatePlan(info *WorkspaceInfo, p PlanDependencies) planEstimate {
+	repoPath := info.Root
+	if info.Mode == ModeWorkspace && p.Repository != "" {
+		repoPath = info.repoDir(p.Repository)
+	}
+	e := planEstimate{Plan: p.Name, Group: p.Group, RatedBy: "size"}
+	e.Files, e.Lines = scopeSize(repoPath, p.InScope)
+	e.Complexity = complexityBySize(e.Lines)
+	if estimateModel != "" {
+		if rating, err := rateComplexity(p, e); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s couldn't rate %s, using its size: %v\n", estimateModel, p.Name, err)
+		} else {
+			e.Complexity, e.RatedBy = rating, estimateModel
+		}
+	}
+	cost := complexityCost[e.Complexity]
+	e.Minutes = int(cost.time.Minutes())
+	e.Tokens = cost.tokens
+	e.Cost = float64(e.Tokens) / 1_000_000 * estimatePrice
+	return e
+}
+
+// scopeSize counts the tracked files under paths in repoPath and their lines.
+// Binary files count as files but not lines; paths that don't exist yet (new
+// code) count as nothing.
+func scopeSize(repoPath string, paths []string) (files, lines int) {
+	if len(paths) == 0 {
+		return 0, 0
+	}
+	out, err := exec.Command("git", append([]string{"-C", repoPath, "ls-files", "-z", "--"}, paths...)...).Output()
+	if err != nil {
+		return 0, 0
+	}
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name == "" {
+			continue
+		}
+		files++
+		data, err := os.ReadFile(filepath.Join(repoPath, name))
+		if err != nil || bytes.IndexByte(data, 0) >= 0 {
+			continue
+		}
+		lines += bytes.Count(data, []byte("\n"))
+	}
+	return files, lines
+}
+
+// complexityBySize rates a plan by the lines of code in its scope
+func complexityBySize(lines int) string {
+	switch {
+	case lines < 1000:
+		return "low"
+	case lines < 5000:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// rateComplexity asks estimateModel to rate p as low, medium, or high
+func rateComplexity(p PlanDependencies, e planEstimate) (string, error) {
+	content, err := os.ReadFile(filepath.Join(getPlansDir(), p.Group, p.Name+".md"))
+	if err != nil {
+		return "", err
+	}
+	prompt := fmt.Sprintf(`Rate how complex this plan is for a coding agent to carry out: low, medium, or high.
+Its scope is %d files, %d lines of existing code. Reply with the one word only.
+
+%s`, e.Files, e.Lines, content)
+	out, err := exec.Command("claude", "-p", "--model", estimateModel, prompt).Output()
+	if err != nil {
+		return "", err
+	}
+	m := complexityRegex.FindStringSubmatch(string(out))
+	if m == nil {
+		return "", fmt.Errorf("unexpected reply %q", strings.TrimSpace(string(out)))
+	}
+	return strings.ToLower(m[1]), nil
+}
+
+// longestWaitChain returns the minutes of the longest chain of plans waiting
+// on channels signaled by other plans in the same group. Optional waits are
+// left out, as plans don't block on them for long.
+func longestWaitChain(plans []PlanDependencies, minutes map[string]int) int {
+	signaler := make(map[string]PlanDependencies) // group/channel -> plan
+	for _, p := range plans {
+		for _, ch := range p.Signals {
+			signaler[p.Group+"/"+ch] = p
+		}
+	}
+	finish := make(map[string]int)
+	visiting := make(map[string]bool)
+	var finishOf func(p PlanDependencies) int
+	finishOf = func(p PlanDependencies) int {
+		key := p.Group + "/" + p.Name
+		if f, ok := finish[key]; ok {
+			return f
+		}
+		if visiting[key] {
+			return 0 // a cycle; 'air plan validate' reports it
+		}
+		visiting[key] = true
+		start := 0
+		for _, ch := range p.WaitsOn {
+			if up, ok := signaler[p.Group+"/"+ch]; ok && !contains(p.Optional, ch) {
+				start = max(start, finishOf(up))
+			}
+		}
+		visiting[key] = false
+		finish[key] = start + minutes[key]
+		return finish[key]
+	}
+	longest := 0
+	for _, p := range plans {
+		longest = max(longest, finishOf(p))
+	}
+	return longest
+}
+
+// printEstimate prints an estimate report for humans
+func printEstimate(report *estimateReport) {
+	header := fmt.Sprintf("%-24s %6s %8s  %-10s %6s %8s", "PLAN", "FILES", "LINES", "COMPLEXITY", "TIME", "TOKENS")
+	if estimatePrice > 0 {
+		header += fmt.Sprintf(" %9s", "EST. COST")
+	}
+	fmt.Println(header)
+	for _, e := range report.Plans {
+		name := e.Plan
+		if e.Group != "" {
+			name = e.Group + "/" + e.Plan
+		}
+		complexity := e.Complexity
+		if e.RatedBy != "size" {
+			complexity += "*"
+		}
+		line := fmt.Sprintf("%-24s %6d %8d  %-10s %6s %8s", name, e.Files, e.Lines, complexity,
+			formatDuration(time.Duration(e.Minutes)*time.Minute), formatCount(e.Tokens))
+		if estimatePrice > 0 {
+			line += fmt.Sprintf(" %9s", fmt.Sprintf("$%.2f", e.Cost))
+		}
+		fmt.Println(line)
+	}
+
+	total := fmt.Sprintf("\nTotal: %d plans, ~%s of agent time, ~%s wall clock, ~%s tokens",
+		len(report.Plans), formatDuration(time.Duration(report.AgentMinutes)*time.Minute),
+		formatDuration(time.Duration(report.WallMinutes)*time.Minute), formatCount(report.Tokens))
+	if estimatePrice > 0 {
+		total += fmt.Sprintf(", ~$%.2f", report.Cost)
+	}
+	fmt.Println(total)
+	if estimateModel != "" {
+		fmt.Printf("* rated by %s; others by lines of code in scope\n", estimateModel)
+	}
+	fmt.Println("Rough estimates: wall clock is the longest chain of waits, assuming every plan runs at once.")
+}
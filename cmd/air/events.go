@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Event kinds recorded in events.jsonl
+const (
+	eventLaunched   = "launched"   // agent started by 'air run' / 'air launch'
+	eventDone       = "done"       // agent ran 'air agent done'
+	eventConflict   = "conflict"   // a merge of the agent's work conflicted
+	eventIntegrated = "integrated" // 'air integrate --auto' merged the agent's branch
+	eventFailed     = "failed"     // agent ran 'air agent fail'
+	eventStopped    = "stopped"    // 'air stop' ended the agent's session
+)
+
+// Event is one line of the project's event log. The log is append-only and
+// survives 'air clean', so it's the history 'air stats' summarizes.
+type Event struct {
+	Time    time.Time   `json:"time"`
+	Kind    string      `json:"kind"`
+	Agent   string      `json:"agent"`
+	Run     string      `json:"run,omitempty"`     // launch batch, e.g. 20261015-143000 (launched only)
+	Attempt int         `json:"attempt,omitempty"` // 1 for the first launch, 2 for the first retry... (launched only)
+	Repo    string      `json:"repo,omitempty"`    // workspace mode
+	Tokens  *tokenUsage `json:"tokens,omitempty"`  // transcript usage so far (done and stopped only)
+	With    []string    `json:"with,omitempty"`    // plans whose changes it conflicted with (conflict only)
+	Files   []string    `json:"files,omitempty"`   // conflicting files (conflict only)
+	Reason  string      `json:"reason,omitempty"`  // why the agent gave up (failed), or that it was killed (stopped)
+}
+
+// getEventsPath returns ~/.air/<project>/events.jsonl. Agents find it beside
+// their AIR_CHANNELS_DIR, since their working directory is a worktree.
+func getEventsPath() string {
+	if dir := os.Getenv("AIR_CHANNELS_DIR"); dir != "" {
+		return filepath.Join(filepath.Dir(dir), "events.jsonl")
+	}
+	return filepath.Join(mustGetAirDir(), "events.jsonl")
+}
+
+// recordEvent appends e to the event log, stamping the time if unset.
+// Best effort: history is never worth failing a command over.
+func recordEvent(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(getEventsPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(data, '\n'))
+}
+
+// readEvents returns the project's events in the order they were recorded,
+// skipping lines that don't parse
+func readEvents() ([]Event, error) {
+	return readEventsFile(getEventsPath())
+}
+
+// readEventsFile reads the event log at path, which may be another project's
+func readEventsFile(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			events = append(events, e)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// newRunID names a launch batch by its start time
+func newRunID(t time.Time) string {
+	return t.UTC().Format("20060102-150405")
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <plan>",
+	Short: "Show everything air would compute for a plan",
+	Long: `Prints what 'air run' would set up for a plan without creating or launching
+anything: the target repo or component, worktree path, branch and base, the
+environment its launch script exports, its dependencies and who satisfies them,
+the context files it gets, and the allowed tools and model Claude runs with.
+
+Takes the same --model, --no-auto-accept, and --channel-ttl flags as 'air run',
+so you can check how they change the launch.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplain,
+}
+
+func init() {
+	explainCmd.Flags().StringVar(&runModel, "model", "", "Claude model for the agent (default: claude's default)")
+	explainCmd.Flags().BoolVar(&noAutoAccept, "no-auto-accept", false, "Explain with auto-accept disabled")
+	explainCmd.Flags().DurationVar(&runChannelTTL, "channel-ttl", 0, "Explain with channel signals expiring after this duration")
+	supportsOutput(explainCmd)
+}
+
+// planExplanation is what 'air explain' reports for a plan
+type planExplanation struct {
+	Plan           string         `json:"plan"`
+	Group          string         `json:"group,omitempty"`
+	Mode           Mode           `json:"mode"`
+	Repo           string         `json:"repo,omitempty"`      // workspace mode
+	Component      string         `json:"component,omitempty"` // monorepo mode
+	RepoPath       string         `json:"repo_path"`
+	Worktree       string         `json:"worktree"`
+	WorktreeExists bool           `json:"worktree_exists"`
+	Branch         string         `json:"branch"`
+	Base           agentBase      `json:"base"`
+	BaseRecorded   bool           `json:"base_recorded"` // base.json exists, so the worktree keeps it
+	Waits          []explainWait  `json:"waits"`
+	Signals        []string       `json:"signals"`
+	Consumes       []string       `json:"consumes,omitempty"`
+	ContextFiles   []string       `json:"context_files"`
+	Env            []envVar       `json:"env"`
+	Model          string         `json:"model"`
+	PermissionMode string         `json:"permission_mode"`
+	AllowedTools   []string       `json:"allowed_tools"`
+	Problems       []string       `json:"problems"` // validation errors that would stop 'air run'
+	Paused         string         `json:"paused_by,omitempty"`
+	Launch         explainCommand `json:"launch"`
+}
+
+// explainWait is one channel the plan waits on
+type explainWait struct {
+	Channel  string `json:"channel"`
+	Optional bool   `json:"optional,omitempty"`
+	Producer string `json:"producer"` // signaling plan, or "external"
+	Repo     string `json:"repo,omitempty"`
+	Timeout  string `json:"timeout,omitempty"`
+	Fallback string `json:"fallback,omitempty"`
+}
+
+// explainCommand is where the agent's launch files go
+type explainCommand struct {
+	Script     string `json:"script"`
+	Context    string `json:"context"`
+	Assignment string `json:"assignment"`
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	if _, err := os.Stat(planPath(name)); err != nil {
+		return errPlanNotFound(name)
+	}
+
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+
+	// Explain even an invalid plan set: the problems are part of the answer
+	plans, validationErrs := ValidatePlansWithMode(info)
+	var pd PlanDependencies
+	for _, p := range plans {
+		if p.Name == name {
+			pd = p
+		}
+	}
+	if pd.Name == "" {
+		for _, err := range validationErrs {
+			fmt.Fprintf(os.Stderr, "✗ %s\n", err)
+		}
+		return fmt.Errorf("failed to read plan %s", name)
+	}
+
+	e := explainPlan(info, pd, plans)
+	for _, err := range validationErrs {
+		e.Problems = append(e.Problems, err.Error())
+	}
+	return render(e, func() { printExplanation(e) })
+}
+
+// explainPlan computes what 'air run' would set up for pd, without side effects
+func explainPlan(info *WorkspaceInfo, pd PlanDependencies, plans []PlanDependencies) *planExplanation {
+	repoName, repoPath, wtPath := agentPaths(info, pd)
+	e := &planExplanation{
+		Plan:         pd.Name,
+		Group:        pd.Group,
+		Mode:         info.Mode,
+		Repo:         repoName,
+		Component:    pd.Component,
+		RepoPath:     repoPath,
+		Worktree:     wtPath,
+		Branch:       "air/" + pd.Name,
+		Waits:        []explainWait{},
+		Signals:      append([]string{}, pd.Signals...),
+		ContextFiles: []string{getContextPath()},
+		Model:        runModel,
+		AllowedTools: projectAllowedTools(),
+		Problems:     []string{},
+		Paused:       pausedPlans()[pd.Name],
+	}
+	if _, err := os.Stat(wtPath); err == nil {
+		e.WorktreeExists = true
+	}
+
+	// Existing worktrees keep the base they were created from
+	if recorded := readAgentBase(pd.Name); recorded != nil {
+		e.Base, e.BaseRecorded = *recorded, true
+	} else {
+		e.Base = resolveAgentBase(repoPath, info.baseBranch(repoName))
+	}
+
+	producers := make(map[string]PlanDependencies)
+	for _, p := range plansInGroup(plans, pd.Group) {
+		for _, ch := range p.Signals {
+			producers[ch] = p
+		}
+	}
+	for _, ch := range append(append([]string{}, pd.WaitsOn...), pd.Optional...) {
+		w := explainWait{Channel: ch, Optional: contains(pd.Optional, ch), Producer: "external"}
+		if p, ok := producers[ch]; ok {
+			w.Producer, w.Repo = p.Name, p.Repository
+		} else if !contains(pd.External, ch) {
+			w.Producer = "nobody"
+		}
+		if policy, ok := pd.waitPolicyFor(ch); ok {
+			w.Timeout, w.Fallback = policy.Timeout, policy.Fallback
+		}
+		e.Waits = append(e.Waits, w)
+	}
+	for _, c := range pd.Consumes {
+		e.Consumes = append(e.Consumes, c.String())
+	}
+	e.ContextFiles = append(e.ContextFiles, agentContextFiles(pd, repoName)...)
+
+	var base *agentBase
+	if e.Base.SHA != "" {
+		base = &e.Base
+	}
+	e.Env = agentEnv(info, pd, base)
+
+	e.PermissionMode = "acceptEdits"
+	if noAutoAccept {
+		e.PermissionMode = "default (asks before edits)"
+	}
+	if e.Model == "" {
+		e.Model = "default"
+	}
+
+	agentDir := filepath.Join(getAgentsDir(), pd.Name)
+	e.Launch = explainCommand{
+		Script:     filepath.Join(agentDir, "launch.sh"),
+		Context:    filepath.Join(agentDir, "context"),
+		Assignment: filepath.Join(agentDir, "assignment"),
+	}
+	return e
+}
+
+// printExplanation prints an explanation for humans
+func printExplanation(e *planExplanation) {
+	if e.Group != "" {
+		fmt.Printf("Plan: %s (group %s, %s mode)\n\n", e.Plan, e.Group, e.Mode)
+	} else {
+		fmt.Printf("Plan: %s (%s mode)\n\n", e.Plan, e.Mode)
+	}
+
+	fmt.Println("Target")
+	switch {
+	case e.Repo != "":
+		fmt.Printf("  repo:      %s (%s)\n", e.Repo, e.RepoPath)
+	case e.Component != "":
+		fmt.Printf("  component: %s in %s\n", e.Component, e.RepoPath)
+	default:
+		fmt.Printf("  repo:      %s\n", e.RepoPath)
+	}
+	exists := ""
+	if e.WorktreeExists {
+		exists = " (exists)"
+	}
+	fmt.Printf("  worktree:  %s%s\n", e.Worktree, exists)
+	fmt.Printf("  branch:    %s\n", e.Branch)
+	base := fmt.Sprintf("%s@%s", e.Base.Branch, shortRef(e.Base.SHA))
+	if e.BaseRecorded {
+		base += " (recorded when the worktree was created)"
+	}
+	fmt.Printf("  base:      %s\n", base)
+
+	fmt.Println("\nDependencies")
+	if len(e.Waits) == 0 && len(e.Signals) == 0 {
+		fmt.Println("  none")
+	}
+	for _, w := range e.Waits {
+		label := "waits on"
+		if w.Optional {
+			label = "optional"
+		}
+		from := w.Producer
+		if w.Repo != "" {
+			from += " [" + w.Repo + "]"
+		}
+		line := fmt.Sprintf("  %-9s %s (from %s)", label+":", w.Channel, from)
+		if w.Timeout != "" {
+			line += fmt.Sprintf(", timeout %s then %s", w.Timeout, w.Fallback)
+		}
+		fmt.Println(line)
+	}
+	for _, ch := range e.Signals {
+		fmt.Printf("  signals:  %s\n", ch)
+	}
+	for _, c := range e.Consumes {
+		fmt.Printf("  consumes: %s\n", c)
+	}
+
+	fmt.Println("\nContext files")
+	for _, f := range e.ContextFiles {
+		fmt.Printf("  %s\n", f)
+	}
+
+	fmt.Println("\nEnvironment")
+	for _, v := range e.Env {
+		fmt.Printf("  %s=%s\n", v.Name, v.Value)
+	}
+
+	fmt.Println("\nClaude")
+	fmt.Printf("  model:         %s\n", e.Model)
+	fmt.Printf("  permissions:   %s\n", e.PermissionMode)
+	fmt.Printf("  allowed tools: %s\n", strings.Join(e.AllowedTools, " "))
+	fmt.Printf("  launch script: %s\n", e.Launch.Script)
+
+	if e.Paused != "" {
+		fmt.Printf("\n⏸ Paused: waits on %s, which is escalated\n", e.Paused)
+	}
+	if len(e.Problems) > 0 {
+		fmt.Println("\nProblems ('air run' would refuse to start)")
+		for _, p := range e.Problems {
+			fmt.Printf("  ✗ %s\n", p)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Bundle project state for another machine",
+	Long: `Archives the project's state like 'air backup'. With --portable, absolute
+paths in run.json, channel payloads, launch scripts, and other stored state are
+replaced with placeholders for this project's air directory, root, workspace
+repos, and home directory, so 'air import' can point them at wherever those
+live on the other machine.
+
+The archive defaults to air-<project>-<timestamp>.tar.gz in the current
+directory.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExport,
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Unpack an 'air export' archive, remapping its paths to this machine",
+	Long: `Unpacks an 'air export' (or 'air backup') archive into this project's
+~/.air/<project>/, replacing the placeholders of a portable export with this
+machine's paths. Run it from the project root (or workspace root) on the new
+machine. Workspace repos are matched by name; a repo that isn't found here is
+assumed to be at <root>/<name>.
+
+If the project already has state, --force is required. Worktrees aren't part of
+the archive: recreate them with 'air run'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+var exportPortable bool
+var importForce bool
+
+func init() {
+	exportCmd.Flags().BoolVar(&exportPortable, "portable", false, "Replace absolute paths with placeholders 'air import' remaps")
+	importCmd.Flags().BoolVar(&importForce, "force", false, "Overwrite existing project state")
+}
+
+// repoPlaceholder matches the placeholder of a workspace repo's path
+var repoPlaceholder = regexp.MustCompile(`\{\{AIR_REPO:([^}]+)\}\}`)
+
+// portablePaths maps each placeholder of a portable export to its absolute
+// path on this machine
+func portablePaths(info *WorkspaceInfo, airDir string) map[string]string {
+	paths := map[string]string{"{{AIR_DIR}}": airDir, "{{AIR_ROOT}}": info.Root}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths["{{HOME}}"] = home
+	}
+	for name, r := range info.RepoConfigs {
+		paths["{{AIR_REPO:"+name+"}}"] = r.Path
+	}
+	for placeholder, path := range paths {
+		if path == "" || path == string(filepath.Separator) {
+			delete(paths, placeholder)
+		}
+	}
+	return paths
+}
+
+// replacer returns a function that replaces each key of replacements with its
+// value in text, longest key first so nested paths win. A key is only replaced
+// where it ends, so /home/u/proj leaves /home/u/proj2 alone. Binary files (with
+// a NUL byte) are left alone.
+func replacer(replacements map[string]string) func([]byte) []byte {
+	var olds []string
+	for old := range replacements {
+		olds = append(olds, old)
+	}
+	sort.Slice(olds, func(i, j int) bool { return len(olds[i]) > len(olds[j]) })
+	return func(data []byte) []byte {
+		if bytes.IndexByte(data, 0) >= 0 {
+			return data
+		}
+		for _, old := range olds {
+			data = replaceWhole(data, []byte(old), []byte(replacements[old]))
+		}
+		return data
+	}
+}
+
+// replaceWhole replaces each occurrence of old in data with new, unless the
+// byte after it continues a file name
+func replaceWhole(data, old, new []byte) []byte {
+	var out bytes.Buffer
+	for {
+		i := bytes.Index(data, old)
+		if i < 0 {
+			break
+		}
+		end := i + len(old)
+		out.Write(data[:i])
+		if end < len(data) && continuesName(data[end]) {
+			out.Write(old)
+		} else {
+			out.Write(new)
+		}
+		data = data[end:]
+	}
+	out.Write(data)
+	return out.Bytes()
+}
+
+// continuesName reports whether b can be part of a file name rather than
+// end one, as '/', '"', whitespace, and the end of the text do
+func continuesName(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' ||
+		b == '.' || b == '-' || b == '_' || b == '+' || b == '~' || b >= 0x80
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+	airDir := mustGetAirDir()
+	project := filepath.Base(airDir)
+	now := time.Now()
+
+	path := fmt.Sprintf("air-%s-%s.tar.gz", project, now.Format("20060102-150405"))
+	if len(args) > 0 {
+		path = args[0]
+	}
+	manifest := backupManifest{Project: project, Created: now.UTC(), Version: version}
+	var rewrite func([]byte) []byte
+	if exportPortable {
+		manifest.Paths = portablePaths(info, airDir)
+		toPlaceholders := make(map[string]string)
+		for placeholder, p := range manifest.Paths {
+			toPlaceholders[p] = placeholder
+		}
+		rewrite = replacer(toPlaceholders)
+	}
+	files, err := writeBackup(path, airDir, manifest, rewrite)
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	fmt.Printf("Exported %d files from %s to %s\n", files, airDir, path)
+	if exportPortable {
+		fmt.Println("Paths are portable: run 'air import' from the project root on the other machine.")
+	}
+	return nil
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+	airDir, err := getAirDir()
+	if err != nil {
+		return fmt.Errorf("failed to determine project: %w", err)
+	}
+	if entries, _ := os.ReadDir(airDir); len(entries) > 0 && !importForce {
+		return withCode(codeUsage, fmt.Errorf("%s already has project state; rerun with --force to overwrite it with the import", airDir))
+	}
+
+	// Repos missing here fall back to <root>/<name>
+	paths := portablePaths(info, airDir)
+	var missing []string
+	known := replacer(paths)
+	rewrite := func(data []byte) []byte {
+		data = known(data)
+		return repoPlaceholder.ReplaceAllFunc(data, func(m []byte) []byte {
+			name := string(repoPlaceholder.FindSubmatch(m)[1])
+			if !contains(missing, name) {
+				missing = append(missing, name)
+			}
+			return []byte(filepath.Join(info.Root, name))
+		})
+	}
+
+	manifest, files, err := readBackup(args[0], airDir, rewrite)
+	if err != nil {
+		return err
+	}
+	from := ""
+	if manifest != nil {
+		from = fmt.Sprintf(" (export of %s from %s)", manifest.Project, manifest.Created.Local().Format(time.RFC822))
+	}
+	fmt.Printf("Imported %d files to %s%s\n", files, airDir, from)
+	if manifest != nil && len(manifest.Paths) > 0 {
+		fmt.Printf("Remapped paths: %s is now %s\n", manifest.Paths["{{AIR_ROOT}}"], info.Root)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		fmt.Printf("Warning: repos not found in this workspace, assumed under %s: %s\n", info.Root, strings.Join(missing, ", "))
+	}
+	fmt.Println("Worktrees aren't part of an export; 'air run' recreates them from the branches.")
+	return nil
+}
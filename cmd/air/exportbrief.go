@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var exportBriefCmd = &cobra.Command{
+	Use:   "export-brief <plan> [file]",
+	Short: "Write a plan's full agent briefing to one file",
+	Long: `Assembles the briefing 'air run' gives the plan's agent — the system prompt
+(context.md plus the plan's repo or component and context files) and the
+assignment (the plan, its coordination steps, and commit instructions) — into
+one Markdown document, so the same briefing can be handed to another assistant
+or attached to a ticket.
+
+Secrets are masked as in the agent's own files. The document goes to stdout,
+or to file if given. Nothing under the agent's directory is written.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runExportBrief,
+}
+
+func runExportBrief(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	if _, err := os.Stat(planPath(name)); err != nil {
+		return errPlanNotFound(name)
+	}
+
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+
+	// The coordination steps depend on the whole plan set, so it must be valid
+	plans, validationErrs := ValidatePlansWithMode(info)
+	if len(validationErrs) > 0 {
+		fmt.Fprintln(os.Stderr, "Dependency validation failed:")
+		for _, err := range validationErrs {
+			fmt.Fprintf(os.Stderr, "  ✗ %s\n", err)
+		}
+		return withCode(codeValidationFailed, fmt.Errorf("invalid dependency graph"))
+	}
+	var pd PlanDependencies
+	for _, p := range plans {
+		if p.Name == name {
+			pd = p
+		}
+	}
+
+	contextContent, err := os.ReadFile(getContextPath())
+	if err != nil {
+		return fmt.Errorf("failed to read context: %w", err)
+	}
+	agentContext, assignment, err := assembleBriefing(info, pd, plans, contextContent)
+	if err != nil {
+		return err
+	}
+
+	// The briefing is meant to leave the machine, so mask what the agent's files would
+	patterns := loadSecretPatterns(info.Root)
+	agentContext, n := redactSecrets(agentContext, patterns)
+	assignment, m := redactSecrets(assignment, patterns)
+	if n+m > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: masked %d secret(s) in %s's briefing; pass secrets through the environment, not plans or context\n", n+m, name)
+	}
+
+	doc := fmt.Sprintf("# Briefing: %s\n\n## System prompt\n\n%s\n\n## Assignment\n\n%s\n", name, agentContext, assignment)
+	if len(args) == 1 {
+		fmt.Print(doc)
+		return nil
+	}
+	if err := os.WriteFile(args[1], []byte(doc), 0644); err != nil {
+		return fmt.Errorf("failed to write briefing: %w", err)
+	}
+	fmt.Printf("Wrote %s's briefing to %s\n", name, args[1])
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var agentFailCmd = &cobra.Command{
+	Use:   "fail",
+	Short: "Signal that this agent has given up",
+	Long: `Marks the agent as failed by writing failed/<agent-id>, with --reason saying
+why. Failure is terminal, like done: 'air status' shows the agent as failed,
+pipelines stop instead of waiting for it, and agents waiting on a channel its
+plan would have signaled stop waiting and are told their producer failed
+(or proceed without it, with --on-timeout proceed).
+
+The agent's diff, transcript, tmux pane, and the channel state are saved to
+agents/<agent-id>/failures/<timestamp>/, which 'air clean' keeps.
+
+Relaunching the plan with 'air run' clears the failure.`,
+	Args: cobra.NoArgs,
+	RunE: runAgentFail,
+}
+
+var failReason string
+
+func init() {
+	agentCmd.AddCommand(agentFailCmd)
+	agentFailCmd.Flags().StringVar(&failReason, "reason", "", "Why the agent can't finish (required)")
+}
+
+// getFailedDir returns the directory holding failure markers
+func getFailedDir() string {
+	return filepath.Join(getChannelsDir(), "failed")
+}
+
+// readFailure returns agent's failure marker, or nil if it hasn't failed
+func readFailure(agent string) *ChannelPayload {
+	payload, err := readChannel("failed/" + agent)
+	if err != nil {
+		return nil
+	}
+	return payload
+}
+
+// readFailures returns every failure marker, keyed by agent
+func readFailures() map[string]*ChannelPayload {
+	failures := make(map[string]*ChannelPayload)
+	entries, _ := os.ReadDir(getFailedDir())
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+			if f := readFailure(name); f != nil {
+				failures[name] = f
+			}
+		}
+	}
+	return failures
+}
+
+// failedProducer returns the failure marker of the agent that owed channel,
+// or nil if no failed agent did
+func failedProducer(channel string) *ChannelPayload {
+	for _, f := range readFailures() {
+		if contains(f.Unsignaled, channel) {
+			return f
+		}
+	}
+	return nil
+}
+
+func runAgentFail(cmd *cobra.Command, args []string) error {
+	agentID := os.Getenv("AIR_AGENT_ID")
+	if agentID == "" {
+		return fmt.Errorf("AIR_AGENT_ID environment variable is required")
+	}
+	reason := strings.TrimSpace(failReason)
+	if reason == "" {
+		return withCode(codeUsage, fmt.Errorf("--reason is required"))
+	}
+	if channelExists("done/" + agentID) {
+		return fmt.Errorf("agent '%s' is already done", agentID)
+	}
+
+	worktree := os.Getenv("AIR_WORKTREE")
+	if worktree == "" {
+		worktree, _ = os.Getwd()
+	}
+	// The branch may be in any state when an agent gives up, so don't insist on it
+	sha, _ := getCurrentSHA()
+	branch, _ := getCurrentBranch()
+	payload := &ChannelPayload{
+		SHA:        sha,
+		Branch:     branch,
+		Worktree:   worktree,
+		Agent:      agentID,
+		Repo:       os.Getenv("AIR_REPO"),
+		Workspace:  os.Getenv("AIR_WORKSPACE"),
+		Timestamp:  time.Now().UTC(),
+		Message:    reason,
+		Unsignaled: owedChannels(agentID),
+	}
+	if err := writeChannel("failed/"+agentID, payload); err != nil {
+		return err
+	}
+	recordEvent(Event{Kind: eventFailed, Agent: agentID, Repo: payload.Repo, Reason: reason})
+	if err := markRunAgents([]string{agentID}, agentFailed, "", payload.Timestamp); err != nil {
+		fmt.Printf("Warning: failed to update run.json: %v\n", err)
+	}
+	notifyHuman(fmt.Sprintf("air: %s failed: %s", agentID, reason))
+
+	fmt.Printf("Marked %s as failed: %s\n", agentID, reason)
+	if dir, err := snapshotFailure(agentID, worktree, payload); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	} else {
+		fmt.Printf("Saved its work, transcript, and channel state to %s\n", dir)
+	}
+	if len(payload.Unsignaled) > 0 {
+		fmt.Printf("Agents waiting on %s will be told it won't be signaled.\n", strings.Join(payload.Unsignaled, ", "))
+	}
+	return nil
+}
+
+// owedChannels returns the channels agent's plan signals that haven't been
+// signaled yet
+func owedChannels(agent string) []string {
+	plans, err := loadAllPlanDependencies()
+	if err != nil {
+		return nil
+	}
+	var owed []string
+	for _, p := range plans {
+		if p.Name != agent {
+			continue
+		}
+		for _, ch := range p.Signals {
+			if !channelExists(ch) {
+				owed = append(owed, ch)
+			}
+		}
+	}
+	return owed
+}
+
+// clearFailure removes agent's failure marker, so a relaunch starts over
+func clearFailure(agent string) {
+	os.Remove(filepath.Join(getFailedDir(), agent+".json"))
+}
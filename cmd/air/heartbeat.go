@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var agentHeartbeatCmd = &cobra.Command{
+	Use:   "heartbeat",
+	Short: "Record that this agent is alive",
+	Long: `Writes the current time to agents/<name>/heartbeat.json. Launch scripts run
+this every 30 seconds for as long as Claude is running, so 'air status' can tell
+a quiet agent from one whose process has exited, even without tmux. The
+first heartbeat of a launch records --pid in run.json. If the agent's session
+has ended in a rate limit error, it also pauses the launch of new agents
+across projects (see 'air slots').`,
+	Args: cobra.NoArgs,
+	RunE: runAgentHeartbeat,
+}
+
+// heartbeatInterval is how often launch scripts emit a heartbeat. An agent is
+// considered stopped after missing three.
+const heartbeatInterval = 30 * time.Second
+
+var heartbeatPID int
+
+func init() {
+	agentCmd.AddCommand(agentHeartbeatCmd)
+	agentHeartbeatCmd.Flags().IntVar(&heartbeatPID, "pid", 0, "Process the heartbeat stands for (checked for liveness)")
+}
+
+// Heartbeat is written to agents/<name>/heartbeat.json
+type Heartbeat struct {
+	Time time.Time `json:"time"`
+	PID  int       `json:"pid,omitempty"`
+}
+
+// getAgentDir returns an agent's data directory. Agents use AIR_AGENT_DIR, or
+// find it beside their AIR_CHANNELS_DIR for launch scripts that predate it.
+func getAgentDir(name string) string {
+	if dir := os.Getenv("AIR_AGENT_DIR"); dir != "" && name == os.Getenv("AIR_AGENT_ID") {
+		return dir
+	}
+	if dir := os.Getenv("AIR_CHANNELS_DIR"); dir != "" {
+		return filepath.Join(filepath.Dir(dir), "agents", name)
+	}
+	return filepath.Join(getAgentsDir(), name)
+}
+
+func runAgentHeartbeat(cmd *cobra.Command, args []string) error {
+	agentID := os.Getenv("AIR_AGENT_ID")
+	if agentID == "" {
+		return fmt.Errorf("AIR_AGENT_ID environment variable is required")
+	}
+
+	data, err := json.Marshal(Heartbeat{Time: time.Now().UTC(), PID: heartbeatPID})
+	if err != nil {
+		return err
+	}
+	dir := getAgentDir(agentID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create agent directory: %w", err)
+	}
+	// Write then rename so readers never see a partial file
+	path := filepath.Join(dir, "heartbeat.json")
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write heartbeat: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+
+	if wt := os.Getenv("AIR_WORKTREE"); wt != "" {
+		project := filepath.Base(filepath.Dir(os.Getenv("AIR_CHANNELS_DIR")))
+		if err := noteRateLimit(project, agentID, wt); err != nil {
+			return fmt.Errorf("failed to record rate limit: %w", err)
+		}
+	}
+	if heartbeatPID > 0 {
+		if err := recordAgentPID(agentID, heartbeatPID); err != nil {
+			return fmt.Errorf("failed to record PID in run.json: %w", err)
+		}
+	}
+	return nil
+}
+
+// readHeartbeat returns an agent's last heartbeat, or nil if it never sent one
+func readHeartbeat(name string) *Heartbeat {
+	return readHeartbeatFile(filepath.Join(getAgentDir(name), "heartbeat.json"))
+}
+
+// readHeartbeatFile reads the heartbeat at path, or returns nil if there's none
+func readHeartbeatFile(path string) *Heartbeat {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var hb Heartbeat
+	if err := json.Unmarshal(data, &hb); err != nil {
+		return nil
+	}
+	return &hb
+}
+
+// agentAlive reports whether an agent's process is running. known is false
+// for agents that never sent a heartbeat (launched by hand or by an older air).
+func agentAlive(name string, now time.Time) (alive, known bool) {
+	return heartbeatAlive(readHeartbeat(name), now)
+}
+
+// heartbeatAlive is agentAlive for a heartbeat already read
+func heartbeatAlive(hb *Heartbeat, now time.Time) (alive, known bool) {
+	if hb == nil {
+		return false, false
+	}
+	if now.Sub(hb.Time) > 3*heartbeatInterval {
+		return false, true
+	}
+	if hb.PID > 0 && !processExists(hb.PID) {
+		return false, true
+	}
+	return true, true
+}
+
+// processExists reports whether a process with pid exists on this machine
+func processExists(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// Signal 0 checks for existence without delivering anything
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// agentAuthorPlaceholder in air.agentAuthor is replaced with the agent's name
+const agentAuthorPlaceholder = "{agent}"
+
+// authorRegex splits "Name <email>"
+var authorRegex = regexp.MustCompile(`^\s*(.+?)\s*<([^<>]+)>\s*$`)
+
+// agentAuthor returns the git identity the agent named name commits as in
+// repoPath, from 'git config air.agentAuthor "air:{agent} <air@noreply>"'.
+// ok is false if the repo doesn't set one, and the user's own identity is used.
+func agentAuthor(repoPath, name string) (author, email string, ok bool, err error) {
+	out, _ := exec.Command("git", "-C", repoPath, "config", "--get", "air.agentAuthor").Output()
+	value := strings.TrimSpace(string(out))
+	if value == "" {
+		return "", "", false, nil
+	}
+	m := authorRegex.FindStringSubmatch(strings.ReplaceAll(value, agentAuthorPlaceholder, name))
+	if m == nil {
+		return "", "", false, fmt.Errorf("air.agentAuthor must look like 'air:%s <air@noreply>' (got %q)", agentAuthorPlaceholder, value)
+	}
+	return m[1], m[2], true, nil
+}
+
+// agentAuthorEnv returns the GIT_AUTHOR_* and GIT_COMMITTER_* variables that
+// give the agent its identity, if the repo configures one. Set in launch.sh,
+// they reach only the agent's own git commands, leaving the repo's config,
+// the main checkout, and other agents alone.
+func agentAuthorEnv(repoPath, name string) []envVar {
+	author, email, ok, err := agentAuthor(repoPath, name)
+	if err != nil || !ok {
+		return nil
+	}
+	return []envVar{
+		{"GIT_AUTHOR_NAME", author}, {"GIT_AUTHOR_EMAIL", email},
+		{"GIT_COMMITTER_NAME", author}, {"GIT_COMMITTER_EMAIL", email},
+	}
+}
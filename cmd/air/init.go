@@ -1,8 +1,14 @@
 package main
 
 import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/scotro/air/cmd/air/prompts"
 	"github.com/spf13/cobra"
@@ -15,10 +21,71 @@ var initCmd = &cobra.Command{
 
 Supports two modes:
   - Single-repo mode: Run in a git repository
-  - Workspace mode: Run in a directory containing multiple git repos`,
+  - Workspace mode: Run in a directory containing multiple git repos
+
+In a workspace, --repos a,b limits air to those repos: they're listed in
+air.workspace.yaml, so planning, validation, and runs ignore the rest.
+
+With --analyze, scans the code for languages, build, test, and lint commands,
+and directory layout, and writes what it finds to a Project Facts section of
+context.md, so agents know how to build and test from the start. Rerunning it
+refreshes that section.
+
+For Go, Node.js/TypeScript, Python, and Rust projects, context.md also gets
+that stack's build, test, and lint conventions, and allowed-tools lists its
+commands so agents can run them without asking.
+
+context.md is stamped with the version of the template it was generated from.
+With --update-context, shows how it differs from the current template and offers
+to merge the template's changes into it, keeping local customizations.`,
 	RunE: runInit,
 }
 
+var initUpdateContext bool
+var initYes bool
+var initAnalyze bool
+var initRepos []string
+
+func init() {
+	initCmd.Flags().BoolVar(&initUpdateContext, "update-context", false, "Merge changes from the current context template into context.md")
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "Apply --update-context without asking")
+	initCmd.Flags().StringSliceVar(&initRepos, "repos", nil, "Workspace repos to orchestrate, comma-separated (recorded in air.workspace.yaml)")
+	initCmd.Flags().BoolVar(&initAnalyze, "analyze", false, "Scan the code and add its build and test commands to context.md")
+}
+
+// contextStampPrefix starts the first line of a generated context.md, which
+// records the template version it came from
+const contextStampPrefix = "<!-- air context template: "
+
+// contextTemplate returns the embedded agent context template for the mode
+func contextTemplate(info *WorkspaceInfo) string {
+	if info.Mode == ModeWorkspace {
+		return prompts.AgentContextWorkspace
+	}
+	return prompts.AgentContext
+}
+
+// templateVersion identifies a template by a short hash of its content
+func templateVersion(template string) string {
+	sum := sha256.Sum256([]byte(template))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// stampContext prefixes content with the version stamp of template
+func stampContext(template, content string) []byte {
+	return []byte(contextStampPrefix + templateVersion(template) + " -->\n" + content)
+}
+
+// readContextStamp splits a context.md into its template version (empty if
+// unstamped) and body
+func readContextStamp(content string) (version, body string) {
+	first, rest, _ := strings.Cut(content, "\n")
+	if v, ok := strings.CutPrefix(first, contextStampPrefix); ok {
+		return strings.TrimSuffix(v, " -->"), rest
+	}
+	return "", content
+}
+
 func runInit(cmd *cobra.Command, args []string) error {
 	// Detect mode based on directory structure
 	info, err := detectMode()
@@ -26,12 +93,37 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot initialize Air here: %w", err)
 	}
 
+	if initUpdateContext {
+		return runUpdateContext(info)
+	}
+
+	if len(initRepos) > 0 {
+		if info, err = selectManifestRepos(info, initRepos); err != nil {
+			return err
+		}
+	}
+
 	// Get air directory path
 	airDir, err := info.getAirDirForWorkspace()
 	if err != nil {
 		return fmt.Errorf("failed to determine air directory: %w", err)
 	}
 
+	// A repo that just opted into remote identity keeps the state it had under its directory name
+	if legacy := filepath.Join(filepath.Dir(airDir), dirProjectName(info.Root)); legacy != airDir {
+		if _, err := os.Stat(airDir); os.IsNotExist(err) {
+			// Worktrees can't move without breaking git's links to them
+			if entries, _ := os.ReadDir(filepath.Join(legacy, "worktrees")); len(entries) > 0 {
+				fmt.Printf("Not moving %s: it has worktrees. Unset air.identity, run 'air clean', then re-run 'air init'.\n", legacy)
+			} else if _, err := os.Stat(legacy); err == nil {
+				if err := os.Rename(legacy, airDir); err != nil {
+					return fmt.Errorf("failed to move %s to %s: %w", legacy, airDir, err)
+				}
+				fmt.Printf("Moved existing state from %s to %s\n", legacy, airDir)
+			}
+		}
+	}
+
 	// Check for collision (directory already exists for different project)
 	if _, err := os.Stat(airDir); err == nil {
 		fmt.Printf("Air directory already exists: %s\n", airDir)
@@ -46,24 +138,50 @@ func runInit(cmd *cobra.Command, args []string) error {
 	// Create context.md with appropriate template
 	contextPath := getContextPath()
 	if _, err := os.Stat(contextPath); os.IsNotExist(err) {
-		template := prompts.AgentContext
-		if info.Mode == ModeWorkspace {
-			template = prompts.AgentContextWorkspace
-		}
-		if err := os.WriteFile(contextPath, []byte(template), 0644); err != nil {
+		template := projectContextTemplate(info)
+		stacks := projectStacks(info)
+		if err := os.WriteFile(contextPath, stampContext(template, template), 0644); err != nil {
 			return fmt.Errorf("failed to create context.md: %w", err)
 		}
+		if err := os.WriteFile(getContextBasePath(), []byte(template), 0644); err != nil {
+			return fmt.Errorf("failed to save context template: %w", err)
+		}
+		if err := writeTemplateRecord(info); err != nil {
+			return fmt.Errorf("failed to record templates: %w", err)
+		}
+		if err := writeStackTools(stacks); err != nil {
+			return fmt.Errorf("failed to write allowed tools: %w", err)
+		}
 		fmt.Printf("Created %s\n", contextPath)
+		if len(stacks) > 0 {
+			fmt.Printf("Added %s conventions to context.md, and their commands to %s\n", strings.Join(stacks, ", "), getAllowedToolsPath())
+		}
 	} else {
 		fmt.Printf("context.md already exists at %s\n", contextPath)
 	}
 
+	if initAnalyze {
+		current, err := os.ReadFile(contextPath)
+		if err != nil {
+			return fmt.Errorf("failed to read context: %w", err)
+		}
+		if err := os.WriteFile(contextPath, []byte(withAnalysis(string(current), analyzeProject(info))), 0644); err != nil {
+			return fmt.Errorf("failed to write context.md: %w", err)
+		}
+		fmt.Println("Added Project Facts (languages, build and test commands, layout) to context.md")
+	}
+
 	// Print initialization summary
 	if info.Mode == ModeWorkspace {
 		fmt.Printf("\nInitialized Air workspace '%s' with %d repositories:\n", info.Name, len(info.Repos))
 		for _, repo := range info.Repos {
 			fmt.Printf("  - %s\n", repo)
 		}
+	} else if info.Mode == ModeMonorepo {
+		fmt.Printf("\nInitialized Air monorepo '%s' with %d components:\n", info.Name, len(info.Components))
+		for _, c := range info.Components {
+			fmt.Printf("  - %s\n", c)
+		}
 	} else {
 		fmt.Printf("\nInitialized Air workflow for '%s'.\n", info.Name)
 	}
@@ -80,3 +198,124 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runUpdateContext brings context.md up to date with the embedded template.
+// If the template context.md was generated from is known (context-base.md),
+// the template's changes are 3-way merged into the local copy; otherwise the
+// local copy is replaced, keeping a backup.
+func runUpdateContext(info *WorkspaceInfo) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+
+	contextPath := getContextPath()
+	current, err := os.ReadFile(contextPath)
+	if err != nil {
+		return fmt.Errorf("failed to read context: %w", err)
+	}
+	version, body := readContextStamp(string(current))
+
+	latest := projectContextTemplate(info)
+	latestVersion := templateVersion(latest)
+	if version == latestVersion {
+		fmt.Printf("context.md is up to date (template %s)\n", latestVersion)
+		return nil
+	}
+	if version == "" {
+		fmt.Printf("context.md has no template version (generated by an older air); current template is %s\n", latestVersion)
+	} else {
+		fmt.Printf("context.md was generated from template %s; current template is %s\n", version, latestVersion)
+	}
+
+	// Show what differs from the current template
+	latestFile, err := os.CreateTemp("", "air-context-*.md")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(latestFile.Name())
+	if _, err := latestFile.WriteString(latest); err != nil {
+		latestFile.Close()
+		return err
+	}
+	latestFile.Close()
+
+	fmt.Println()
+	diff := exec.Command("git", "diff", "--no-index", "--", contextPath, latestFile.Name())
+	diff.Stdout = os.Stdout
+	diff.Stderr = os.Stderr
+	diff.Run() // exits 1 when the files differ
+
+	// Merge the template's changes into the local copy when the base is known
+	merged := latest
+	conflicts := false
+	base, baseErr := os.ReadFile(getContextBasePath())
+	if baseErr == nil && version != "" && templateVersion(string(base)) == version {
+		merged, conflicts, err = mergeContext(body, string(base), latest)
+		if err != nil {
+			return err
+		}
+		fmt.Println("\nYour customizations will be kept; the template's changes are merged in.")
+	} else {
+		fmt.Println("\nThe template context.md came from is unknown, so it will be replaced (a backup is kept).")
+	}
+
+	if !initYes {
+		fmt.Print("\nUpdate context.md? [y/N] ")
+		reader := bufio.NewReader(os.Stdin)
+		response, _ := reader.ReadString('\n')
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Left context.md unchanged.")
+			return nil
+		}
+	}
+
+	backupPath := contextPath + ".bak"
+	if err := os.WriteFile(backupPath, current, 0644); err != nil {
+		return fmt.Errorf("failed to back up context.md: %w", err)
+	}
+	if err := os.WriteFile(contextPath, stampContext(latest, merged), 0644); err != nil {
+		return fmt.Errorf("failed to write context.md: %w", err)
+	}
+	if err := os.WriteFile(getContextBasePath(), []byte(latest), 0644); err != nil {
+		return fmt.Errorf("failed to save context template: %w", err)
+	}
+	if err := writeTemplateRecord(info); err != nil {
+		return fmt.Errorf("failed to record templates: %w", err)
+	}
+
+	fmt.Printf("Updated context.md to template %s (previous version saved to %s)\n", latestVersion, backupPath)
+	if conflicts {
+		fmt.Println("Warning: some of your changes conflict with the template; resolve the <<<<<<< markers with 'air context edit'")
+	}
+	return nil
+}
+
+// mergeContext 3-way merges the template change base -> latest into local.
+// Conflicting hunks are left with conflict markers.
+func mergeContext(local, base, latest string) (string, bool, error) {
+	dir, err := os.MkdirTemp("", "air-context-merge-")
+	if err != nil {
+		return "", false, err
+	}
+	defer os.RemoveAll(dir)
+
+	paths := make([]string, 3)
+	for i, content := range []string{local, base, latest} {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("%d.md", i))
+		if err := os.WriteFile(paths[i], []byte(content), 0644); err != nil {
+			return "", false, err
+		}
+	}
+
+	// git merge-file exits with the number of conflicts (negative on error)
+	cmd := exec.Command("git", "merge-file", "-p", "-L", "context.md", "-L", "old template", "-L", "new template", paths[0], paths[1], paths[2])
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return string(out), true, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to merge context: %w", err)
+	}
+	return string(out), false, nil
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/scotro/air/cmd/air/prompts"
 	"github.com/spf13/cobra"
@@ -13,13 +16,49 @@ import (
 var integrateCmd = &cobra.Command{
 	Use:   "integrate",
 	Short: "Start integration session to merge completed work",
-	RunE:  runIntegrate,
+	Long: `Launches Claude with integration context to guide merging completed agent branches.
+
+With --auto, skips Claude and merges completed branches directly: repos are
+ordered so upstream repos (those whose plans signal channels other repos wait
+on) merge first, and branches within a repo merge in dependency order.
+Each merge commit carries Air-Plan and Air-Run trailers naming the plan and
+run it came from. With --notes (or 'git config air.notes true'), the agent's
+done payload (summary, diffstat, changed files) is attached to it as a git
+note under refs/notes/air.
+
+With --record (or 'git config air.recordRuns' in the repo), a run whose
+branches all merge is marked in each repo by an annotated tag on the merged
+base branch listing its plans and their branches' commits: "ref" writes
+refs/air/runs/<id>, kept out of 'git tag' and clones; "tag" writes the tag
+air/runs/<id>.
+
+With --ci, only branches whose CI is green are merged: each branch must be
+pushed to origin, and the checks on its tip are read with gh (GitHub) or glab
+(GitLab), waiting up to --ci-timeout for running ones. Branches that fail, are
+still running, have no checks, or aren't pushed are held back.`,
+	RunE: runIntegrate,
+}
+
+var integrateAuto bool
+var integrateDryRun bool
+var integrateRecord string
+var integrateNotes bool
+var integrateCI bool
+var integrateCITimeout time.Duration
+
+func init() {
+	integrateCmd.Flags().BoolVar(&integrateAuto, "auto", false, "Merge completed branches in dependency order without launching Claude")
+	integrateCmd.Flags().BoolVar(&integrateDryRun, "dry-run", false, "With --auto, print the merge order without merging")
+	integrateCmd.Flags().BoolVar(&integrateNotes, "notes", false, "With --auto, attach each agent's done payload to its merge commit as a git note (refs/notes/air)")
+	integrateCmd.Flags().BoolVar(&integrateCI, "ci", false, "With --auto, merge only branches whose CI checks passed on origin")
+	integrateCmd.Flags().DurationVar(&integrateCITimeout, "ci-timeout", 30*time.Minute, "With --ci, how long to wait for running checks")
+	integrateCmd.Flags().StringVar(&integrateRecord, "record", "", "With --auto, mark the completed run in git: ref (refs/air/runs/<id>) or tag (air/runs/<id>)")
 }
 
 func runIntegrate(cmd *cobra.Command, args []string) error {
 	// Check initialization
 	if !isInitialized() {
-		return fmt.Errorf("not initialized (run 'air init' first)")
+		return errNotInitialized()
 	}
 
 	// Detect mode
@@ -28,6 +67,16 @@ func runIntegrate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to detect mode: %w", err)
 	}
 
+	if integrateRecord != "" && integrateRecord != "ref" && integrateRecord != "tag" {
+		return withCode(codeUsage, fmt.Errorf("invalid --record %q (want ref or tag)", integrateRecord))
+	}
+	if integrateRecord != "" && !integrateAuto {
+		return withCode(codeUsage, fmt.Errorf("--record only applies with --auto"))
+	}
+	if integrateAuto || integrateDryRun {
+		return runAutoIntegrate(info, integrateDryRun)
+	}
+
 	// Read context
 	context, err := os.ReadFile(getContextPath())
 	if err != nil {
@@ -41,6 +90,11 @@ func runIntegrate(cmd *cobra.Command, args []string) error {
 	} else {
 		integrationPrompt = string(context) + "\n\n" + prompts.Integration
 	}
+	integrationPrompt += buildBareRepoContext(info)
+	integrationPrompt += buildCompletionSummaryContext(readDoneSummaries())
+	integrationPrompt += buildReadinessContext(info)
+	// Merge commits carry the run's ID in their Air-Run trailer
+	integrationPrompt = strings.ReplaceAll(integrationPrompt, "<run-id>", currentRunID())
 
 	// Launch claude with initial prompt
 	claudeCmd := buildIntegrateCommand(integrationPrompt, info)
@@ -68,6 +122,51 @@ func buildIntegrateCommand(integrationPrompt string, info *WorkspaceInfo) *exec.
 		initialPrompt)
 }
 
+// buildBareRepoContext tells the integrator where to merge for bare
+// repositories, which have no working tree of their own
+func buildBareRepoContext(info *WorkspaceInfo) string {
+	repos := []string{""}
+	if info.Mode == ModeWorkspace {
+		repos = info.Repos
+	}
+	var lines []string
+	for _, repo := range repos {
+		repoPath := info.repoDir(repo)
+		if !isBareRepo(repoPath) {
+			continue
+		}
+		if dir, err := checkoutFor(repoPath, info.baseBranch(repo)); err == nil {
+			lines = append(lines, fmt.Sprintf("- `%s` is a bare repository: run merges in its worktree at `%s`", repoPath, dir))
+		} else {
+			lines = append(lines, fmt.Sprintf("- `%s` is a bare repository with no worktree on its base branch: ask the user to check one out before merging", repoPath))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\n\n## Bare Repositories\n\n" + strings.Join(lines, "\n") + "\n"
+}
+
+// buildCompletionSummaryContext lists the summaries agents left with 'air agent done'
+func buildCompletionSummaryContext(summaries map[string]string) string {
+	if len(summaries) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(summaries))
+	for name := range summaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("\n\n## Agent Completion Summaries\n\nEach agent described what it accomplished. Use these to explain each branch before merging.\n")
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("\n### %s\n\n%s\n", name, summaries[name]))
+	}
+	return sb.String()
+}
+
 // buildWorkspaceIntegrationContext generates integration instructions for workspace mode
 func buildWorkspaceIntegrationContext(info *WorkspaceInfo) string {
 	var sb strings.Builder
@@ -91,9 +190,7 @@ For each repository, check the agent branches:
 
 	for _, repo := range info.Repos {
 		sb.WriteString("- `cd ")
-		sb.WriteString(info.Root)
-		sb.WriteString("/")
-		sb.WriteString(repo)
+		sb.WriteString(info.repoDir(repo))
 		sb.WriteString(" && git branch | grep air/`\n")
 	}
 
@@ -127,7 +224,7 @@ For each repository in order:
 1. ` + "`cd <repo-path>`" + `
 2. For each branch targeting this repo (in dependency order):
    - Check for conflicts with merge-tree
-   - If clean: ` + "`git merge air/<name> --no-ff -m \"Merge <name>\"`" + `
+   - If clean: ` + "`git merge air/<name> --no-ff -m \"Merge <name>\" -m \"Air-Plan: <name>\" -m \"Air-Run: <run-id>\"`" + `
    - If conflicts: STOP and help resolve before continuing
 3. Move to next repo
 
@@ -145,10 +242,8 @@ Provide commands grouped by repository:
 		sb.WriteString("\n**")
 		sb.WriteString(repo)
 		sb.WriteString(":**\n```\ncd ")
-		sb.WriteString(info.Root)
-		sb.WriteString("/")
-		sb.WriteString(repo)
-		sb.WriteString("\ngit merge air/<plan-name> --no-ff -m \"Merge <plan-name>\"\n```\n")
+		sb.WriteString(info.repoDir(repo))
+		sb.WriteString("\ngit merge air/<plan-name> --no-ff -m \"Merge <plan-name>\" -m \"Air-Plan: <plan-name>\" -m \"Air-Run: <run-id>\"\n```\n")
 	}
 
 	sb.WriteString(`
@@ -157,9 +252,270 @@ Provide commands grouped by repository:
 If a merge has conflicts:
 1. Show which files conflict
 2. Help resolve them interactively
-3. After resolution: ` + "`git add <files>`" + ` then ` + "`git commit`" + `
+3. After resolution: ` + "`git add <files>`" + ` then ` + "`git commit`" + `, keeping the Air-Plan and Air-Run trailers in the message
 4. Continue with remaining branches in that repo, then move to next repo
 `)
 
 	return sb.String()
 }
+
+// integrationStep is one branch merge in the computed integration order
+type integrationStep struct {
+	plan     string
+	repoName string // empty in single/monorepo mode
+	repoPath string
+}
+
+// computeIntegrationOrder returns merge steps ordered repo-by-repo (upstream
+// repos first) and, within each repo, by channel dependencies between plans.
+func computeIntegrationOrder(plans []PlanDependencies, info *WorkspaceInfo) ([]integrationStep, error) {
+	// Plan-level graph: a plan depends on the signaler of every channel it waits on
+	signaled := make(map[string]string)
+	for _, p := range plans {
+		for _, ch := range p.Signals {
+			signaled[ch] = p.Name
+		}
+	}
+	planRepo := make(map[string]string)
+	var planNames []string
+	planDeps := make(map[string][]string)
+	for _, p := range plans {
+		planNames = append(planNames, p.Name)
+		if info.Mode == ModeWorkspace {
+			planRepo[p.Name] = p.Repository
+		}
+		for _, ch := range p.WaitsOn {
+			if signaler, ok := signaled[ch]; ok {
+				planDeps[p.Name] = append(planDeps[p.Name], signaler)
+			}
+		}
+	}
+
+	planOrder, err := topoSort(planNames, planDeps)
+	if err != nil {
+		return nil, fmt.Errorf("plans: %w", err)
+	}
+
+	// Repo-level graph: upstream repos merge before the repos that wait on them
+	repoOrder := []string{""}
+	if info.Mode == ModeWorkspace {
+		repoOrder, err = topoSort(info.Repos, repoDependencies(plans))
+		if err != nil {
+			return nil, fmt.Errorf("repositories: %w (integrate these repos manually)", err)
+		}
+	}
+
+	var steps []integrationStep
+	for _, repo := range repoOrder {
+		for _, plan := range planOrder {
+			if planRepo[plan] != repo {
+				continue
+			}
+			steps = append(steps, integrationStep{
+				plan:     plan,
+				repoName: repo,
+				repoPath: info.repoDir(repo),
+			})
+		}
+	}
+	return steps, nil
+}
+
+// topoSort orders nodes so each comes after everything in deps[node].
+// Ties keep the input order, making the result deterministic.
+func topoSort(nodes []string, deps map[string][]string) ([]string, error) {
+	done := make(map[string]bool)
+	var order []string
+	for len(order) < len(nodes) {
+		progressed := false
+		for _, n := range nodes {
+			if done[n] {
+				continue
+			}
+			ready := true
+			for _, d := range deps[n] {
+				if !done[d] && contains(nodes, d) {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[n] = true
+				order = append(order, n)
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			var remaining []string
+			for _, n := range nodes {
+				if !done[n] {
+					remaining = append(remaining, n)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle involving [%s]", strings.Join(remaining, ", "))
+		}
+	}
+	return order, nil
+}
+
+// runAutoIntegrate merges completed agent branches in computed order
+func runAutoIntegrate(info *WorkspaceInfo, dryRun bool) error {
+	plans, errs := ValidatePlansWithMode(info)
+	if len(errs) > 0 {
+		fmt.Println("Dependency validation failed:")
+		for _, err := range errs {
+			fmt.Printf("  ✗ %s\n", err)
+		}
+		return withCode(codeValidationFailed, fmt.Errorf("invalid dependency graph"))
+	}
+	if len(plans) == 0 {
+		fmt.Println("No plans found.")
+		return nil
+	}
+
+	steps, err := computeIntegrationOrder(plans, info)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Println("Merge order:")
+		for i, step := range steps {
+			fmt.Printf("  %d. %s\n", i+1, stepLabel(step))
+		}
+		return nil
+	}
+
+	doneDir := filepath.Join(getChannelsDir(), "done")
+	runID := currentRunID()
+	var merged, skipped int
+	var mergedPlans []string
+	// Plans merged into each checkout, for marking the run with --record
+	var checkouts []string
+	integrated := make(map[string][]string)
+	noteIntegrated := func(dir, plan string) {
+		if _, ok := integrated[dir]; !ok {
+			checkouts = append(checkouts, dir)
+		}
+		integrated[dir] = append(integrated[dir], plan)
+	}
+	currentRepo := "-"
+	for _, step := range steps {
+		if info.Mode == ModeWorkspace && step.repoName != currentRepo {
+			currentRepo = step.repoName
+			mergedPlans = nil
+			fmt.Printf("\n%s:\n", step.repoName)
+		}
+
+		branch := "air/" + step.plan
+		if exec.Command("git", "-C", step.repoPath, "rev-parse", "--verify", "--quiet", branch).Run() != nil {
+			fmt.Printf("  - %s (no branch, skipped)\n", step.plan)
+			skipped++
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(doneDir, step.plan+".json")); err != nil {
+			fmt.Printf("  - %s (not done, skipped)\n", step.plan)
+			skipped++
+			continue
+		}
+		// Bare repositories merge in the worktree that has their base branch checked out
+		dir, err := checkoutFor(step.repoPath, info.baseBranch(step.repoName))
+		if err != nil {
+			return err
+		}
+		if exec.Command("git", "-C", dir, "merge-base", "--is-ancestor", branch, "HEAD").Run() == nil {
+			fmt.Printf("  - %s (already merged)\n", step.plan)
+			recordEvent(Event{Kind: eventIntegrated, Agent: step.plan, Repo: step.repoName})
+			mergedPlans = append(mergedPlans, step.plan)
+			noteIntegrated(dir, step.plan)
+			continue
+		}
+		if integrateCI {
+			if state, detail := waitForCI(step.repoPath, branch, integrateCITimeout); state != ciPassed {
+				fmt.Printf("  - %s (held back: %s)\n", step.plan, detail)
+				skipped++
+				continue
+			}
+		}
+
+		statusOut, _ := exec.Command("git", "-C", dir, "status", "--porcelain", "--untracked-files=no").Output()
+		if strings.TrimSpace(string(statusOut)) != "" {
+			return fmt.Errorf("%s has uncommitted changes; commit or stash them before integrating", dir)
+		}
+
+		mergeCmd := exec.Command("git", "-C", dir, "merge", branch, "--no-ff", "-m", "Merge "+step.plan, "-m", mergeTrailers(step.plan, runID))
+		if out, err := mergeCmd.CombinedOutput(); err != nil {
+			if files := conflictedFiles(dir); len(files) > 0 {
+				recordEvent(Event{Kind: eventConflict, Agent: step.plan, Repo: step.repoName, With: plansTouching(mergedPlans, files), Files: files})
+			}
+			exec.Command("git", "-C", dir, "merge", "--abort").Run()
+			fmt.Printf("  ✗ %s (conflicts)\n%s", step.plan, out)
+			return withCode(codeMergeConflict, fmt.Errorf("merge of %s failed; resolve it manually, then rerun 'air integrate --auto' (merged branches are skipped)", stepLabel(step)))
+		}
+		fmt.Printf("  ✓ %s\n", step.plan)
+		if mergeNotesEnabled(dir) {
+			if err := addMergeNote(dir, filepath.Join(doneDir, step.plan+".json")); err != nil {
+				fmt.Printf("    Warning: %v\n", err)
+			}
+		}
+		recordEvent(Event{Kind: eventIntegrated, Agent: step.plan, Repo: step.repoName})
+		merged++
+		mergedPlans = append(mergedPlans, step.plan)
+		noteIntegrated(dir, step.plan)
+	}
+
+	fmt.Printf("\nMerged %d branch(es), skipped %d.\n", merged, skipped)
+	if skipped == 0 {
+		for _, dir := range checkouts {
+			kind := runRecordKind(dir)
+			if kind == "" {
+				continue
+			}
+			ref, err := markRunInGit(dir, kind, runID, integrated[dir])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Recorded run %s as %s in %s\n", runID, ref, dir)
+		}
+	}
+	if merged > 0 {
+		fmt.Println("Run your tests, then 'air clean' to remove worktrees.")
+	}
+	return nil
+}
+
+// stepLabel formats a merge step for display
+func stepLabel(step integrationStep) string {
+	if step.repoName != "" {
+		return fmt.Sprintf("air/%s [%s]", step.plan, step.repoName)
+	}
+	return "air/" + step.plan
+}
+
+// conflictedFiles lists the unmerged paths of an in-progress merge in dir
+func conflictedFiles(dir string) []string {
+	out, err := exec.Command("git", "-C", dir, "diff", "--name-only", "--diff-filter=U").Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(out))
+}
+
+// plansTouching returns the plans whose done payload lists any of files as changed
+func plansTouching(plans, files []string) []string {
+	var touching []string
+	for _, plan := range plans {
+		payload, err := readChannel("done/" + plan)
+		if err != nil {
+			continue
+		}
+		for _, f := range payload.ChangedFiles {
+			if contains(files, f) {
+				touching = append(touching, plan)
+				break
+			}
+		}
+	}
+	return touching
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var prepareCmd = &cobra.Command{
+	Use:   "prepare [plans...]",
+	Short: "Create worktrees and launch scripts without starting agents",
+	Long: `Does everything 'air run' does except start tmux and Claude: validates plans,
+creates worktrees and agent directories, and writes each agent's launch script.
+
+Inspect or seed the worktrees (fixtures, generated files, local config), then
+start the agents with 'air launch'. Takes the same arguments and flags as 'air run'.`,
+	RunE: runPrepare,
+}
+
+var launchCmd = &cobra.Command{
+	Use:   "launch [plans...]",
+	Short: "Start agents prepared with 'air prepare'",
+	Long: `Starts a tmux session with a window per prepared agent, running the launch
+script 'air prepare' wrote. With no arguments, launches every prepared agent.`,
+	RunE: runLaunch,
+}
+
+func init() {
+	addRunFlags(prepareCmd)
+	addAttachFlags(launchCmd)
+	launchCmd.Flags().BoolVar(&runForce, "force", false, "Replace a run that is still active in this project")
+}
+
+func runPrepare(cmd *cobra.Command, args []string) error {
+	prepareOnly = true
+	return runRun(cmd, args)
+}
+
+func runLaunch(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+
+	// Prepared agents have a worktree and a launch script
+	worktrees, _ := runWorktrees(info)
+	agentsDir := getAgentsDir()
+	prepared := make(map[string]worktreeInfo)
+	var names []string
+	for _, wt := range worktrees {
+		if _, err := os.Stat(filepath.Join(agentsDir, wt.name, "launch.sh")); err == nil {
+			prepared[wt.name] = wt
+			names = append(names, wt.name)
+		}
+	}
+
+	if len(args) > 0 {
+		for _, name := range args {
+			if _, ok := prepared[name]; !ok {
+				return fmt.Errorf("plan '%s' is not prepared (run 'air prepare %s' first)", name, name)
+			}
+		}
+		names = args
+	}
+	if len(names) == 0 {
+		fmt.Println("No prepared agents. Run 'air prepare <plans...>' first.")
+		return nil
+	}
+
+	if names = skipPausedPlans(names); len(names) == 0 {
+		return nil
+	}
+
+	var agents []worktreeInfo
+	for _, name := range names {
+		agents = append(agents, prepared[name])
+	}
+	if err := acquireRunLock("launch", runForce); err != nil {
+		return err
+	}
+	return launchAgents(info, agents)
+}
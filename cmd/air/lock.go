@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var agentLockCmd = &cobra.Command{
+	Use:   "lock <resource>",
+	Short: "Acquire a cooperative lock on a shared resource",
+	Long: `Blocks until the named lock is free, then takes it. Use this around edits to a
+genuinely shared file (e.g. a changelog or migration index) and release it
+with 'air agent unlock'.
+
+A lock is considered stale, and is taken over, if its holder has signaled done
+or it is older than --stale-after.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentLock,
+}
+
+var agentUnlockCmd = &cobra.Command{
+	Use:   "unlock <resource>",
+	Short: "Release a lock taken with 'air agent lock'",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAgentUnlock,
+}
+
+var lockStaleAfter time.Duration
+var lockTimeout time.Duration
+var unlockForce bool
+
+func init() {
+	agentCmd.AddCommand(agentLockCmd)
+	agentCmd.AddCommand(agentUnlockCmd)
+
+	agentLockCmd.Flags().DurationVar(&lockStaleAfter, "stale-after", 30*time.Minute, "Take over locks held longer than this")
+	agentLockCmd.Flags().DurationVar(&lockTimeout, "timeout", 0, "Give up after waiting this long (default: wait forever)")
+	agentUnlockCmd.Flags().BoolVar(&unlockForce, "force", false, "Release a lock held by another agent")
+}
+
+// LockInfo is written to a lock file by its holder
+type LockInfo struct {
+	Resource  string    `json:"resource"`
+	Agent     string    `json:"agent"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// getLocksDir returns the directory holding lock files
+func getLocksDir() string {
+	return filepath.Join(getChannelsDir(), "locks")
+}
+
+// getLockPath returns the lock file for a resource. Resources may be paths, so
+// the name is escaped into a single filename.
+func getLockPath(resource string) string {
+	return filepath.Join(getLocksDir(), url.PathEscape(resource)+".json")
+}
+
+// readLock reads a resource's lock file
+func readLock(resource string) (*LockInfo, error) {
+	data, err := os.ReadFile(getLockPath(resource))
+	if err != nil {
+		return nil, err
+	}
+	var lock LockInfo
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lock %s: %w", resource, err)
+	}
+	return &lock, nil
+}
+
+// tryLock atomically creates the lock file. Returns false if it is already held.
+func tryLock(resource, agentID string) (bool, error) {
+	if err := os.MkdirAll(getLocksDir(), 0755); err != nil {
+		return false, fmt.Errorf("failed to create locks directory: %w", err)
+	}
+
+	f, err := os.OpenFile(getLockPath(resource), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create lock file: %w", err)
+	}
+	defer f.Close()
+
+	data, err := json.MarshalIndent(LockInfo{Resource: resource, Agent: agentID, Timestamp: time.Now().UTC()}, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal lock: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return false, fmt.Errorf("failed to write lock file: %w", err)
+	}
+	return true, nil
+}
+
+// lockStaleReason explains why a held lock can be taken over ("" if it is live)
+func lockStaleReason(lock *LockInfo, staleAfter time.Duration, now time.Time) string {
+	if _, err := os.Stat(filepath.Join(getChannelsDir(), "done", lock.Agent+".json")); err == nil {
+		return fmt.Sprintf("holder '%s' is done", lock.Agent)
+	}
+	if readFailure(lock.Agent) != nil {
+		return fmt.Sprintf("holder '%s' failed", lock.Agent)
+	}
+	if staleAfter > 0 && now.Sub(lock.Timestamp) > staleAfter {
+		return fmt.Sprintf("held by '%s' for over %s", lock.Agent, staleAfter)
+	}
+	return ""
+}
+
+func runAgentLock(cmd *cobra.Command, args []string) error {
+	resource := args[0]
+
+	agentID := os.Getenv("AIR_AGENT_ID")
+	if agentID == "" {
+		return fmt.Errorf("AIR_AGENT_ID environment variable is required")
+	}
+
+	interval := pollInterval()
+	start := time.Now()
+	announced := false
+	for {
+		ok, err := tryLock(resource, agentID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			fmt.Printf("Acquired lock '%s'\n", resource)
+			return nil
+		}
+
+		data, err := os.ReadFile(getLockPath(resource))
+		if os.IsNotExist(err) {
+			// Released between our attempt and the read
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read lock: %w", err)
+		}
+
+		var lock LockInfo
+		var held, reason string
+		if err := json.Unmarshal(data, &lock); err != nil {
+			// Being written by a new holder, or left empty by one that died
+			// mid-write: only its age says which
+			held = "unreadable lock file"
+			if fi, err := os.Stat(getLockPath(resource)); err == nil && lockStaleAfter > 0 && time.Since(fi.ModTime()) > lockStaleAfter {
+				reason = fmt.Sprintf("unreadable for over %s", lockStaleAfter)
+			}
+		} else {
+			if lock.Agent == agentID {
+				fmt.Printf("Lock '%s' is already held by you\n", resource)
+				return nil
+			}
+			held = fmt.Sprintf("held by '%s'", lock.Agent)
+			reason = lockStaleReason(&lock, lockStaleAfter, time.Now())
+		}
+
+		if reason != "" && takeOverLock(resource, data) {
+			fmt.Printf("Taking over stale lock '%s' (%s)\n", resource, reason)
+			continue
+		}
+
+		if lockTimeout > 0 && time.Since(start) > lockTimeout {
+			return fmt.Errorf("timed out waiting for lock '%s' (%s)", resource, held)
+		}
+		if !announced {
+			fmt.Printf("Waiting for lock '%s' (%s)...\n", resource, held)
+			announced = true
+		}
+		time.Sleep(interval)
+	}
+}
+
+// takeOverLock removes a stale lock file, provided it's still the one read
+// as stale
+func takeOverLock(resource string, stale []byte) bool {
+	return breakStaleLock(getLockPath(resource), stale)
+}
+
+// breakStaleLock removes the lock file at path if it still holds the stale
+// contents read from it. Breakers take path.break first, so of several
+// processes breaking the same lock only one compares and removes it, and a
+// lock taken after that is never mistaken for the stale one.
+func breakStaleLock(path string, stale []byte) bool {
+	breaker := path + ".break"
+	f, err := os.OpenFile(breaker, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		// Another process is breaking it, or died doing so and left this behind
+		if fi, err := os.Stat(breaker); err == nil && time.Since(fi.ModTime()) > 5*time.Second {
+			os.Remove(breaker)
+		}
+		return false
+	}
+	f.Close()
+	defer os.Remove(breaker)
+
+	if data, err := os.ReadFile(path); err != nil || !bytes.Equal(data, stale) {
+		return false
+	}
+	return os.Remove(path) == nil
+}
+
+func runAgentUnlock(cmd *cobra.Command, args []string) error {
+	resource := args[0]
+
+	agentID := os.Getenv("AIR_AGENT_ID")
+	if agentID == "" && !unlockForce {
+		return fmt.Errorf("AIR_AGENT_ID environment variable is required")
+	}
+
+	lock, err := readLock(resource)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("lock '%s' is not held", resource)
+		}
+		return err
+	}
+	if lock.Agent != agentID && !unlockForce {
+		return fmt.Errorf("lock '%s' is held by '%s', not you (use --force to release it anyway)", resource, lock.Agent)
+	}
+
+	if err := os.Remove(getLockPath(resource)); err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	fmt.Printf("Released lock '%s'\n", resource)
+	return nil
+}
+
+// listLocks returns all held locks, sorted by resource
+func listLocks() []LockInfo {
+	entries, err := os.ReadDir(getLocksDir())
+	if err != nil {
+		return nil
+	}
+	var locks []LockInfo
+	for _, e := range entries {
+		// Skip locks being taken over
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(getLocksDir(), e.Name()))
+		if err != nil {
+			continue
+		}
+		var lock LockInfo
+		if err := json.Unmarshal(data, &lock); err != nil {
+			continue
+		}
+		locks = append(locks, lock)
+	}
+	sort.Slice(locks, func(i, j int) bool { return locks[i].Resource < locks[j].Resource })
+	return locks
+}
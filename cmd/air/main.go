@@ -5,7 +5,10 @@ import (
 )
 
 func main() {
+	if code, ok := runPlugin(os.Args[1:]); ok {
+		os.Exit(code)
+	}
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(reportError(os.Stderr, err))
 	}
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var agentSendCmd = &cobra.Command{
+	Use:   "send <agent> <message>",
+	Short: "Send a message to another agent",
+	Long: `Appends a message to another agent's inbox. The recipient reads it with
+'air agent inbox'. Use this for questions between agents instead of one-shot
+signal channels.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAgentSend,
+}
+
+var agentInboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "Read messages sent to this agent",
+	Long: `Prints and marks as read all unread messages in this agent's inbox.
+
+With --wait, blocks until at least one message arrives.`,
+	Args: cobra.NoArgs,
+	RunE: runAgentInbox,
+}
+
+var inboxWait bool
+
+func init() {
+	agentCmd.AddCommand(agentSendCmd)
+	agentCmd.AddCommand(agentInboxCmd)
+
+	agentInboxCmd.Flags().BoolVar(&inboxWait, "wait", false, "Block until a message arrives")
+}
+
+// inboxChannel returns the queue channel backing an agent's inbox
+func inboxChannel(agent string) string {
+	return "inbox/" + agent
+}
+
+// validateAgentName rejects names that would escape the inbox directory. Like
+// plan names, which agents are named after, they have no path separators.
+func validateAgentName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid agent name '%s'", name)
+	}
+	return nil
+}
+
+func runAgentSend(cmd *cobra.Command, args []string) error {
+	to, body := args[0], args[1]
+
+	// Messages from outside an agent (e.g. the orchestrator) come from "user"
+	from := os.Getenv("AIR_AGENT_ID")
+	if from == "" {
+		from = "user"
+	}
+	if to == from {
+		return fmt.Errorf("cannot send a message to yourself")
+	}
+	if err := validateAgentName(to); err != nil {
+		return err
+	}
+	if _, err := os.Stat(getAgentDir(to)); err != nil {
+		return fmt.Errorf("no agent named '%s' in this project", to)
+	}
+
+	msg := &ChannelPayload{
+		Agent:     from,
+		Repo:      os.Getenv("AIR_REPO"),
+		Workspace: os.Getenv("AIR_WORKSPACE"),
+		Timestamp: time.Now().UTC(),
+		Message:   body,
+	}
+	if err := enqueueChannel(inboxChannel(to), msg); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	fmt.Printf("Sent message to '%s'\n", to)
+	return nil
+}
+
+func runAgentInbox(cmd *cobra.Command, args []string) error {
+	agentID := os.Getenv("AIR_AGENT_ID")
+	if agentID == "" {
+		return fmt.Errorf("AIR_AGENT_ID environment variable is required")
+	}
+
+	if inboxWait {
+		fmt.Println("Waiting for messages...")
+	}
+
+	interval := pollInterval()
+	for {
+		var messages []*ChannelPayload
+		for {
+			msg, err := dequeueChannel(inboxChannel(agentID), agentID)
+			if err != nil {
+				return err
+			}
+			if msg == nil {
+				break
+			}
+			messages = append(messages, msg)
+		}
+
+		if len(messages) > 0 {
+			for _, msg := range messages {
+				fmt.Printf("\nFrom %s (%s):\n%s\n", msg.Agent, msg.Timestamp.Local().Format(time.Kitchen), msg.Message)
+			}
+			fmt.Printf("\nReply with: air agent send <agent> \"<message>\"\n")
+			return nil
+		}
+
+		if !inboxWait {
+			fmt.Println("No new messages.")
+			return nil
+		}
+		time.Sleep(interval)
+	}
+}
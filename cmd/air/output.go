@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormat is the root --output flag: text, json, or yaml
+var outputFormat string
+
+// structuredOutput annotates commands that render their results with render,
+// and so accept --output json|yaml
+const structuredOutput = "air.structured-output"
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, or yaml")
+}
+
+// supportsOutput marks cmds as rendering their results through render
+func supportsOutput(cmds ...*cobra.Command) {
+	for _, cmd := range cmds {
+		if cmd.Annotations == nil {
+			cmd.Annotations = map[string]string{}
+		}
+		cmd.Annotations[structuredOutput] = "true"
+	}
+}
+
+// checkOutputFormat rejects unknown formats, and json/yaml for commands that
+// only print text, so scripts fail loudly instead of parsing prose
+func checkOutputFormat(cmd *cobra.Command) error {
+	switch outputFormat {
+	case "text":
+		return nil
+	case "json", "yaml":
+	default:
+		return withCode(codeUsage, fmt.Errorf("invalid --output '%s' (use text, json, or yaml)", outputFormat))
+	}
+	if cmd.Annotations[structuredOutput] == "" {
+		return withCode(codeUsage, fmt.Errorf("'%s' doesn't support --output %s", cmd.CommandPath(), outputFormat))
+	}
+	return nil
+}
+
+// render writes v to stdout as JSON or YAML per --output, or calls text to
+// print the human-readable form. Both structured formats follow v's json tags.
+func render(v any, text func()) error {
+	return renderTo(os.Stdout, v, text)
+}
+
+func renderTo(w io.Writer, v any, text func()) error {
+	switch outputFormat {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "yaml":
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		// JSON is valid YAML: decoding into a node keeps field order, and
+		// clearing styles re-emits it in block form
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return err
+		}
+		clearYAMLStyle(&node)
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	text()
+	return nil
+}
+
+// clearYAMLStyle resets flow and quoting styles so the encoder picks defaults
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
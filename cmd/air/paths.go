@@ -3,8 +3,10 @@ package main
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
-	"sort"
+	"strings"
+	"sync"
 )
 
 // Mode represents the Air operating mode
@@ -15,6 +17,8 @@ const (
 	ModeSingle Mode = "single"
 	// ModeWorkspace is multi-repo workspace mode
 	ModeWorkspace Mode = "workspace"
+	// ModeMonorepo is a single repo whose top-level directories are planned like workspace repos
+	ModeMonorepo Mode = "monorepo"
 )
 
 // WorkspaceInfo holds information about the current workspace
@@ -23,81 +27,105 @@ type WorkspaceInfo struct {
 	Name  string   // Project/workspace name (directory basename)
 	Root  string   // Absolute path to workspace root (cwd)
 	Repos []string // List of repo names (empty for single mode, populated for workspace mode)
+
+	// Components lists component directories relative to Root (monorepo mode only)
+	Components []string
+
+	// RepoConfigs maps repo name to its resolved configuration (path, base branch).
+	// Repos missing from the map are assumed to live at Root/<name>.
+	RepoConfigs map[string]WorkspaceRepo
 }
 
-// detectMode determines the Air operating mode based on the current directory.
-// - If cwd is a git repo → single mode
-// - If cwd is NOT a git repo but has git repo children → workspace mode
+// detectMode determines the Air operating mode based on the project root, which
+// is the current directory or the repo or workspace containing it (see findProjectRoot).
+// - If root has an air.workspace.yaml listing repos → workspace mode with those repos
+// - If root is a git repo with a manifest listing components → monorepo mode
+// - If root is a git repo (and has no manifest) → single mode
+// - If root has git repo descendants (per the manifest's discover settings) → workspace mode
 // - Otherwise → error
 func detectMode() (*WorkspaceInfo, error) {
-	cwd, err := os.Getwd()
+	root, err := getProjectRoot()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get working directory: %w", err)
+		return nil, fmt.Errorf("failed to find project root: %w", err)
 	}
 
-	name := filepath.Base(cwd)
+	name := projectName(root)
+
+	// An explicit repo list in the manifest overrides directory scanning
+	manifest, err := loadWorkspaceManifest(root)
+	if err != nil {
+		return nil, err
+	}
+	if manifest != nil && len(manifest.Repos) > 0 {
+		repos, err := resolveManifestRepos(root, manifest)
+		if err != nil {
+			return nil, err
+		}
+		return newWorkspaceInfo(name, root, repos), nil
+	}
 
-	// Check if cwd is a git repo
-	gitDir := filepath.Join(cwd, ".git")
-	if stat, err := os.Stat(gitDir); err == nil && stat.IsDir() {
+	// Components turn a single repo into a monorepo with pseudo-repos
+	if manifest != nil && len(manifest.Components) > 0 {
+		if !isGitRepo(root) {
+			return nil, fmt.Errorf("%s declares components but %s is not a git repo", workspaceManifestFile, root)
+		}
+		components, err := resolveComponents(root, manifest.Components)
+		if err != nil {
+			return nil, err
+		}
 		return &WorkspaceInfo{
-			Mode:  ModeSingle,
-			Name:  name,
-			Root:  cwd,
-			Repos: nil,
+			Mode:       ModeMonorepo,
+			Name:       name,
+			Root:       root,
+			Components: components,
 		}, nil
 	}
 
-	// Check for git repo children
-	repos, err := findChildRepos(cwd)
-	if err != nil {
-		return nil, err
+	// A git repo is a single repo unless a discover-only manifest finds
+	// nested repos below it
+	single := isGitRepo(root)
+	if manifest != nil || !single {
+		repos, err := discoverRepos(root, manifest.discovery())
+		if err != nil {
+			return nil, err
+		}
+		if len(repos) > 0 {
+			return newWorkspaceInfo(name, root, repos), nil
+		}
 	}
 
-	if len(repos) > 0 {
+	if single {
 		return &WorkspaceInfo{
-			Mode:  ModeWorkspace,
+			Mode:  ModeSingle,
 			Name:  name,
-			Root:  cwd,
-			Repos: repos,
+			Root:  root,
+			Repos: nil,
 		}, nil
 	}
 
-	return nil, fmt.Errorf("not a git repo and no git repo children found in %s", cwd)
+	return nil, fmt.Errorf("not a git repo and no git repo children found in %s", root)
 }
 
-// findChildRepos returns a sorted list of immediate child directories that are git repos
-func findChildRepos(dir string) ([]string, error) {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read directory: %w", err)
+// newWorkspaceInfo builds a workspace-mode WorkspaceInfo from resolved repos
+func newWorkspaceInfo(name, root string, repos []WorkspaceRepo) *WorkspaceInfo {
+	info := &WorkspaceInfo{
+		Mode:        ModeWorkspace,
+		Name:        name,
+		Root:        root,
+		RepoConfigs: make(map[string]WorkspaceRepo),
 	}
-
-	var repos []string
-	for _, e := range entries {
-		if !e.IsDir() {
-			continue
-		}
-		// Skip hidden directories
-		if e.Name()[0] == '.' {
-			continue
-		}
-		childPath := filepath.Join(dir, e.Name())
-		gitDir := filepath.Join(childPath, ".git")
-		if stat, err := os.Stat(gitDir); err == nil && stat.IsDir() {
-			repos = append(repos, e.Name())
-		}
+	for _, r := range repos {
+		info.Repos = append(info.Repos, r.Name)
+		info.RepoConfigs[r.Name] = r
 	}
-
-	sort.Strings(repos)
-	return repos, nil
+	return info
 }
 
 // getRepoPath returns the absolute path to a repo within the workspace.
-// In single mode, returns the workspace root.
+// In single (and monorepo) mode, returns the workspace root.
 // In workspace mode, returns the path to the named repo.
 func (w *WorkspaceInfo) getRepoPath(repoName string) (string, error) {
-	if w.Mode == ModeSingle {
+	if w.Mode != ModeWorkspace {
 		if repoName != "" && repoName != w.Name {
 			return "", fmt.Errorf("in single-repo mode, cannot reference repo %q", repoName)
 		}
@@ -107,12 +135,29 @@ func (w *WorkspaceInfo) getRepoPath(repoName string) (string, error) {
 	// Workspace mode: validate repo exists
 	for _, r := range w.Repos {
 		if r == repoName {
-			return filepath.Join(w.Root, repoName), nil
+			return w.repoDir(repoName), nil
 		}
 	}
 	return "", fmt.Errorf("repo %q not found in workspace (available: %v)", repoName, w.Repos)
 }
 
+// repoDir returns the absolute path to a workspace repo without validating it.
+// Manifest-defined repos may live anywhere; scanned repos live at Root/<name>.
+func (w *WorkspaceInfo) repoDir(repoName string) string {
+	if w.Mode != ModeWorkspace {
+		return w.Root
+	}
+	if r, ok := w.RepoConfigs[repoName]; ok {
+		return r.Path
+	}
+	return filepath.Join(w.Root, repoName)
+}
+
+// baseBranch returns the configured base branch for a repo, or "" to use HEAD
+func (w *WorkspaceInfo) baseBranch(repoName string) string {
+	return w.RepoConfigs[repoName].Base
+}
+
 // getAirDirForWorkspace returns the air directory for this workspace: ~/.air/<name>/
 func (w *WorkspaceInfo) getAirDirForWorkspace() (string, error) {
 	home, err := os.UserHomeDir()
@@ -131,7 +176,7 @@ func (w *WorkspaceInfo) getWorktreePath(repoName, planName string) (string, erro
 		return "", err
 	}
 
-	if w.Mode == ModeSingle {
+	if w.Mode != ModeWorkspace {
 		return filepath.Join(airDir, "worktrees", planName), nil
 	}
 
@@ -142,19 +187,169 @@ func (w *WorkspaceInfo) getWorktreePath(repoName, planName string) (string, erro
 	return filepath.Join(airDir, "worktrees", repoName, planName), nil
 }
 
-// getProjectName returns the basename of the current working directory.
+// getProjectName returns the project name for the current working directory.
 // This is used as the project identifier in ~/.air/<project>/
 func getProjectName() (string, error) {
+	root, err := getProjectRoot()
+	if err != nil {
+		return "", err
+	}
+	return projectName(root), nil
+}
+
+// projectRoots caches findProjectRoot by working directory, since paths are
+// resolved many times per command
+var projectRoots = struct {
+	sync.Mutex
+	byDir map[string]string
+}{byDir: make(map[string]string)}
+
+// getProjectRoot returns the project root for the current working directory,
+// so commands work from anywhere inside the repo or workspace
+func getProjectRoot() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Base(cwd), nil
+	projectRoots.Lock()
+	defer projectRoots.Unlock()
+	root, ok := projectRoots.byDir[cwd]
+	if !ok {
+		root = findProjectRoot(cwd)
+		projectRoots.byDir[cwd] = root
+	}
+	return root, nil
+}
+
+// findProjectRoot returns the directory air treats as the project root for dir:
+//   - the nearest directory at or above dir with an air.workspace.yaml
+//   - dir itself if it is a repo
+//   - the top of the git repository containing dir, or the directory above it
+//     if that is the initialized project (a workspace without a manifest)
+//   - otherwise dir: a workspace if it has repos below it, and if not, where
+//     errors name the directory the user is in
+//
+// The enclosing repo comes before repos below dir, so a subdirectory holding
+// nested checkouts (vendored clones, say) doesn't become a workspace.
+func findProjectRoot(dir string) string {
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, workspaceManifestFile)); err == nil {
+			return d
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if isGitRepo(dir) {
+		return dir
+	}
+
+	if out, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output(); err == nil {
+		top := filepath.Clean(strings.TrimSpace(string(out)))
+		if parent := filepath.Dir(top); !projectInitialized(top) && projectInitialized(parent) {
+			return parent
+		}
+		return top
+	}
+	return dir
+}
+
+// projectInitialized reports whether dir has been set up with 'air init'
+func projectInitialized(dir string) bool {
+	root, err := getAirRoot()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(root, projectName(dir)))
+	return err == nil
+}
+
+// projectName names the project rooted at dir: after its origin remote when
+// the repo opts in with 'git config air.identity remote', so every clone of a
+// repo shares one project, otherwise after the directory
+func projectName(dir string) string {
+	if name := remoteProjectName(dir); name != "" {
+		return name
+	}
+	return dirProjectName(dir)
+}
+
+// remoteNames caches remoteProjectName by directory, since it runs git
+var remoteNames = struct {
+	sync.Mutex
+	byDir map[string]string
+}{byDir: make(map[string]string)}
+
+// remoteProjectName returns the project name derived from dir's origin URL,
+// or "" if dir doesn't opt in or has no origin
+func remoteProjectName(dir string) string {
+	remoteNames.Lock()
+	defer remoteNames.Unlock()
+	if name, ok := remoteNames.byDir[dir]; ok {
+		return name
+	}
+	name := ""
+	out, _ := exec.Command("git", "-C", dir, "config", "--get", "air.identity").Output()
+	if strings.TrimSpace(string(out)) == "remote" {
+		if url, err := exec.Command("git", "-C", dir, "remote", "get-url", "origin").Output(); err == nil {
+			name = normalizeRemoteURL(strings.TrimSpace(string(url)))
+		}
+	}
+	remoteNames.byDir[dir] = name
+	return name
+}
+
+// normalizeRemoteURL turns the ways of writing a remote into one project name:
+// https://github.com/scotro/air.git, git@github.com:scotro/air, and
+// ssh://git@github.com:22/scotro/air all become github.com_scotro_air
+func normalizeRemoteURL(raw string) string {
+	rest := raw
+	if _, after, ok := strings.Cut(rest, "://"); ok {
+		rest = after
+	} else if i := strings.Index(rest, ":"); i > 0 && !strings.Contains(rest[:i], "/") {
+		// scp-like syntax: [user@]host:path
+		rest = rest[:i] + "/" + rest[i+1:]
+	}
+	host, path, _ := strings.Cut(rest, "/")
+	if _, h, ok := strings.Cut(host, "@"); ok {
+		host = h
+	}
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h // drop the port
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+
+	var parts []string
+	for _, p := range strings.Split(strings.ToLower(host)+"/"+path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// dirProjectName names the project rooted at dir after the directory, dropping
+// the .git suffix bare repositories are conventionally cloned with
+func dirProjectName(dir string) string {
+	name := filepath.Base(dir)
+	if trimmed, ok := strings.CutSuffix(name, ".git"); ok && trimmed != "" && isBareRepo(dir) {
+		return trimmed
+	}
+	return name
+}
+
+// getAirRoot returns the root of all air project directories: ~/.air/
+func getAirRoot() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".air"), nil
 }
 
 // getAirDir returns the air directory for the current project: ~/.air/<project>/
 func getAirDir() (string, error) {
-	home, err := os.UserHomeDir()
+	root, err := getAirRoot()
 	if err != nil {
 		return "", err
 	}
@@ -162,7 +357,7 @@ func getAirDir() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".air", project), nil
+	return filepath.Join(root, project), nil
 }
 
 // mustGetAirDir returns the air directory or panics. Use only when error handling
@@ -175,8 +370,12 @@ func mustGetAirDir() string {
 	return dir
 }
 
-// getPlansDir returns ~/.air/<project>/plans/
+// getPlansDir returns ~/.air/<project>/plans/. Agents find it beside their
+// AIR_CHANNELS_DIR, since their working directory is a worktree.
 func getPlansDir() string {
+	if dir := os.Getenv("AIR_CHANNELS_DIR"); dir != "" {
+		return filepath.Join(filepath.Dir(dir), "plans")
+	}
 	return filepath.Join(mustGetAirDir(), "plans")
 }
 
@@ -202,11 +401,39 @@ func getChannelsDir() string {
 	return filepath.Join(mustGetAirDir(), "channels")
 }
 
+// getArtifactsDir returns the directory agents publish shared artifacts to.
+// For agent commands (with AIR_ARTIFACTS_DIR set), returns the env var value.
+// For main project commands, computes ~/.air/<project>/artifacts/
+func getArtifactsDir() string {
+	if dir := os.Getenv("AIR_ARTIFACTS_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(mustGetAirDir(), "artifacts")
+}
+
 // getContextPath returns ~/.air/<project>/context.md
 func getContextPath() string {
 	return filepath.Join(mustGetAirDir(), "context.md")
 }
 
+// getContextBasePath returns ~/.air/<project>/context-base.md, the unmodified
+// template context.md was generated from (the base for 'air init --update-context')
+func getContextBasePath() string {
+	return filepath.Join(mustGetAirDir(), "context-base.md")
+}
+
+// getAllowedToolsPath returns ~/.air/<project>/allowed-tools, the commands
+// agents may run without asking beyond air's own
+func getAllowedToolsPath() string {
+	return filepath.Join(mustGetAirDir(), "allowed-tools")
+}
+
+// getContextDir returns ~/.air/<project>/context/, which holds context files
+// appended to individual agents' system prompts
+func getContextDir() string {
+	return filepath.Join(mustGetAirDir(), "context")
+}
+
 // isInitialized checks if the air directory exists for the current project.
 func isInitialized() bool {
 	dir, err := getAirDir()
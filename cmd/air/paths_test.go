@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -225,3 +226,351 @@ func TestWorkspaceInfo_GetWorktreePath(t *testing.T) {
 		t.Error("expected error for missing repo name in workspace mode")
 	}
 }
+
+func TestPlanList_GroupsByRepositoryInWorkspace(t *testing.T) {
+	t.Parallel()
+	env := setupTestWorkspace(t)
+	defer env.cleanup()
+
+	if out, err := env.run(t, nil, "init"); err != nil {
+		t.Fatalf("air init failed: %v\n%s", err, out)
+	}
+
+	plansDir := filepath.Join(env.airDir(), "plans")
+	os.WriteFile(filepath.Join(plansDir, "proto.md"), []byte("# Plan: proto\n\n**Repository:** schema\n\n**Objective:** Update protos\n"), 0644)
+	os.WriteFile(filepath.Join(plansDir, "login.md"), []byte("# Plan: login\n\n**Repository:** authapi\n\n**Objective:** Add login\n"), 0644)
+	os.WriteFile(filepath.Join(plansDir, "orphan.md"), []byte("# Plan: orphan\n\n**Objective:** No repo\n"), 0644)
+
+	out, err := env.run(t, nil, "plan", "list")
+	if err != nil {
+		t.Fatalf("air plan list failed: %v\n%s", err, out)
+	}
+
+	authIdx := strings.Index(out, "authapi:")
+	schemaIdx := strings.Index(out, "schema:")
+	if authIdx == -1 || schemaIdx == -1 {
+		t.Fatalf("expected repo headings, got: %s", out)
+	}
+	if authIdx > strings.Index(out, "login") || strings.Index(out, "login") > schemaIdx {
+		t.Errorf("expected login listed under authapi, got: %s", out)
+	}
+	if !strings.Contains(out, "Missing **Repository:** field") || !strings.Contains(out, "orphan") {
+		t.Errorf("expected orphan plan flagged as missing repository, got: %s", out)
+	}
+}
+
+func TestDetectMode_ManifestOverridesScanning(t *testing.T) {
+	t.Parallel()
+	env := setupTestWorkspace(t)
+	defer env.cleanup()
+
+	// Vendored checkout that would otherwise be picked up by scanning
+	vendored := filepath.Join(env.dir, "vendored")
+	os.Mkdir(vendored, 0755)
+	os.Mkdir(filepath.Join(vendored, ".git"), 0755)
+
+	manifest := `repos:
+  - path: schema
+    alias: proto
+    base: main
+  - path: authapi
+`
+	os.WriteFile(filepath.Join(env.dir, "air.workspace.yaml"), []byte(manifest), 0644)
+
+	out, err := env.run(t, nil, "init")
+	if err != nil {
+		t.Fatalf("air init failed: %v\n%s", err, out)
+	}
+
+	if !strings.Contains(out, "2 repositories") {
+		t.Errorf("expected only manifest repos, got: %s", out)
+	}
+	if !strings.Contains(out, "- proto") || !strings.Contains(out, "- authapi") {
+		t.Errorf("expected manifest repos (with alias) to be listed, got: %s", out)
+	}
+	if strings.Contains(out, "usersvc") || strings.Contains(out, "vendored") {
+		t.Errorf("repos outside the manifest should be ignored, got: %s", out)
+	}
+}
+
+func TestDetectMode_ManifestRejectsNonRepo(t *testing.T) {
+	t.Parallel()
+	env := setupTestWorkspace(t)
+	defer env.cleanup()
+
+	os.WriteFile(filepath.Join(env.dir, "air.workspace.yaml"), []byte("repos:\n  - path: missing\n"), 0644)
+
+	out, err := env.run(t, nil, "init")
+	if err == nil {
+		t.Fatalf("expected init to fail for manifest entry that is not a repo, got: %s", out)
+	}
+	if !strings.Contains(out, "not a git repository") {
+		t.Errorf("expected error to name the bad entry, got: %s", out)
+	}
+}
+
+func TestDetectMode_DiscoversNestedRepos(t *testing.T) {
+	t.Parallel()
+	env := setupTestDir(t)
+	defer env.cleanup()
+
+	// org/team/repo layout
+	for _, rel := range []string{"acme/core/api", "acme/core/web", "acme/infra/deploy", "scratch/tmp"} {
+		repo := filepath.Join(env.dir, filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Join(repo, ".git"), 0755)
+	}
+
+	manifest := `discover:
+  include:
+    - "acme/*/*"
+`
+	os.WriteFile(filepath.Join(env.dir, "air.workspace.yaml"), []byte(manifest), 0644)
+
+	out, err := env.run(t, nil, "init")
+	if err != nil {
+		t.Fatalf("air init failed: %v\n%s", err, out)
+	}
+
+	for _, repo := range []string{"api", "web", "deploy"} {
+		if !strings.Contains(out, "- "+repo) {
+			t.Errorf("expected nested repo %s to be discovered, got: %s", repo, out)
+		}
+	}
+	if strings.Contains(out, "- tmp") {
+		t.Errorf("repo not matching include pattern should be skipped, got: %s", out)
+	}
+}
+
+func TestDetectMode_DefaultDepthIsOneLevel(t *testing.T) {
+	t.Parallel()
+	env := setupTestDir(t)
+	defer env.cleanup()
+
+	os.MkdirAll(filepath.Join(env.dir, "top", ".git"), 0755)
+	os.MkdirAll(filepath.Join(env.dir, "group", "nested", ".git"), 0755)
+
+	out, err := env.run(t, nil, "init")
+	if err != nil {
+		t.Fatalf("air init failed: %v\n%s", err, out)
+	}
+	if strings.Contains(out, "nested") {
+		t.Errorf("nested repo should not be found without a discover depth, got: %s", out)
+	}
+	if !strings.Contains(out, "- top") {
+		t.Errorf("expected top-level repo, got: %s", out)
+	}
+}
+
+func TestDetectMode_DiscoverOnlyManifestInRepo(t *testing.T) {
+	t.Parallel()
+	env := setupTestRepo(t)
+	defer env.cleanup()
+
+	// Discovery settings alone don't make a repo a workspace
+	os.WriteFile(filepath.Join(env.dir, "air.workspace.yaml"), []byte("discover:\n  depth: 2\n"), 0644)
+
+	out, err := env.run(t, nil, "init")
+	if err != nil {
+		t.Fatalf("air init failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Initialized Air workflow") {
+		t.Errorf("expected single-repo mode without nested repos, got: %s", out)
+	}
+
+	// Nested repos found by discovery do
+	os.RemoveAll(env.airDir())
+	os.MkdirAll(filepath.Join(env.dir, "libs", "shared", ".git"), 0755)
+	out, err = env.run(t, nil, "init")
+	if err != nil {
+		t.Fatalf("air init failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "workspace") || !strings.Contains(out, "- shared") {
+		t.Errorf("expected workspace mode with the nested repo, got: %s", out)
+	}
+}
+
+func TestDetectMode_Monorepo(t *testing.T) {
+	t.Parallel()
+	env := setupTestRepo(t)
+	defer env.cleanup()
+
+	for _, dir := range []string{"services/auth", "services/billing", "packages/ui", "docs"} {
+		os.MkdirAll(filepath.Join(env.dir, filepath.FromSlash(dir)), 0755)
+	}
+	os.WriteFile(filepath.Join(env.dir, "air.workspace.yaml"), []byte("components:\n  - services/*\n  - packages/*\n"), 0644)
+
+	out, err := env.run(t, nil, "init")
+	if err != nil {
+		t.Fatalf("air init failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "monorepo") || !strings.Contains(out, "3 components") {
+		t.Errorf("expected monorepo detection with 3 components, got: %s", out)
+	}
+
+	plansDir := filepath.Join(env.airDir(), "plans")
+	os.WriteFile(filepath.Join(plansDir, "auth.md"), []byte("# Plan: auth\n\n**Component:** services/auth\n\n**In scope:**\n- services/auth/\n"), 0644)
+	os.WriteFile(filepath.Join(plansDir, "leak.md"), []byte("# Plan: leak\n\n**Component:** packages/ui\n\n**In scope:**\n- docs/\n"), 0644)
+
+	out, err = env.run(t, nil, "plan", "validate")
+	if err == nil {
+		t.Fatalf("expected validation to fail for out-of-component scope, got: %s", out)
+	}
+	if !strings.Contains(out, "outside its component") {
+		t.Errorf("expected scope boundary error, got: %s", out)
+	}
+	if !strings.Contains(out, "auth [component: services/auth]") {
+		t.Errorf("expected component in plan summary, got: %s", out)
+	}
+}
+
+func TestDetectMode_ExcludePatterns(t *testing.T) {
+	t.Parallel()
+	env := setupTestWorkspace(t)
+	defer env.cleanup()
+
+	for _, rel := range []string{"authapi-fork", "archive/oldsvc"} {
+		os.MkdirAll(filepath.Join(env.dir, filepath.FromSlash(rel), ".git"), 0755)
+	}
+
+	manifest := `discover:
+  depth: 2
+  exclude:
+    - "*-fork"
+    - "archive"
+    - usersvc
+`
+	os.WriteFile(filepath.Join(env.dir, "air.workspace.yaml"), []byte(manifest), 0644)
+
+	out, err := env.run(t, nil, "init")
+	if err != nil {
+		t.Fatalf("air init failed: %v\n%s", err, out)
+	}
+
+	if !strings.Contains(out, "2 repositories") || !strings.Contains(out, "- authapi\n") || !strings.Contains(out, "- schema") {
+		t.Errorf("expected only authapi and schema, got: %s", out)
+	}
+	for _, excluded := range []string{"authapi-fork", "oldsvc", "usersvc"} {
+		if strings.Contains(out, excluded) {
+			t.Errorf("excluded repo %s should not be listed, got: %s", excluded, out)
+		}
+	}
+}
+
+func TestDetectMode_FromSubdirectory(t *testing.T) {
+	t.Parallel()
+	env := setupTestRepo(t)
+	defer env.cleanup()
+
+	env.run(t, nil, "init")
+	os.WriteFile(filepath.Join(env.airDir(), "plans", "api.md"), []byte("# Plan: api\n"), 0644)
+
+	sub := filepath.Join(env.dir, "src", "deep")
+	os.MkdirAll(sub, 0755)
+	subEnv := &testEnv{dir: sub, home: env.home}
+	out, err := subEnv.run(t, nil, "plan", "list")
+	if err != nil || !strings.Contains(out, "api") {
+		t.Errorf("expected plan list to find the repo's plans from a subdirectory, got %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(env.home, ".air", "deep")); !os.IsNotExist(err) {
+		t.Error("subdirectory should not become its own project")
+	}
+}
+
+func TestDetectMode_FromSubdirectoryWithNestedCheckouts(t *testing.T) {
+	t.Parallel()
+	env := setupTestRepo(t)
+	defer env.cleanup()
+
+	env.run(t, nil, "init")
+	os.WriteFile(filepath.Join(env.airDir(), "plans", "api.md"), []byte("# Plan: api\n"), 0644)
+
+	// A vendored clone below the subdirectory doesn't make it a workspace
+	vendor := filepath.Join(env.dir, "third_party")
+	if out, err := exec.Command("git", "init", "-q", filepath.Join(vendor, "lib")).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+	subEnv := &testEnv{dir: vendor, home: env.home}
+	out, err := subEnv.run(t, nil, "plan", "list")
+	if err != nil || !strings.Contains(out, "api") {
+		t.Errorf("expected the enclosing repo's plans, got %v\n%s", err, out)
+	}
+}
+
+func TestDetectMode_FromRepoInsideWorkspace(t *testing.T) {
+	t.Parallel()
+	env := setupTestDir(t)
+	defer env.cleanup()
+
+	for _, repo := range []string{"api", "web"} {
+		if out, err := exec.Command("git", "init", "-q", filepath.Join(env.dir, repo)).CombinedOutput(); err != nil {
+			t.Fatalf("git init failed: %v\n%s", err, out)
+		}
+	}
+	if out, err := env.run(t, nil, "init"); err != nil {
+		t.Fatalf("air init failed: %v\n%s", err, out)
+	}
+
+	// From inside a repo of an initialized workspace, the workspace is the project
+	sub := filepath.Join(env.dir, "api", "internal")
+	os.MkdirAll(sub, 0755)
+	subEnv := &testEnv{dir: sub, home: env.home}
+	out, err := subEnv.run(t, nil, "status", "-o", "json")
+	if err != nil {
+		t.Fatalf("air status failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, `"workspace": "`+filepath.Base(env.dir)+`"`) {
+		t.Errorf("expected workspace status from inside a member repo, got:\n%s", out)
+	}
+}
+
+func TestNormalizeRemoteURL(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct{ url, want string }{
+		{"https://github.com/scotro/air.git", "github.com_scotro_air"},
+		{"https://github.com/scotro/air", "github.com_scotro_air"},
+		{"git@github.com:scotro/air.git", "github.com_scotro_air"},
+		{"ssh://git@GitHub.com:22/scotro/air.git/", "github.com_scotro_air"},
+		{"https://user@gitlab.example.com/group/sub/project.git", "gitlab.example.com_group_sub_project"},
+		{"/srv/git/air.git", "srv_git_air"},
+	} {
+		if got := normalizeRemoteURL(tc.url); got != tc.want {
+			t.Errorf("normalizeRemoteURL(%q) = %q, want %q", tc.url, got, tc.want)
+		}
+	}
+}
+
+func TestProjectIdentity_RemoteSharesStateAcrossClones(t *testing.T) {
+	t.Parallel()
+	env := setupTestRepo(t)
+	defer env.cleanup()
+
+	git := func(args ...string) {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("-C", env.dir, "remote", "add", "origin", "git@github.com:scotro/air.git")
+	env.run(t, nil, "init")
+	os.WriteFile(filepath.Join(env.airDir(), "plans", "api.md"), []byte("# Plan: api\n"), 0644)
+
+	// Opting in moves the directory-named state to the remote-named project
+	git("-C", env.dir, "config", "air.identity", "remote")
+	out, err := env.run(t, nil, "init")
+	if err != nil || !strings.Contains(out, "Moved existing state") {
+		t.Fatalf("expected init to move existing state, got %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(env.home, ".air", "github.com_scotro_air", "plans", "api.md")); err != nil {
+		t.Fatalf("expected plans under the remote-named project: %v", err)
+	}
+
+	// A second clone elsewhere, also opted in, sees the same project
+	clone := filepath.Join(env.dir, "elsewhere", "other-name")
+	git("clone", "-q", env.dir, clone)
+	git("-C", clone, "remote", "set-url", "origin", "https://github.com/scotro/air")
+	git("-C", clone, "config", "air.identity", "remote")
+	cloneEnv := &testEnv{dir: clone, home: env.home}
+	out, err = cloneEnv.run(t, nil, "plan", "list")
+	if err != nil || !strings.Contains(out, "api") {
+		t.Errorf("expected the clone to share the project's plans, got %v\n%s", err, out)
+	}
+}
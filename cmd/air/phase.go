@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Plans may declare a **Phase:** number. 'air run --phase N' launches phase N
+// only once every plan in an earlier phase is done and integrated; plans
+// without a phase aren't part of the sequence.
+
+// phaseRegex matches **Phase:** field value
+var phaseRegex = regexp.MustCompile(`^\*\*Phase:\*\*\s*(.+)$`)
+
+// parsePhase reads a **Phase:** value. Returns -1 for anything but a positive
+// number, so validation can report it.
+func parsePhase(value string) int {
+	n, err := strconv.Atoi(strings.Trim(strings.TrimSpace(value), "`"))
+	if err != nil || n < 1 {
+		return -1
+	}
+	return n
+}
+
+// validatePhases checks phase numbers, and that no plan waits on a channel
+// signaled in a later phase, which could never arrive
+func validatePhases(plans []PlanDependencies) []error {
+	var errs []error
+	signaledBy := make(map[string]PlanDependencies)
+	for _, p := range plans {
+		if p.Phase < 0 {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("plan '%s' has an invalid **Phase:** (want a positive number)", p.Name),
+			})
+		}
+		for _, ch := range p.Signals {
+			signaledBy[ch] = p
+		}
+	}
+	for _, p := range plans {
+		if p.Phase < 1 {
+			continue
+		}
+		for _, ch := range append(append([]string{}, p.WaitsOn...), p.Optional...) {
+			if producer, ok := signaledBy[ch]; ok && producer.Phase > p.Phase {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' (phase %d) waits on '%s', which '%s' signals in later phase %d", p.Name, p.Phase, ch, producer.Name, producer.Phase),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// planPhases returns the phases plans declare, in order
+func planPhases(plans []PlanDependencies) []int {
+	var phases []int
+	seen := make(map[int]bool)
+	for _, p := range plans {
+		if p.Phase > 0 && !seen[p.Phase] {
+			seen[p.Phase] = true
+			phases = append(phases, p.Phase)
+		}
+	}
+	sort.Ints(phases)
+	return phases
+}
+
+// plansInPhase returns the names of the plans in phase
+func plansInPhase(plans []PlanDependencies, phase int) []string {
+	var names []string
+	for _, p := range plans {
+		if p.Phase == phase {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// planProgress is how far a plan's most recent launch has got
+type planProgress struct {
+	launched   bool
+	done       bool
+	integrated bool
+	failed     bool
+}
+
+// readPlanProgress reports each plan's progress since its latest launch, from
+// the event log (which survives 'air clean') and the repositories. A branch
+// already merged into its base branch counts as integrated, however it got there.
+func readPlanProgress(info *WorkspaceInfo, plans []PlanDependencies) map[string]planProgress {
+	events, _ := readEvents()
+	progress := make(map[string]planProgress)
+	for _, e := range events {
+		p := progress[e.Agent]
+		switch e.Kind {
+		case eventLaunched:
+			p = planProgress{launched: true}
+		case eventDone:
+			p.done = true
+		case eventIntegrated:
+			p.integrated = true
+		case eventFailed:
+			p.failed = true
+		}
+		progress[e.Agent] = p
+	}
+
+	for _, pd := range plans {
+		p := progress[pd.Name]
+		if _, err := os.Stat(filepath.Join(getChannelsDir(), "done", pd.Name+".json")); err == nil {
+			p.done = true
+		}
+		if p.done && !p.integrated {
+			repoName, repoPath, _ := agentPaths(info, pd)
+			branch := "air/" + pd.Name
+			if exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", branch).Run() == nil &&
+				exec.Command("git", "-C", repoPath, "merge-base", "--is-ancestor", branch, info.baseBranch(repoName)).Run() == nil {
+				p.integrated = true
+			}
+		}
+		progress[pd.Name] = p
+	}
+	return progress
+}
+
+// phaseBlockers returns the plans in names that aren't done and integrated, with why
+func phaseBlockers(names []string, progress map[string]planProgress) []string {
+	var blockers []string
+	for _, name := range names {
+		switch p := progress[name]; {
+		case !p.launched && !p.done:
+			blockers = append(blockers, name+" (not run)")
+		case p.failed && !p.done:
+			blockers = append(blockers, name+" (failed)")
+		case !p.done:
+			blockers = append(blockers, name+" (not done)")
+		case !p.integrated:
+			blockers = append(blockers, name+" (not integrated)")
+		}
+	}
+	return blockers
+}
+
+// selectPhase returns the plans of phase, or of the first phase not yet done
+// and integrated when phase is 0, refusing if an earlier phase is unfinished
+func selectPhase(info *WorkspaceInfo, plans []PlanDependencies, phase int) (int, []string, error) {
+	phases := planPhases(plans)
+	if len(phases) == 0 {
+		return 0, nil, fmt.Errorf("no plans declare a **Phase:**")
+	}
+	progress := readPlanProgress(info, plans)
+
+	if phase == 0 {
+		for _, p := range phases {
+			if len(phaseBlockers(plansInPhase(plans, p), progress)) > 0 {
+				phase = p
+				break
+			}
+		}
+		if phase == 0 {
+			return 0, nil, fmt.Errorf("all phases are done and integrated")
+		}
+	} else if !containsInt(phases, phase) {
+		return 0, nil, fmt.Errorf("no plans in phase %d", phase)
+	}
+
+	for _, p := range phases {
+		if p >= phase {
+			break
+		}
+		if blockers := phaseBlockers(plansInPhase(plans, p), progress); len(blockers) > 0 {
+			return 0, nil, withCode(codeDependencyMissing, fmt.Errorf("phase %d can't start until phase %d is done and integrated: %s", phase, p, strings.Join(blockers, ", ")))
+		}
+	}
+	return phase, plansInPhase(plans, phase), nil
+}
+
+// containsInt reports whether s contains n
+func containsInt(s []int, n int) bool {
+	for _, v := range s {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// pickerItem is one selectable line in the plan picker
+type pickerItem struct {
+	name   string
+	detail string // Objective and target repo/component, shown after the name
+}
+
+// pickerState is the cursor and selection of a multi-select list
+type pickerState struct {
+	items    []pickerItem
+	cursor   int
+	selected []bool
+}
+
+// Picker keys, normalized from raw terminal input
+const (
+	keyUp = iota
+	keyDown
+	keyToggle
+	keyToggleAll
+	keyEnter
+	keyCancel
+	keyOther
+)
+
+// parseKey maps raw terminal input to a picker key
+func parseKey(b []byte) int {
+	switch {
+	case len(b) >= 3 && b[0] == 0x1b && b[1] == '[' && b[2] == 'A':
+		return keyUp
+	case len(b) >= 3 && b[0] == 0x1b && b[1] == '[' && b[2] == 'B':
+		return keyDown
+	case len(b) == 0:
+		return keyOther
+	}
+	switch b[0] {
+	case 'k':
+		return keyUp
+	case 'j':
+		return keyDown
+	case ' ', 'x':
+		return keyToggle
+	case 'a':
+		return keyToggleAll
+	case '\r', '\n':
+		return keyEnter
+	case 'q', 0x03, 0x1b: // q, Ctrl-C, bare Esc
+		return keyCancel
+	}
+	return keyOther
+}
+
+// handle applies a key. Returns done once the user confirms or cancels.
+func (s *pickerState) handle(key int) (done, cancelled bool) {
+	switch key {
+	case keyUp:
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case keyDown:
+		if s.cursor < len(s.items)-1 {
+			s.cursor++
+		}
+	case keyToggle:
+		s.selected[s.cursor] = !s.selected[s.cursor]
+	case keyToggleAll:
+		all := len(s.chosen()) == len(s.items)
+		for i := range s.selected {
+			s.selected[i] = !all
+		}
+	case keyEnter:
+		// Enter with nothing toggled launches the plan under the cursor
+		if len(s.chosen()) == 0 {
+			s.selected[s.cursor] = true
+		}
+		return true, false
+	case keyCancel:
+		return true, true
+	}
+	return false, false
+}
+
+// chosen returns the selected plan names in list order
+func (s *pickerState) chosen() []string {
+	var names []string
+	for i, item := range s.items {
+		if s.selected[i] {
+			names = append(names, item.name)
+		}
+	}
+	return names
+}
+
+// render draws the list. Lines end in \r\n since the terminal is in raw mode.
+func (s *pickerState) render() string {
+	var sb strings.Builder
+	sb.WriteString("Select plans to run (↑/↓ move, space toggle, a all, enter launch, q cancel)\r\n")
+	for i, item := range s.items {
+		cursor := " "
+		if i == s.cursor {
+			cursor = ">"
+		}
+		check := "[ ]"
+		if s.selected[i] {
+			check = "[x]"
+		}
+		fmt.Fprintf(&sb, "%s %s %s", cursor, check, item.name)
+		if item.detail != "" {
+			sb.WriteString("  " + item.detail)
+		}
+		sb.WriteString("\r\n")
+	}
+	return sb.String()
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// Other character devices (e.g. /dev/null) aren't terminals; stty only works on a tty
+	cmd := exec.Command("stty", "-g")
+	cmd.Stdin = f
+	return cmd.Run() == nil
+}
+
+// stty runs stty against the controlling terminal on stdin
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// pickPlans shows an interactive multi-select of items and returns the chosen
+// names, or nil if the user cancelled
+func pickPlans(items []pickerItem) ([]string, error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terminal settings: %w", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("failed to set terminal to raw mode: %w", err)
+	}
+	defer stty(saved)
+
+	state := &pickerState{items: items, selected: make([]bool, len(items))}
+	lines := len(items) + 1
+	fmt.Print("\033[?25l" + state.render()) // hide cursor while picking
+	defer fmt.Print("\033[?25h")
+
+	buf := make([]byte, 8)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		done, cancelled := state.handle(parseKey(buf[:n]))
+		// Move back to the top of the list and redraw
+		fmt.Printf("\033[%dA\033[J%s", lines, state.render())
+		if cancelled {
+			return nil, nil
+		}
+		if done {
+			return state.chosen(), nil
+		}
+	}
+}
@@ -0,0 +1,430 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// pipelineFile is the optional file at the project root describing ordered
+// stages of plans for 'air run --pipeline'
+const pipelineFile = "air.pipeline.yaml"
+
+// pipelinePollInterval is how often a running stage checks for done agents
+var pipelinePollInterval = 2 * time.Second
+
+// Pipeline is the parsed contents of air.pipeline.yaml
+type Pipeline struct {
+	Stages []PipelineStage `yaml:"stages"`
+}
+
+// PipelineStage is one step of a pipeline: a set of plans run together, then
+// integrated, then checked by the gate before the next stage starts
+type PipelineStage struct {
+	Name  string       `yaml:"name"`
+	Group string       `yaml:"group,omitempty"` // Plan group to run (plans/<group>/)
+	Plans []string     `yaml:"plans,omitempty"` // Plans to run, instead of a group
+	Gate  PipelineGate `yaml:"gate,omitempty"`
+}
+
+// PipelineGate decides whether the pipeline moves on after a stage integrates
+type PipelineGate struct {
+	Verify  string `yaml:"verify,omitempty"`  // Shell command run at the project root; must exit 0
+	Approve bool   `yaml:"approve,omitempty"` // Ask a human before continuing
+}
+
+// PipelineState is ~/.air/<project>/pipeline.json: how far the current
+// pipeline has got, so 'air run --pipeline --resume' can pick up after a
+// failure or reboot. Unlike run.json it survives 'air clean'.
+type PipelineState struct {
+	Started  time.Time            `json:"started"`
+	Finished *time.Time           `json:"finished,omitempty"`
+	Stages   []PipelineStageState `json:"stages"`
+}
+
+// PipelineStageState records which steps of a stage have completed
+type PipelineStageState struct {
+	Name       string     `json:"name"`
+	Launched   *time.Time `json:"launched,omitempty"`
+	Done       *time.Time `json:"done,omitempty"` // every agent ran 'air agent done'
+	Integrated *time.Time `json:"integrated,omitempty"`
+	Passed     *time.Time `json:"passed,omitempty"` // gate passed
+	Error      string     `json:"error,omitempty"`  // why the stage last stopped
+}
+
+// getPipelineStatePath returns ~/.air/<project>/pipeline.json
+func getPipelineStatePath() string {
+	return filepath.Join(mustGetAirDir(), "pipeline.json")
+}
+
+// readPipelineState loads pipeline.json, or returns nil if no pipeline has run
+func readPipelineState() (*PipelineState, error) {
+	data, err := os.ReadFile(getPipelineStatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var state PipelineState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", getPipelineStatePath(), err)
+	}
+	return &state, nil
+}
+
+// save writes the state, replacing the file so readers never see a partial one
+func (s *PipelineState) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := getPipelineStatePath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, getPipelineStatePath())
+}
+
+// stage returns the named stage's state, adding it if missing
+func (s *PipelineState) stage(name string) *PipelineStageState {
+	for i := range s.Stages {
+		if s.Stages[i].Name == name {
+			return &s.Stages[i]
+		}
+	}
+	s.Stages = append(s.Stages, PipelineStageState{Name: name})
+	return &s.Stages[len(s.Stages)-1]
+}
+
+// current returns the first stage of pipeline that hasn't passed its gate, or nil
+func (s *PipelineState) current(pipeline *Pipeline) *PipelineStageState {
+	for _, stage := range pipeline.Stages {
+		if st := s.stage(stage.Name); st.Passed == nil {
+			return st
+		}
+	}
+	return nil
+}
+
+// loadPipeline reads air.pipeline.yaml from dir.
+// Returns nil (and no error) if the file does not exist.
+func loadPipeline(dir string) (*Pipeline, error) {
+	data, err := os.ReadFile(filepath.Join(dir, pipelineFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", pipelineFile, err)
+	}
+
+	var pipeline Pipeline
+	if err := yaml.Unmarshal(data, &pipeline); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", pipelineFile, err)
+	}
+	return &pipeline, nil
+}
+
+// resolvePipeline returns each stage's plans, checking that stages are named
+// uniquely and select existing plans
+func resolvePipeline(p *Pipeline) ([][]string, []error) {
+	if len(p.Stages) == 0 {
+		return nil, []error{ValidationError{Message: pipelineFile + " has no stages"}}
+	}
+	files, err := listPlanFiles(getPlansDir())
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var errs []error
+	stagePlans := make([][]string, len(p.Stages))
+	seen := make(map[string]bool)
+	for i, stage := range p.Stages {
+		label := fmt.Sprintf("stage %d", i+1)
+		if stage.Name == "" {
+			errs = append(errs, ValidationError{Message: label + " has no name"})
+		} else {
+			label = fmt.Sprintf("stage '%s'", stage.Name)
+			if seen[stage.Name] {
+				errs = append(errs, ValidationError{Message: fmt.Sprintf("%s is defined more than once", label)})
+			}
+			seen[stage.Name] = true
+		}
+
+		switch {
+		case stage.Group != "" && len(stage.Plans) > 0:
+			errs = append(errs, ValidationError{Message: label + " sets both group and plans; use one"})
+		case stage.Group != "":
+			for _, f := range files {
+				if f.group == stage.Group {
+					stagePlans[i] = append(stagePlans[i], f.name)
+				}
+			}
+			if len(stagePlans[i]) == 0 {
+				errs = append(errs, ValidationError{Message: fmt.Sprintf("%s: no plans in group '%s'", label, stage.Group)})
+			}
+		case len(stage.Plans) > 0:
+			for _, name := range stage.Plans {
+				if _, ok := findPlanFile(getPlansDir(), name); !ok {
+					errs = append(errs, ValidationError{Message: fmt.Sprintf("%s: plan '%s' not found", label, name)})
+				}
+			}
+			stagePlans[i] = stage.Plans
+		default:
+			errs = append(errs, ValidationError{Message: label + " needs a group or plans"})
+		}
+	}
+	return stagePlans, errs
+}
+
+// runPipeline runs each stage of air.pipeline.yaml in order: launch its plans,
+// wait for every agent to finish, integrate, then pass the gate
+func runPipeline(cmd *cobra.Command, info *WorkspaceInfo) error {
+	pipeline, err := loadPipeline(info.Root)
+	if err != nil {
+		return err
+	}
+	if pipeline == nil {
+		return fmt.Errorf("no %s in %s", pipelineFile, info.Root)
+	}
+	stagePlans, errs := resolvePipeline(pipeline)
+	if len(errs) > 0 {
+		fmt.Println("Pipeline validation failed:")
+		for _, err := range errs {
+			fmt.Printf("  ✗ %s\n", err)
+		}
+		return withCode(codeValidationFailed, fmt.Errorf("invalid %s", pipelineFile))
+	}
+
+	fmt.Println("Pipeline:")
+	for i, stage := range pipeline.Stages {
+		fmt.Printf("  %d. %s: %s%s\n", i+1, stage.Name, strings.Join(stagePlans[i], ", "), gateLabel(stage.Gate))
+	}
+	if dryRun {
+		return nil
+	}
+
+	// An unfinished pipeline is only continued on request, never restarted silently
+	state, err := readPipelineState()
+	if err != nil {
+		return err
+	}
+	inProgress := state != nil && state.Finished == nil
+	switch {
+	case runResume && !inProgress:
+		return fmt.Errorf("no unfinished pipeline to resume")
+	case runResume:
+		if st := state.current(pipeline); st != nil {
+			fmt.Printf("\nResuming at stage '%s'\n", st.Name)
+		}
+	case inProgress:
+		where := "its last stage"
+		if st := state.current(pipeline); st != nil {
+			where = fmt.Sprintf("stage '%s'", st.Name)
+		}
+		return fmt.Errorf("a pipeline is already in progress at %s; continue it with 'air run --pipeline --resume' (or delete %s to start over)", where, getPipelineStatePath())
+	default:
+		state = &PipelineState{Started: time.Now().UTC()}
+	}
+
+	if !runYes && isTerminal(os.Stdin) {
+		if !confirm(fmt.Sprintf("\nRun %d stages? [y/N] ", len(pipeline.Stages))) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+	if err := state.save(); err != nil {
+		return fmt.Errorf("failed to write pipeline state: %w", err)
+	}
+
+	// Stages run unattended: the pipeline keeps the terminal to report progress
+	runPipelineFlag, runYes, noAttach = false, true, true
+	for i, stage := range pipeline.Stages {
+		st := state.stage(stage.Name)
+		if st.Passed != nil {
+			fmt.Printf("\n=== Stage %d/%d: %s (already complete) ===\n", i+1, len(pipeline.Stages), stage.Name)
+			continue
+		}
+		fmt.Printf("\n=== Stage %d/%d: %s ===\n\n", i+1, len(pipeline.Stages), stage.Name)
+
+		if err := runPipelineStage(cmd, info, state, st, stage, stagePlans[i], i == len(pipeline.Stages)-1); err != nil {
+			st.Error = err.Error()
+			state.save()
+			return err
+		}
+	}
+
+	finished := time.Now().UTC()
+	state.Finished = &finished
+	if err := state.save(); err != nil {
+		return fmt.Errorf("failed to write pipeline state: %w", err)
+	}
+	fmt.Printf("\nPipeline complete: %d stages integrated.\n", len(pipeline.Stages))
+	return nil
+}
+
+// runPipelineStage runs the steps of stage that st doesn't record as complete,
+// saving the state after each one
+func runPipelineStage(cmd *cobra.Command, info *WorkspaceInfo, state *PipelineState, st *PipelineStageState, stage PipelineStage, plans []string, last bool) error {
+	step := func(stamp **time.Time) error {
+		now := time.Now().UTC()
+		*stamp = &now
+		st.Error = ""
+		if err := state.save(); err != nil {
+			return fmt.Errorf("failed to write pipeline state: %w", err)
+		}
+		return nil
+	}
+
+	if st.Done == nil {
+		// After a failure or reboot, relaunch the agents that aren't done if
+		// any has lost its window; their worktrees are reused
+		relaunch := plans
+		if st.Launched != nil {
+			relaunch = agentsToRelaunch(plans)
+			if len(relaunch) > 0 {
+				fmt.Printf("Relaunching %s\n\n", strings.Join(relaunch, ", "))
+			}
+		}
+		if len(relaunch) > 0 {
+			runGroup = ""
+			args := relaunch
+			if st.Launched == nil {
+				runGroup, args = stage.Group, stage.Plans
+			}
+			if err := runRun(cmd, args); err != nil {
+				return fmt.Errorf("stage '%s': %w", stage.Name, err)
+			}
+		}
+		if st.Launched == nil {
+			if err := step(&st.Launched); err != nil {
+				return err
+			}
+		}
+		if err := waitForPlansDone(plans); err != nil {
+			return fmt.Errorf("stage '%s': %w", stage.Name, err)
+		}
+		if err := step(&st.Done); err != nil {
+			return err
+		}
+	}
+
+	if st.Integrated == nil {
+		fmt.Printf("\nIntegrating stage '%s'...\n", stage.Name)
+		if err := runAutoIntegrate(info, false); err != nil {
+			return fmt.Errorf("stage '%s': %w", stage.Name, err)
+		}
+		if err := step(&st.Integrated); err != nil {
+			return err
+		}
+	}
+
+	if err := passGate(info, stage, last); err != nil {
+		return err
+	}
+	return step(&st.Passed)
+}
+
+// agentsToRelaunch returns the plans that aren't done if any of them has no
+// window in the project's tmux session (after a reboot, say) or has failed, or nil
+// if all are still running. Launching recreates the session, so it's all or none.
+func agentsToRelaunch(plans []string) []string {
+	doneDir := filepath.Join(getChannelsDir(), "done")
+	var unfinished []string
+	missing := false
+	for _, name := range plans {
+		if _, err := os.Stat(filepath.Join(doneDir, name+".json")); err == nil {
+			continue
+		}
+		unfinished = append(unfinished, name)
+		if findAgentWindow(runSession(), name) == "" || readFailure(name) != nil {
+			missing = true
+		}
+	}
+	if !missing {
+		return nil
+	}
+	return unfinished
+}
+
+// waitForPlansDone blocks until every named agent has run 'air agent done',
+// or returns an error as soon as one runs 'air agent fail'
+func waitForPlansDone(names []string) error {
+	doneDir := filepath.Join(getChannelsDir(), "done")
+	fmt.Printf("\nWaiting for %d agents (watch with: tmux attach -t %s)\n", len(names), runSession())
+	done := make(map[string]bool)
+	for len(done) < len(names) {
+		for _, name := range names {
+			if done[name] {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(doneDir, name+".json")); err == nil {
+				done[name] = true
+				fmt.Printf("  ✓ %s done (%d/%d)\n", name, len(done), len(names))
+			} else if f := readFailure(name); f != nil {
+				fmt.Printf("  ✗ %s failed: %s\n", name, f.Message)
+				return withCode(codeDependencyMissing, fmt.Errorf("agent '%s' failed: %s", name, f.Message))
+			}
+		}
+		if len(done) < len(names) {
+			time.Sleep(pipelinePollInterval)
+		}
+	}
+	return nil
+}
+
+// passGate runs a stage's verify command and asks for approval if required
+func passGate(info *WorkspaceInfo, stage PipelineStage, last bool) error {
+	if stage.Gate.Verify != "" {
+		fmt.Printf("\nVerifying stage '%s': %s\n", stage.Name, stage.Gate.Verify)
+		verify := exec.Command("sh", "-c", stage.Gate.Verify)
+		verify.Dir = info.Root
+		verify.Stdout = os.Stdout
+		verify.Stderr = os.Stderr
+		if err := verify.Run(); err != nil {
+			return fmt.Errorf("stage '%s' failed verification (%v); fix the integrated result, then rerun", stage.Name, err)
+		}
+		fmt.Println("✓ Verified")
+	}
+
+	if stage.Gate.Approve && !last {
+		if !isTerminal(os.Stdin) {
+			return fmt.Errorf("stage '%s' needs approval to continue; rerun on a terminal", stage.Name)
+		}
+		if !confirm(fmt.Sprintf("\nStage '%s' is integrated. Continue to the next stage? [y/N] ", stage.Name)) {
+			return fmt.Errorf("stopped after stage '%s'", stage.Name)
+		}
+	}
+	return nil
+}
+
+// gateLabel describes a stage's gate for the pipeline summary
+func gateLabel(gate PipelineGate) string {
+	var parts []string
+	if gate.Verify != "" {
+		parts = append(parts, "verify: "+gate.Verify)
+	}
+	if gate.Approve {
+		parts = append(parts, "approval")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " [gate: " + strings.Join(parts, ", ") + "]"
+}
+
+// confirm asks a yes/no question on the terminal, defaulting to no
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
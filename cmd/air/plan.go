@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/scotro/air/cmd/air/prompts"
@@ -47,19 +48,22 @@ var planRestoreCmd = &cobra.Command{
 }
 
 var listArchived bool
+var listGroup string
 
 func init() {
 	planCmd.AddCommand(planListCmd)
 	planCmd.AddCommand(planShowCmd)
 	planCmd.AddCommand(planArchiveCmd)
 	planCmd.AddCommand(planRestoreCmd)
+	supportsOutput(planListCmd)
 	planListCmd.Flags().BoolVar(&listArchived, "archived", false, "Show archived plans")
+	planListCmd.Flags().StringVar(&listGroup, "group", "", "Show only the plans in this group")
 }
 
 func runPlan(cmd *cobra.Command, args []string) error {
 	// Check initialization
 	if !isInitialized() {
-		return fmt.Errorf("not initialized (run 'air init' first)")
+		return errNotInitialized()
 	}
 
 	// Detect mode
@@ -75,7 +79,7 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	// Case 1: Worktrees exist - work is in progress
 	if len(worktrees) > 0 {
 		fmt.Println("Work is already in progress from a previous session.")
-		fmt.Println("\nTo continue: use `air status` or `tmux attach -t air`")
+		fmt.Printf("\nTo continue: use `air status` or `tmux attach -t %s`\n", runSession())
 		fmt.Println("To start fresh: run `air clean` first")
 		return nil
 	}
@@ -83,20 +87,10 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	// Case 2: Plans exist but no worktrees - offer to extend or start fresh
 	if len(plans) > 0 {
 		fmt.Println("Found existing plans:")
-		plansDir := getPlansDir()
 		for _, name := range plans {
 			// Read objective from plan
-			content, _ := os.ReadFile(filepath.Join(plansDir, name+".md"))
-			lines := strings.Split(string(content), "\n")
-			objective := ""
-			for _, line := range lines {
-				if strings.HasPrefix(line, "**Objective:**") {
-					objective = strings.TrimPrefix(line, "**Objective:**")
-					objective = strings.TrimSpace(objective)
-					break
-				}
-			}
-			fmt.Printf("  %-15s %s\n", name, objective)
+			content, _ := os.ReadFile(planPath(name))
+			fmt.Printf("  %-15s %s\n", name, parsePlanObjective(string(content)))
 		}
 
 		fmt.Println("\nAre you:")
@@ -127,19 +121,9 @@ func runPlan(cmd *cobra.Command, args []string) error {
 		// If "e", just proceed with orchestration
 	}
 
-	// Read context
-	context, err := os.ReadFile(getContextPath())
+	orchestrationPrompt, err := buildOrchestrationPrompt(info)
 	if err != nil {
-		return fmt.Errorf("failed to read context: %w", err)
-	}
-
-	// Build orchestration prompt based on mode
-	var orchestrationPrompt string
-	if info.Mode == ModeWorkspace {
-		repoContext := buildWorkspaceRepoContext(info)
-		orchestrationPrompt = string(context) + "\n\n" + repoContext + "\n\n" + prompts.OrchestrationWorkspace
-	} else {
-		orchestrationPrompt = string(context) + "\n\n" + prompts.Orchestration
+		return err
 	}
 
 	// Launch claude with initial prompt
@@ -148,6 +132,7 @@ func runPlan(cmd *cobra.Command, args []string) error {
 		initialPrompt = fmt.Sprintf("Begin orchestration for workspace '%s' with %d repositories. Ask me what I want to build.", info.Name, len(info.Repos))
 	}
 
+	// Plans are written through 'air plan create' and checked with 'air plan validate'
 	claudeCmd := exec.Command("claude",
 		"--allowedTools", "Bash(air plan:*)",
 		"--append-system-prompt", orchestrationPrompt,
@@ -159,6 +144,22 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	return claudeCmd.Run()
 }
 
+// buildOrchestrationPrompt combines the project context with the orchestration
+// prompt for the mode
+func buildOrchestrationPrompt(info *WorkspaceInfo) (string, error) {
+	context, err := os.ReadFile(getContextPath())
+	if err != nil {
+		return "", fmt.Errorf("failed to read context: %w", err)
+	}
+	if info.Mode == ModeWorkspace {
+		repoContext := buildWorkspaceRepoContext(info)
+		return string(context) + "\n\n" + repoContext + "\n\n" + prompts.OrchestrationWorkspace, nil
+	} else if info.Mode == ModeMonorepo {
+		return string(context) + "\n\n" + prompts.Orchestration + "\n\n" + buildMonorepoComponentContext(info), nil
+	}
+	return string(context) + "\n\n" + prompts.Orchestration, nil
+}
+
 // buildWorkspaceRepoContext builds context about each repo in the workspace
 func buildWorkspaceRepoContext(info *WorkspaceInfo) string {
 	var sb strings.Builder
@@ -166,7 +167,7 @@ func buildWorkspaceRepoContext(info *WorkspaceInfo) string {
 	sb.WriteString(fmt.Sprintf("This is a multi-repo workspace '%s' containing %d repositories:\n\n", info.Name, len(info.Repos)))
 
 	for _, repo := range info.Repos {
-		repoPath := filepath.Join(info.Root, repo)
+		repoPath := info.repoDir(repo)
 		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
 
 		// Try to read CLAUDE.md
@@ -206,6 +207,33 @@ func buildWorkspaceRepoContext(info *WorkspaceInfo) string {
 	return sb.String()
 }
 
+// buildMonorepoComponentContext describes the monorepo's components and the
+// **Component:** field every plan must declare
+func buildMonorepoComponentContext(info *WorkspaceInfo) string {
+	var sb strings.Builder
+	sb.WriteString("## Monorepo Components\n\n")
+	sb.WriteString(fmt.Sprintf("This repository '%s' is organized into %d components:\n\n", info.Name, len(info.Components)))
+	for _, c := range info.Components {
+		line := "- `" + c + "`"
+		if projectType := detectProjectType(filepath.Join(info.Root, c)); projectType != "" {
+			line += " (" + projectType + ")"
+		}
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString(`
+### CRITICAL: Component Field
+
+Every plan MUST target exactly one component with a **Component:** field placed after the title (` + "`air plan create --component <component-path>`" + ` writes it):
+
+` + "```markdown" + `
+**Component:** <component-path>
+` + "```" + `
+
+Every path under **In scope:** must be inside that component's directory (e.g. ` + "`<component-path>/internal/`" + `). ` + "`air plan validate`" + ` rejects plans whose scope reaches outside their component. Work that spans components must be split into one plan per component, coordinated with channels.
+`)
+	return sb.String()
+}
+
 // detectProjectType tries to identify the project type from files
 func detectProjectType(repoPath string) string {
 	types := []struct {
@@ -235,73 +263,178 @@ func runPlanList(cmd *cobra.Command, args []string) error {
 
 	basePlansDir := getPlansDir()
 	if listArchived {
-		plansDir = filepath.Join(basePlansDir, "archive")
+		plansDir = filepath.Join(basePlansDir, archiveDirName)
 		label = "Archived Plans:"
 	} else {
 		plansDir = basePlansDir
 		label = "Plans:"
 	}
 
-	entries, err := os.ReadDir(plansDir)
+	files, err := listPlanFiles(plansDir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			if listArchived {
-				fmt.Println("No archived plans.")
-			} else {
-				fmt.Println("No plans yet. Run 'air plan' to create some.")
-			}
-			return nil
+		return err
+	}
+	var plans []planFile
+	for _, f := range files {
+		if listGroup == "" || f.group == listGroup {
+			plans = append(plans, f)
 		}
-		return fmt.Errorf("failed to read plans: %w", err)
 	}
 
-	// Filter to only .md files (exclude archive directory)
-	var plans []os.DirEntry
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
-			plans = append(plans, entry)
-		}
+	summaries := make([]planSummary, 0, len(plans))
+	for _, f := range plans {
+		content, _ := os.ReadFile(f.path)
+		deps := parsePlanDependencies(f.name, string(content))
+		summaries = append(summaries, planSummary{
+			Name:       f.name,
+			Group:      f.group,
+			Objective:  parsePlanObjective(string(content)),
+			Repository: deps.Repository,
+			Component:  deps.Component,
+			Archived:   listArchived,
+		})
 	}
 
+	return render(summaries, func() { printPlanList(label, plans) })
+}
+
+// planSummary is one plan in 'air plan list --output json|yaml'
+type planSummary struct {
+	Name       string `json:"name"`
+	Group      string `json:"group,omitempty"`
+	Objective  string `json:"objective"`
+	Repository string `json:"repository,omitempty"`
+	Component  string `json:"component,omitempty"`
+	Archived   bool   `json:"archived"`
+}
+
+// printPlanList prints plans as text, one section per plan group, grouped by
+// target within each in workspace and monorepo mode
+func printPlanList(label string, plans []planFile) {
 	if len(plans) == 0 {
-		if listArchived {
+		switch {
+		case listGroup != "":
+			fmt.Printf("No plans in group '%s'.\n", listGroup)
+		case listArchived:
 			fmt.Println("No archived plans.")
-		} else {
+		default:
 			fmt.Println("No plans yet. Run 'air plan' to create some.")
 		}
-		return nil
+		return
 	}
 
-	fmt.Println(label)
-	for _, entry := range plans {
-		name := strings.TrimSuffix(entry.Name(), ".md")
-
-		// Read first line for objective
-		content, _ := os.ReadFile(filepath.Join(plansDir, entry.Name()))
-		lines := strings.Split(string(content), "\n")
-		objective := ""
-		for _, line := range lines {
-			if strings.HasPrefix(line, "**Objective:**") {
-				objective = strings.TrimPrefix(line, "**Objective:**")
-				objective = strings.TrimSpace(objective)
-				break
+	info, _ := detectMode()
+	for i, section := range splitPlanGroups(plans) {
+		if i > 0 {
+			fmt.Println()
+		}
+		sectionLabel := label
+		if group := section[0].group; group != "" {
+			sectionLabel = fmt.Sprintf("%s (group %s)", strings.TrimSuffix(label, ":"), group) + ":"
+		}
+
+		// In workspace and monorepo mode, group plans under their target repo/component
+		switch {
+		case info != nil && info.Mode == ModeWorkspace:
+			printPlansGrouped(sectionLabel, section, info.Repos, "Repository", func(d PlanDependencies) string { return d.Repository })
+		case info != nil && info.Mode == ModeMonorepo:
+			printPlansGrouped(sectionLabel, section, info.Components, "Component", func(d PlanDependencies) string { return d.Component })
+		default:
+			fmt.Println(sectionLabel)
+			for _, f := range section {
+				content, _ := os.ReadFile(f.path)
+				fmt.Printf("  %-15s %s\n", f.name, parsePlanObjective(string(content)))
 			}
 		}
+	}
+}
 
-		fmt.Printf("  %-15s %s\n", name, objective)
+// splitPlanGroups splits plans (as ordered by listPlanFiles) into runs of the same group
+func splitPlanGroups(plans []planFile) [][]planFile {
+	var sections [][]planFile
+	for i, f := range plans {
+		if i == 0 || f.group != plans[i-1].group {
+			sections = append(sections, nil)
+		}
+		sections[len(sections)-1] = append(sections[len(sections)-1], f)
 	}
+	return sections
+}
 
-	return nil
+// printPlansGrouped prints plans grouped by a target field (**Repository:** or **Component:**).
+// Plans without the field, or naming a target that doesn't exist, are flagged.
+func printPlansGrouped(label string, plans []planFile, targets []string, field string, target func(PlanDependencies) string) {
+	type planLine struct {
+		name      string
+		objective string
+	}
+	byTarget := make(map[string][]planLine)
+	var missing []planLine
+
+	for _, f := range plans {
+		content, _ := os.ReadFile(f.path)
+		t := target(parsePlanDependencies(f.name, string(content)))
+		line := planLine{name: f.name, objective: parsePlanObjective(string(content))}
+		if t == "" {
+			missing = append(missing, line)
+			continue
+		}
+		byTarget[t] = append(byTarget[t], line)
+	}
+
+	// Known targets first (in configured order), then unknown targets alphabetically
+	var groups []string
+	for _, t := range targets {
+		if _, ok := byTarget[t]; ok {
+			groups = append(groups, t)
+		}
+	}
+	var unknown []string
+	for t := range byTarget {
+		if !contains(targets, t) {
+			unknown = append(unknown, t)
+		}
+	}
+	sort.Strings(unknown)
+	groups = append(groups, unknown...)
+
+	fmt.Println(label)
+	for _, g := range groups {
+		if contains(targets, g) {
+			fmt.Printf("\n  %s:\n", g)
+		} else {
+			fmt.Printf("\n  %s: (unknown %s)\n", g, strings.ToLower(field))
+		}
+		for _, p := range byTarget[g] {
+			fmt.Printf("    %-15s %s\n", p.name, p.objective)
+		}
+	}
+
+	if len(missing) > 0 {
+		fmt.Printf("\n  ✗ Missing **%s:** field:\n", field)
+		for _, p := range missing {
+			fmt.Printf("    %-15s %s\n", p.name, p.objective)
+		}
+	}
+}
+
+// parsePlanObjective returns the value of the **Objective:** line in a plan, if any
+func parsePlanObjective(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "**Objective:**") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "**Objective:**"))
+		}
+	}
+	return ""
 }
 
 func runPlanShow(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	planPath := filepath.Join(getPlansDir(), name+".md")
 
-	content, err := os.ReadFile(planPath)
+	content, err := os.ReadFile(planPath(name))
 	if err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("plan '%s' not found", name)
+			return errPlanNotFound(name)
 		}
 		return fmt.Errorf("failed to read plan: %w", err)
 	}
@@ -313,22 +446,24 @@ func runPlanShow(cmd *cobra.Command, args []string) error {
 func runPlanArchive(cmd *cobra.Command, args []string) error {
 	name := args[0]
 	plansDir := getPlansDir()
-	srcPath := filepath.Join(plansDir, name+".md")
-	archiveDir := filepath.Join(plansDir, "archive")
-	dstPath := filepath.Join(archiveDir, name+".md")
 
 	// Check source exists
-	if _, err := os.Stat(srcPath); os.IsNotExist(err) {
-		return fmt.Errorf("plan '%s' not found", name)
+	plan, ok := findPlanFile(plansDir, name)
+	if !ok {
+		return errPlanNotFound(name)
 	}
 
+	// Archived plans keep their group: archive/<group>/<name>.md
+	archiveDir := filepath.Join(plansDir, archiveDirName, plan.group)
+	dstPath := filepath.Join(archiveDir, name+".md")
+
 	// Create archive directory
 	if err := os.MkdirAll(archiveDir, 0755); err != nil {
 		return fmt.Errorf("failed to create archive directory: %w", err)
 	}
 
 	// Move file
-	if err := os.Rename(srcPath, dstPath); err != nil {
+	if err := os.Rename(plan.path, dstPath); err != nil {
 		return fmt.Errorf("failed to archive plan: %w", err)
 	}
 
@@ -339,21 +474,24 @@ func runPlanArchive(cmd *cobra.Command, args []string) error {
 func runPlanRestore(cmd *cobra.Command, args []string) error {
 	name := args[0]
 	plansDir := getPlansDir()
-	srcPath := filepath.Join(plansDir, "archive", name+".md")
-	dstPath := filepath.Join(plansDir, name+".md")
 
 	// Check source exists
-	if _, err := os.Stat(srcPath); os.IsNotExist(err) {
+	archived, ok := findPlanFile(filepath.Join(plansDir, archiveDirName), name)
+	if !ok {
 		return fmt.Errorf("archived plan '%s' not found", name)
 	}
 
 	// Check destination doesn't exist
-	if _, err := os.Stat(dstPath); err == nil {
+	if _, exists := findPlanFile(plansDir, name); exists {
 		return fmt.Errorf("plan '%s' already exists (not archived)", name)
 	}
+	dstDir := filepath.Join(plansDir, archived.group)
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		return fmt.Errorf("failed to create plan group directory: %w", err)
+	}
 
 	// Move file
-	if err := os.Rename(srcPath, dstPath); err != nil {
+	if err := os.Rename(archived.path, filepath.Join(dstDir, name+".md")); err != nil {
 		return fmt.Errorf("failed to restore plan: %w", err)
 	}
 
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var planAutoCmd = &cobra.Command{
+	Use:   "auto <goal>",
+	Short: "Plan a goal without an interactive session",
+	Long: `Runs the orchestration prompt headless ('claude -p') on goal, writes the plans
+it proposes as 'air plan create' would, validates them, and prints the
+dependency graph. Nothing asks for input, so it can be scripted:
+
+  air plan auto "Add rate limiting to the public API" && air run
+
+Plans are written with the same checks as 'air plan create': a plan with
+invalid fields isn't written, and the command fails. Existing plans of the same
+name are kept unless --force. With --group, the plans go in plans/<group>/.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPlanAuto,
+}
+
+var autoGroup string
+var autoForce bool
+var autoModel string
+
+func init() {
+	planCmd.AddCommand(planAutoCmd)
+	planAutoCmd.Flags().StringVar(&autoGroup, "group", "", "Plan group to write into (plans/<group>/)")
+	planAutoCmd.Flags().BoolVar(&autoForce, "force", false, "Overwrite existing plans of the same name")
+	planAutoCmd.Flags().StringVar(&autoModel, "model", "", "Model to plan with (default: claude's)")
+}
+
+// autoPlan is one plan in the headless planner's reply, with the fields of
+// 'air plan create'
+type autoPlan struct {
+	Name            string   `json:"name"`
+	Objective       string   `json:"objective"`
+	Repository      string   `json:"repository,omitempty"`
+	Component       string   `json:"component,omitempty"`
+	Phase           int      `json:"phase,omitempty"`
+	Scope           []string `json:"scope"`
+	OutOfScope      []string `json:"out_of_scope,omitempty"`
+	Criteria        []string `json:"criteria"`
+	WaitsOn         []string `json:"waits_on,omitempty"`
+	WaitsOnOptional []string `json:"waits_on_optional,omitempty"`
+	External        []string `json:"external,omitempty"`
+	Signals         []string `json:"signals,omitempty"`
+	Consumes        []string `json:"consumes,omitempty"`
+	ContextFiles    []string `json:"context_files,omitempty"`
+	CommitFormat    string   `json:"commit_format,omitempty"`
+	Notes           string   `json:"notes,omitempty"`
+}
+
+// spec converts p to the flags 'air plan create' would have been given
+func (p autoPlan) spec() planSpec {
+	return planSpec{
+		name:         p.Name,
+		group:        autoGroup,
+		objective:    p.Objective,
+		repository:   p.Repository,
+		component:    p.Component,
+		phase:        p.Phase,
+		scope:        p.Scope,
+		outOfScope:   p.OutOfScope,
+		criteria:     p.Criteria,
+		waitsOn:      p.WaitsOn,
+		optional:     p.WaitsOnOptional,
+		external:     p.External,
+		signals:      p.Signals,
+		consumes:     p.Consumes,
+		contextFiles: p.ContextFiles,
+		commitFormat: p.CommitFormat,
+		notes:        p.Notes,
+		force:        autoForce,
+	}
+}
+
+// headlessPlanning replaces the interactive steps of the orchestration prompt
+const headlessPlanning = `
+
+## Headless Planning
+
+You are running non-interactively: there is no user to ask, and you must not
+run any commands. Decide the plans yourself from the goal and the context
+above. Instead of calling 'air plan create', reply with only a JSON array, one
+object per plan, using the fields of 'air plan create' (list fields take the
+same "<name> [annotation] [- description]" entries):
+
+[{"name": "...", "objective": "...", "repository": "...", "component": "...",
+  "phase": 0, "scope": ["..."], "out_of_scope": ["..."], "criteria": ["..."],
+  "waits_on": ["..."], "waits_on_optional": ["..."], "external": ["..."],
+  "signals": ["..."], "consumes": ["..."], "context_files": ["..."],
+  "commit_format": "...", "notes": "..."}]
+
+Omit fields that don't apply. name, objective, scope, and criteria are required.`
+
+func runPlanAuto(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+	if len(getExistingWorktrees()) > 0 {
+		return withCode(codeRunActive, fmt.Errorf("work is already in progress; use 'air status', or 'air clean' to start fresh"))
+	}
+
+	prompt, err := buildOrchestrationPrompt(info)
+	if err != nil {
+		return err
+	}
+	claudeArgs := []string{"-p", "--append-system-prompt", prompt + headlessPlanning}
+	if autoModel != "" {
+		claudeArgs = append(claudeArgs, "--model", autoModel)
+	}
+	claudeArgs = append(claudeArgs, "Plan this goal: "+args[0])
+
+	fmt.Println("Planning...")
+	claudeCmd := exec.Command("claude", claudeArgs...)
+	claudeCmd.Stderr = os.Stderr
+	out, err := claudeCmd.Output()
+	if err != nil {
+		return fmt.Errorf("headless planning failed: %w", err)
+	}
+	plans, err := parseAutoPlans(string(out))
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, p := range plans {
+		if err := writePlanSpec(info, p.spec()); err != nil {
+			fmt.Printf("  ✗ %s: %v\n", p.Name, err)
+			failed = append(failed, p.Name)
+		}
+	}
+	fmt.Println()
+	graphErr := printPlanGraph(info, autoGroup)
+	if len(failed) > 0 {
+		return withCode(codeValidationFailed, fmt.Errorf("%d of %d plans not written: %s", len(failed), len(plans), strings.Join(failed, ", ")))
+	}
+	return graphErr
+}
+
+// parseAutoPlans reads the JSON array of plans from the planner's reply,
+// ignoring any prose or code fence around it
+func parseAutoPlans(reply string) ([]autoPlan, error) {
+	start, end := strings.Index(reply, "["), strings.LastIndex(reply, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("planner replied without plans:\n%s", strings.TrimSpace(reply))
+	}
+	var plans []autoPlan
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &plans); err != nil {
+		return nil, fmt.Errorf("planner's plans aren't valid JSON: %w", err)
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("planner proposed no plans")
+	}
+	return plans, nil
+}
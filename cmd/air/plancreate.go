@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var planCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Write a plan from flags",
+	Long: `Writes ~/.air/<project>/plans/<name>.md in the standard plan format, so plans
+created during orchestration are consistently structured and checked. With
+--group, the plan goes in plans/<group>/<name>.md instead.
+
+List flags are repeatable. Channel and scope entries take the form
+"<name> [annotation] [- description]", e.g.
+  --waits-on "schema-ready (timeout: 45m, fallback: ask) - Generated protos"
+  --scope "internal/auth/ - Token handling"
+
+The plan's own fields (name, repository/component, wait annotations, context
+files) must be valid or nothing is written. Dependency graph issues, such as a
+channel no plan signals yet, are reported but don't block: they may resolve as
+the remaining plans are created.`,
+	Args: cobra.NoArgs,
+	RunE: runPlanCreate,
+}
+
+// planSpec holds the flags for 'air plan create'
+type planSpec struct {
+	name         string
+	group        string
+	objective    string
+	repository   string
+	component    string
+	phase        int
+	scope        []string
+	outOfScope   []string
+	criteria     []string
+	waitsOn      []string
+	optional     []string
+	external     []string
+	signals      []string
+	consumes     []string
+	contextFiles []string
+	commitFormat string
+	notes        string
+	force        bool
+}
+
+var createSpec planSpec
+
+// planNameRegex matches valid plan names (used for branches air/<name> and tmux windows)
+var planNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+func init() {
+	planCmd.AddCommand(planCreateCmd)
+
+	f := planCreateCmd.Flags()
+	f.StringVar(&createSpec.name, "name", "", "Plan name (lowercase letters, digits, hyphens)")
+	f.StringVar(&createSpec.group, "group", "", "Plan group to write into (plans/<group>/)")
+	f.StringVar(&createSpec.objective, "objective", "", "One sentence describing what \"done\" looks like")
+	f.StringVar(&createSpec.repository, "repository", "", "Target repository (workspace mode)")
+	f.StringVar(&createSpec.component, "component", "", "Target component (monorepo mode)")
+	f.IntVar(&createSpec.phase, "phase", 0, "Phase the plan belongs to (see 'air run --phase')")
+	f.StringArrayVar(&createSpec.scope, "scope", nil, "Path this agent should touch (repeatable)")
+	f.StringArrayVar(&createSpec.outOfScope, "out-of-scope", nil, "What this agent should not modify (repeatable)")
+	f.StringArrayVar(&createSpec.criteria, "criteria", nil, "Specific, verifiable acceptance criterion (repeatable)")
+	f.StringArrayVar(&createSpec.waitsOn, "waits-on", nil, "Channel to wait on (repeatable)")
+	f.StringArrayVar(&createSpec.optional, "waits-on-optional", nil, "Channel to wait on for a bounded time, then proceed (repeatable)")
+	f.StringArrayVar(&createSpec.external, "external", nil, "Waited-on channel signaled outside the plans (repeatable)")
+	f.StringArrayVar(&createSpec.signals, "signals", nil, "Channel this plan signals (repeatable)")
+	f.StringArrayVar(&createSpec.consumes, "consumes", nil, "Upstream <repo>:<path>[ -> <dest>] to copy in after a wait (repeatable, workspace mode)")
+	f.StringArrayVar(&createSpec.contextFiles, "context-file", nil, "File in the context/ directory to append to this agent's prompt (repeatable)")
+	f.StringVar(&createSpec.commitFormat, "commit-format", "", "Commit subject format for this agent, e.g. '<type>({plan}): <summary>'")
+	f.StringVar(&createSpec.notes, "notes", "", "Additional context for the agent")
+	f.BoolVar(&createSpec.force, "force", false, "Overwrite an existing plan")
+}
+
+func runPlanCreate(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+
+	if err := writePlanSpec(info, createSpec); err != nil {
+		return err
+	}
+
+	// Report graph issues across all plans without failing
+	if _, graphErrs := ValidatePlansWithMode(info); len(graphErrs) > 0 {
+		fmt.Println("\nOutstanding issues across plans (may resolve as you create the rest):")
+		for _, err := range graphErrs {
+			fmt.Printf("  - %s\n", err)
+		}
+	}
+	return nil
+}
+
+// writePlanSpec checks spec's own fields and writes it as a plan
+func writePlanSpec(info *WorkspaceInfo, spec planSpec) error {
+	if !planNameRegex.MatchString(spec.name) {
+		return fmt.Errorf("--name must be lowercase letters, digits, and hyphens (got '%s')", spec.name)
+	}
+	if strings.TrimSpace(spec.objective) == "" {
+		return fmt.Errorf("--objective is required")
+	}
+	if len(spec.scope) == 0 {
+		return fmt.Errorf("at least one --scope is required")
+	}
+	if len(spec.criteria) == 0 {
+		return fmt.Errorf("at least one --criteria is required")
+	}
+
+	if spec.group != "" && (!planNameRegex.MatchString(spec.group) || spec.group == archiveDirName) {
+		return fmt.Errorf("--group must be lowercase letters, digits, and hyphens, and not '%s' (got '%s')", archiveDirName, spec.group)
+	}
+
+	groupDir := filepath.Join(getPlansDir(), spec.group)
+	planPath := filepath.Join(groupDir, spec.name+".md")
+	if existing, ok := findPlanFile(getPlansDir(), spec.name); ok {
+		// Plan names are unique across groups, so --force can't add a second one
+		if existing.group != spec.group {
+			return fmt.Errorf("plan '%s' already exists in group '%s'", spec.name, groupLabel(existing.group))
+		}
+		if !spec.force {
+			return fmt.Errorf("plan '%s' already exists (use --force to overwrite)", spec.name)
+		}
+	}
+
+	// Check the plan's own fields by parsing what will be written
+	content := renderPlan(spec)
+	pd := parsePlanDependencies(spec.name, content)
+	var errs []error
+	switch info.Mode {
+	case ModeWorkspace:
+		errs = append(errs, validateRepositoryReferences([]PlanDependencies{pd}, info)...)
+	case ModeMonorepo:
+		errs = append(errs, validateComponentReferences([]PlanDependencies{pd}, info)...)
+	}
+	errs = append(errs, validateWaitPolicies([]PlanDependencies{pd})...)
+	errs = append(errs, validateContextFiles([]PlanDependencies{pd}, getContextDir())...)
+	errs = append(errs, validatePhases([]PlanDependencies{pd})...)
+	if len(errs) > 0 {
+		fmt.Println("Plan not written:")
+		for _, err := range errs {
+			fmt.Printf("  ✗ %s\n", err)
+		}
+		return withCode(codeValidationFailed, fmt.Errorf("invalid plan '%s'", spec.name))
+	}
+
+	if err := os.MkdirAll(groupDir, 0755); err != nil {
+		return fmt.Errorf("failed to create plans directory: %w", err)
+	}
+	if err := os.WriteFile(planPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	fmt.Printf("Wrote %s\n", planPath)
+	return nil
+}
+
+// renderPlan formats spec in the standard plan layout
+func renderPlan(spec planSpec) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Plan: %s\n\n", spec.name)
+
+	if spec.repository != "" {
+		fmt.Fprintf(&sb, "**Repository:** %s\n\n", spec.repository)
+	}
+	if spec.component != "" {
+		fmt.Fprintf(&sb, "**Component:** %s\n\n", spec.component)
+	}
+	if spec.phase > 0 {
+		fmt.Fprintf(&sb, "**Phase:** %d\n\n", spec.phase)
+	}
+	if len(spec.consumes) > 0 {
+		fmt.Fprintf(&sb, "**Consumes:** %s\n\n", strings.Join(spec.consumes, ", "))
+	}
+	if len(spec.contextFiles) > 0 {
+		fmt.Fprintf(&sb, "**Context files:** %s\n\n", strings.Join(spec.contextFiles, ", "))
+	}
+	if spec.commitFormat != "" {
+		fmt.Fprintf(&sb, "**Commit format:** `%s`\n\n", spec.commitFormat)
+	}
+	fmt.Fprintf(&sb, "**Objective:** %s\n\n", strings.TrimSpace(spec.objective))
+
+	sb.WriteString("## Boundaries\n\n**In scope:**\n")
+	for _, item := range spec.scope {
+		sb.WriteString(renderListItem(item))
+	}
+	if len(spec.outOfScope) > 0 {
+		sb.WriteString("\n**Out of scope:**\n")
+		for _, item := range spec.outOfScope {
+			sb.WriteString("- " + strings.TrimSpace(item) + "\n")
+		}
+	}
+
+	sb.WriteString("\n## Acceptance Criteria\n\n")
+	for _, c := range spec.criteria {
+		sb.WriteString("- [ ] " + strings.TrimSpace(c) + "\n")
+	}
+
+	if len(spec.waitsOn)+len(spec.optional)+len(spec.external)+len(spec.signals) > 0 {
+		sb.WriteString("\n## Dependencies\n")
+		for _, section := range []struct {
+			header string
+			items  []string
+		}{
+			{"**Waits on:**", spec.waitsOn},
+			{"**Waits on (optional):**", spec.optional},
+			{"**External:**", spec.external},
+			{"**Signals:**", spec.signals},
+		} {
+			if len(section.items) == 0 {
+				continue
+			}
+			sb.WriteString("\n" + section.header + "\n")
+			for _, item := range section.items {
+				sb.WriteString(renderListItem(item))
+			}
+		}
+
+		sb.WriteString("\n**Sequence:**\n")
+		step := 1
+		if len(spec.waitsOn)+len(spec.optional) > 0 {
+			fmt.Fprintf(&sb, "%d. Run the wait commands under **Coordination** in your assignment before starting dependent work\n", step)
+			step++
+		}
+		fmt.Fprintf(&sb, "%d. Do implementation work\n", step)
+		fmt.Fprintf(&sb, "%d. Commit changes\n", step+1)
+		step += 2
+		for _, item := range spec.signals {
+			name, _ := splitListItem(item)
+			fmt.Fprintf(&sb, "%d. Run `air agent signal %s` when its work is committed\n", step, name)
+			step++
+		}
+		fmt.Fprintf(&sb, "%d. Run `air agent done` when complete\n", step)
+	}
+
+	if notes := strings.TrimSpace(spec.notes); notes != "" {
+		sb.WriteString("\n## Notes\n\n" + notes + "\n")
+	}
+
+	return sb.String()
+}
+
+// splitListItem splits a list flag value into its leading channel or path and
+// the remaining annotation/description
+func splitListItem(item string) (name, rest string) {
+	name, rest, _ = strings.Cut(strings.TrimSpace(item), " ")
+	return strings.Trim(name, "`"), strings.TrimSpace(rest)
+}
+
+// renderListItem formats "<name> [rest]" as "- `<name>` [rest]"
+func renderListItem(item string) string {
+	name, rest := splitListItem(item)
+	if rest == "" {
+		return "- `" + name + "`\n"
+	}
+	return "- `" + name + "` " + rest + "\n"
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Plans live in plans/<name>.md, or in a group subdirectory such as
+// plans/m1-auth/<name>.md. Plan names stay unique across groups, since agents,
+// branches, and worktrees are named after them, and so do channel names, since
+// groups share the channels directory. plans/archive/ is not a group.
+
+// archiveDirName is the plans/ subdirectory holding archived plans
+const archiveDirName = "archive"
+
+// planFile is a plan on disk
+type planFile struct {
+	name  string
+	group string // "" for plans directly in plans/
+	path  string
+}
+
+// listPlanFiles returns the plans in dir: ungrouped plans first, then each
+// group's plans, groups in name order
+func listPlanFiles(dir string) ([]planFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read plans: %w", err)
+	}
+
+	var plans, grouped []planFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() {
+			if plan, ok := strings.CutSuffix(name, ".md"); ok {
+				plans = append(plans, planFile{name: plan, path: filepath.Join(dir, name)})
+			}
+			continue
+		}
+		if name == archiveDirName || strings.HasPrefix(name, ".") {
+			continue
+		}
+		groupEntries, err := os.ReadDir(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plan group %s: %w", name, err)
+		}
+		for _, ge := range groupEntries {
+			if plan, ok := strings.CutSuffix(ge.Name(), ".md"); ok && !ge.IsDir() {
+				grouped = append(grouped, planFile{name: plan, group: name, path: filepath.Join(dir, name, ge.Name())})
+			}
+		}
+	}
+	return append(plans, grouped...), nil
+}
+
+// findPlanFile returns the named plan in dir, in any group
+func findPlanFile(dir, name string) (planFile, bool) {
+	plans, _ := listPlanFiles(dir)
+	for _, p := range plans {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return planFile{}, false
+}
+
+// planPath returns the file of the named plan, or where an ungrouped plan of
+// that name would be if there is none
+func planPath(name string) string {
+	if p, ok := findPlanFile(getPlansDir(), name); ok {
+		return p.path
+	}
+	return filepath.Join(getPlansDir(), name+".md")
+}
+
+// planGroups returns the groups the plans belong to, in order of first
+// appearance ("" for ungrouped plans)
+func planGroups(plans []PlanDependencies) []string {
+	var groups []string
+	seen := make(map[string]bool)
+	for _, p := range plans {
+		if !seen[p.Group] {
+			seen[p.Group] = true
+			groups = append(groups, p.Group)
+		}
+	}
+	return groups
+}
+
+// plansInGroup returns the plans belonging to group
+func plansInGroup(plans []PlanDependencies, group string) []PlanDependencies {
+	var in []PlanDependencies
+	for _, p := range plans {
+		if p.Group == group {
+			in = append(in, p)
+		}
+	}
+	return in
+}
+
+// duplicatePlanErrors reports plan names used in more than one group
+func duplicatePlanErrors(plans []PlanDependencies) []error {
+	groupsByName := make(map[string][]string)
+	for _, p := range plans {
+		groupsByName[p.Name] = append(groupsByName[p.Name], groupLabel(p.Group))
+	}
+	var names []string
+	for name, groups := range groupsByName {
+		if len(groups) > 1 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, ValidationError{
+			Message: fmt.Sprintf("plan '%s' is defined in more than one group (%s); plan names must be unique", name, strings.Join(groupsByName[name], ", ")),
+		})
+	}
+	return errs
+}
+
+// sharedChannels returns the channels used by plans of more than one group,
+// with those groups. All groups signal into the same channels directory, so
+// one group's signal would satisfy or overwrite another's.
+func sharedChannels(plans []PlanDependencies) map[string][]string {
+	groupsByChannel := make(map[string][]string)
+	for _, p := range plans {
+		for _, list := range [][]string{p.Signals, p.WaitsOn, p.Optional, p.External} {
+			for _, channel := range list {
+				if label := groupLabel(p.Group); !contains(groupsByChannel[channel], label) {
+					groupsByChannel[channel] = append(groupsByChannel[channel], label)
+				}
+			}
+		}
+	}
+	for channel, groups := range groupsByChannel {
+		if len(groups) < 2 {
+			delete(groupsByChannel, channel)
+		}
+	}
+	return groupsByChannel
+}
+
+// sharedChannelErrors reports channels used by more than one group, or only
+// those used by group if involving is set
+func sharedChannelErrors(plans []PlanDependencies, group string, involving bool) []error {
+	shared := sharedChannels(plans)
+	var channels []string
+	for channel, groups := range shared {
+		if !involving || contains(groups, groupLabel(group)) {
+			channels = append(channels, channel)
+		}
+	}
+	sort.Strings(channels)
+
+	var errs []error
+	for _, channel := range channels {
+		errs = append(errs, ValidationError{
+			Message: fmt.Sprintf("channel '%s' is used in more than one group (%s); groups share the channels directory, so channel names must be unique", channel, strings.Join(shared[channel], ", ")),
+		})
+	}
+	return errs
+}
+
+// groupLabel names a group for messages
+func groupLabel(group string) string {
+	if group == "" {
+		return "ungrouped"
+	}
+	return group
+}
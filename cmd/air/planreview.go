@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var planReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Have Claude critique the plans before they run",
+	Long: `Starts a read-only Claude session on the current plan set that looks for
+gaps in the work, overlapping boundaries between plans, missing or wrong
+dependencies, and vague acceptance criteria. It can read the code and the
+plans but can't change anything.
+
+The findings are printed and written to ~/.air/<project>/plan-review.md (or
+plan-review-<group>.md with --group), so they can be worked through with
+'air plan create --force' or 'air plan' before 'air run'.`,
+	Args: cobra.NoArgs,
+	RunE: runPlanReview,
+}
+
+var reviewGroup string
+var reviewModel string
+
+func init() {
+	planCmd.AddCommand(planReviewCmd)
+	planReviewCmd.Flags().StringVar(&reviewGroup, "group", "", "Review only the plans in this group")
+	planReviewCmd.Flags().StringVar(&reviewModel, "model", "", "Model to review with (default: claude's)")
+}
+
+// reviewTools are the read-only tools the reviewer may use
+const reviewTools = `Read Grep Glob Bash(git log:*) Bash(git show:*) Bash(git ls-files:*) Bash(air plan list:*) Bash(air plan show:*) Bash(air plan validate:*)`
+
+// reviewPath returns where the review of group's plans is written
+func reviewPath(group string) string {
+	name := "plan-review.md"
+	if group != "" {
+		name = "plan-review-" + group + ".md"
+	}
+	return filepath.Join(mustGetAirDir(), name)
+}
+
+func runPlanReview(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+	var plans []PlanDependencies
+	var errs []error
+	if reviewGroup != "" {
+		plans, errs = ValidatePlanGroup(info, reviewGroup)
+	} else {
+		plans, errs = ValidatePlansWithMode(info)
+	}
+	if len(plans) == 0 {
+		fmt.Println("No plans to review. Create them with 'air plan'.")
+		return nil
+	}
+
+	context, err := buildOrchestrationPrompt(info)
+	if err != nil {
+		return err
+	}
+	claudeArgs := []string{"-p",
+		"--allowedTools", reviewTools,
+		"--disallowedTools", "Edit Write NotebookEdit",
+		"--append-system-prompt", context + "\n\n" + buildReviewContext(plans, errs)}
+	if reviewModel != "" {
+		claudeArgs = append(claudeArgs, "--model", reviewModel)
+	}
+	claudeArgs = append(claudeArgs, "Review these plans before they run. Report your findings as described under Plan Review.")
+
+	fmt.Printf("Reviewing %d plans...\n\n", len(plans))
+	claudeCmd := exec.Command("claude", claudeArgs...)
+	claudeCmd.Dir = info.Root
+	claudeCmd.Stderr = os.Stderr
+	out, err := claudeCmd.Output()
+	if err != nil {
+		return fmt.Errorf("plan review failed: %w", err)
+	}
+	findings := strings.TrimSpace(string(out))
+	fmt.Println(findings)
+
+	names := make([]string, 0, len(plans))
+	for _, p := range plans {
+		names = append(names, p.Name)
+	}
+	review := fmt.Sprintf("# Plan Review\n\nReviewed: %s\nPlans: %s\n\n%s\n",
+		time.Now().Format(time.RFC822), strings.Join(names, ", "), findings)
+	path := reviewPath(reviewGroup)
+	if err := os.WriteFile(path, []byte(review), 0644); err != nil {
+		return fmt.Errorf("failed to write review: %w", err)
+	}
+	fmt.Printf("\nReview written to %s\n", path)
+	return nil
+}
+
+// buildReviewContext gives the reviewer the plans, what 'air plan validate'
+// already found, and what to look for
+func buildReviewContext(plans []PlanDependencies, errs []error) string {
+	var sb strings.Builder
+	sb.WriteString("## Plan Review\n\n")
+	sb.WriteString("You are reviewing plans written by someone else, not writing them, and you can't change anything. ")
+	sb.WriteString("Read the code the plans touch where it helps. Look for:\n\n")
+	sb.WriteString("- Gaps: work the goal needs that no plan covers\n")
+	sb.WriteString("- Overlapping boundaries: plans whose **In scope:** paths overlap, so their agents would edit the same files\n")
+	sb.WriteString("- Missing dependencies: a plan that needs another's output without waiting on a channel it signals, or waits it doesn't need\n")
+	sb.WriteString("- Vague acceptance criteria: criteria an agent couldn't check for itself\n\n")
+	sb.WriteString("Reply with only the findings in markdown: one section per problem, naming the plans involved and the change you suggest, most serious first. ")
+	sb.WriteString("If the plans are ready to run, say so in one line.\n\n")
+
+	if len(errs) > 0 {
+		sb.WriteString("### Already Found by 'air plan validate'\n\n")
+		for _, err := range errs {
+			fmt.Fprintf(&sb, "- %s\n", err)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("### Plans\n\n")
+	for _, p := range plans {
+		content, err := os.ReadFile(filepath.Join(getPlansDir(), p.Group, p.Name+".md"))
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "```markdown\n%s\n```\n\n", strings.TrimSpace(string(content)))
+	}
+	return sb.String()
+}
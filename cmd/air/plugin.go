@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// runPlugin runs 'air <name> args...' as the executable air-<name> on PATH,
+// git-style, when <name> isn't a built-in command. ok is false if it isn't a
+// plugin, leaving the arguments to cobra (and its unknown command error).
+func runPlugin(args []string) (code int, ok bool) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return 0, false
+	}
+	if cmd, _, err := rootCmd.Find(args); err == nil && cmd != rootCmd {
+		return 0, false
+	}
+	path, err := exec.LookPath("air-" + args[0])
+	if err != nil {
+		return 0, false
+	}
+
+	plugin := exec.Command(path, args[1:]...)
+	plugin.Stdin = os.Stdin
+	plugin.Stdout = os.Stdout
+	plugin.Stderr = os.Stderr
+	plugin.Env = append(os.Environ(), pluginEnv()...)
+	if err := plugin.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), true
+		}
+		return reportError(os.Stderr, fmt.Errorf("failed to run plugin %s: %w", path, err)), true
+	}
+	return 0, true
+}
+
+// pluginEnv describes air and the current project to a plugin: AIR_BIN,
+// AIR_VERSION, and AIR_ROOT (~/.air) always, and AIR_PROJECT,
+// AIR_PROJECT_ROOT, AIR_DIR (its state under ~/.air/), and AIR_MODE when run
+// inside an initialized project
+func pluginEnv() []string {
+	env := []string{"AIR_BIN=" + airExecutable(), "AIR_VERSION=" + version}
+	if root, err := getAirRoot(); err == nil {
+		env = append(env, "AIR_ROOT="+root)
+	}
+	if !isInitialized() {
+		return env
+	}
+	root, _ := getProjectRoot()
+	project, _ := getProjectName()
+	env = append(env, "AIR_PROJECT="+project, "AIR_PROJECT_ROOT="+root, "AIR_DIR="+mustGetAirDir())
+	if info, err := detectMode(); err == nil {
+		env = append(env, "AIR_MODE="+string(info.Mode))
+		if info.Mode == ModeWorkspace {
+			env = append(env, "AIR_WORKSPACE="+info.Name)
+		}
+	}
+	return env
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/scotro/air/cmd/air/prompts"
+	"github.com/spf13/cobra"
+)
+
+var postmortemCmd = &cobra.Command{
+	Use:   "postmortem <plan>",
+	Short: "Report why a plan's agent failed",
+	Long: `Assembles one report on a plan's agent: how it ended, what blocked it
+(escalations, timed-out waits, merge conflicts, retries, 'air agent fail'), the
+plan, the tail of its Claude transcript, and the commits and diff it left.
+
+It reads the latest failure snapshot in agents/<plan>/failures/ when there is
+one, so it works after 'air clean'; otherwise it reads the live worktree.
+
+With --analyze, starts a Claude session with the report to find the root cause
+and suggest plan changes.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPostmortem,
+}
+
+var postmortemAnalyze bool
+var postmortemTail int
+
+func init() {
+	postmortemCmd.Flags().BoolVar(&postmortemAnalyze, "analyze", false, "Start Claude to analyze the root cause and suggest plan changes")
+	postmortemCmd.Flags().IntVar(&postmortemTail, "tail", 30, "Transcript steps to include")
+}
+
+func runPostmortem(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+
+	snapshot := ""
+	if snapshots := listFailureSnapshots(name); len(snapshots) > 0 {
+		snapshot = snapshots[len(snapshots)-1]
+	}
+	plan, err := os.ReadFile(planPath(name))
+	if err != nil && snapshot != "" {
+		plan, err = os.ReadFile(filepath.Join(snapshot, "plan.md"))
+	}
+	if err != nil {
+		return errPlanNotFound(name)
+	}
+
+	pd := parsePlanDependencies(name, string(plan))
+	if plans, err := loadAllPlanDependencies(); err == nil {
+		for _, p := range plans {
+			if p.Name == name {
+				pd = p
+			}
+		}
+	}
+	report := buildPostmortem(info, pd, string(plan), snapshot)
+	if !postmortemAnalyze {
+		fmt.Print(report)
+		fmt.Printf("\nAnalyze with 'air postmortem %s --analyze', or re-plan with 'air plan revise %s'.\n", name, name)
+		return nil
+	}
+
+	context, err := os.ReadFile(getContextPath())
+	if err != nil {
+		return fmt.Errorf("failed to read context: %w", err)
+	}
+	orchestration := prompts.Orchestration
+	if info.Mode == ModeWorkspace {
+		orchestration = buildWorkspaceRepoContext(info) + "\n\n" + prompts.OrchestrationWorkspace
+	} else if info.Mode == ModeMonorepo {
+		orchestration += "\n\n" + buildMonorepoComponentContext(info)
+	}
+	systemPrompt := string(context) + "\n\n" + orchestration + "\n\n" + report
+
+	initialPrompt := fmt.Sprintf("Find the root cause of why plan '%s' failed, using the postmortem report. Say whether the plan, its dependencies, or the environment was at fault, then suggest concrete changes to the plan. Ask before writing anything.", name)
+	claudeCmd := exec.Command("claude",
+		"--allowedTools", "Bash(air plan:*)",
+		"--append-system-prompt", systemPrompt,
+		initialPrompt)
+	claudeCmd.Stdin = os.Stdin
+	claudeCmd.Stdout = os.Stdout
+	claudeCmd.Stderr = os.Stderr
+	return claudeCmd.Run()
+}
+
+// buildPostmortem assembles the report on pd's agent, from snapshot when set
+// or else from its worktree and branch
+func buildPostmortem(info *WorkspaceInfo, pd PlanDependencies, plan, snapshot string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Postmortem: %s\n\n", pd.Name)
+
+	outcome := "didn't finish (never ran 'air agent done' or 'air agent fail')"
+	if f := readFailure(pd.Name); f != nil {
+		outcome = "failed: " + f.Message
+	} else if _, ok := readDoneSummaries()[pd.Name]; ok {
+		outcome = "done"
+	} else if data := readSnapshotFile(snapshot, "failure.json"); data != nil {
+		var f ChannelPayload
+		if json.Unmarshal(data, &f) == nil {
+			outcome = "failed: " + f.Message
+		}
+	}
+	fmt.Fprintf(&sb, "Outcome: %s\n", outcome)
+	if snapshot != "" {
+		fmt.Fprintf(&sb, "Snapshot: %s\n", snapshot)
+	}
+	sb.WriteString("\n### What Happened\n\n")
+	for _, h := range planHistory(pd.Name) {
+		fmt.Fprintf(&sb, "- %s\n", h)
+	}
+
+	sb.WriteString("\n### Plan\n\n```markdown\n")
+	sb.WriteString(strings.TrimSpace(plan))
+	sb.WriteString("\n```\n\n")
+
+	_, _, wtPath := agentPaths(info, pd)
+	transcripts := transcriptFiles(wtPath)
+	if snapshot != "" {
+		transcripts, _ = filepath.Glob(filepath.Join(snapshot, "transcripts", "*.jsonl"))
+	}
+	var pane []string
+	if data := readSnapshotFile(snapshot, "pane.log"); data != nil {
+		pane = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+		if len(pane) > postmortemTail {
+			pane = pane[len(pane)-postmortemTail:]
+		}
+	}
+	sb.WriteString("### Last Activity\n\n")
+	if steps := transcriptTail(transcripts, postmortemTail); len(steps) > 0 {
+		sb.WriteString("From its Claude transcript, oldest first:\n\n")
+		for _, step := range steps {
+			fmt.Fprintf(&sb, "- %s\n", step)
+		}
+	} else if len(pane) > 0 {
+		sb.WriteString("From its tmux pane:\n\n```\n")
+		sb.WriteString(strings.Join(pane, "\n"))
+		sb.WriteString("\n```\n")
+	} else {
+		sb.WriteString("No transcript found.\n")
+	}
+	sb.WriteString("\n")
+
+	if snapshot == "" {
+		sb.WriteString(buildBranchContext(info, pd))
+		return sb.String()
+	}
+	sb.WriteString("### Work at the Time of Failure\n\n")
+	commits := readSnapshotFile(snapshot, "commits.txt")
+	if strings.TrimSpace(string(commits)) == "" {
+		sb.WriteString("No commits.\n\n")
+	} else {
+		sb.WriteString("Commits:\n```\n")
+		sb.Write(commits)
+		sb.WriteString("```\n\n")
+	}
+	if status := readSnapshotFile(snapshot, "status.txt"); len(status) > 0 {
+		sb.WriteString("Uncommitted files:\n```\n")
+		sb.Write(status)
+		sb.WriteString("```\n\n")
+	}
+	if diff := readSnapshotFile(snapshot, "diff.patch"); len(diff) > 0 {
+		text := string(diff)
+		truncated := len(text) > reviseDiffLimit
+		if truncated {
+			text = text[:reviseDiffLimit]
+		}
+		sb.WriteString("Diff against its base, including uncommitted edits:\n```diff\n")
+		sb.WriteString(text)
+		if truncated {
+			fmt.Fprintf(&sb, "\n... (truncated; see %s)", filepath.Join(snapshot, "diff.patch"))
+		}
+		sb.WriteString("\n```\n")
+	}
+	return sb.String()
+}
+
+// readSnapshotFile returns a file from a failure snapshot, or nil if there is
+// no snapshot or it lacks the file
+func readSnapshotFile(snapshot, name string) []byte {
+	if snapshot == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(snapshot, name))
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -27,3 +27,23 @@ var OrchestrationWorkspace string
 //
 //go:embed integration.md
 var Integration string
+
+// StackGo is added to context.md by 'air init' for Go projects.
+//
+//go:embed stacks/go.md
+var StackGo string
+
+// StackNode is added to context.md by 'air init' for Node.js/TypeScript projects.
+//
+//go:embed stacks/node.md
+var StackNode string
+
+// StackPython is added to context.md by 'air init' for Python projects.
+//
+//go:embed stacks/python.md
+var StackPython string
+
+// StackRust is added to context.md by 'air init' for Rust projects.
+//
+//go:embed stacks/rust.md
+var StackRust string
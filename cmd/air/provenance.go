@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// mergeTrailers are the provenance trailers of the commit merging plan's
+// branch, tracing history back to the plan and run that produced it
+func mergeTrailers(plan, runID string) string {
+	return fmt.Sprintf("Air-Plan: %s\nAir-Run: %s", plan, runID)
+}
+
+// mergeNotesEnabled reports whether 'air integrate --auto' attaches the done
+// payload to merge commits in repoPath as a git note under refs/notes/air:
+// --notes, else the repo's 'git config air.notes'
+func mergeNotesEnabled(repoPath string) bool {
+	if integrateNotes {
+		return true
+	}
+	out, _ := exec.Command("git", "-C", repoPath, "config", "--type=bool", "--get", "air.notes").Output()
+	return strings.TrimSpace(string(out)) == "true"
+}
+
+// addMergeNote attaches the done payload at donePath to HEAD in dir
+func addMergeNote(dir, donePath string) error {
+	if out, err := exec.Command("git", "-C", dir, "notes", "--ref=air", "add", "-f", "-F", donePath, "HEAD").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to add note in %s: %s", dir, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// runRecordKind returns how 'air integrate --auto' marks a completed run in
+// repoPath: --record, else the repo's 'git config air.recordRuns', else ""
+// (not at all). "ref" writes refs/air/runs/<id>; "tag" the tag air/runs/<id>.
+func runRecordKind(repoPath string) string {
+	if integrateRecord != "" {
+		return integrateRecord
+	}
+	out, _ := exec.Command("git", "-C", repoPath, "config", "--get", "air.recordRuns").Output()
+	switch kind := strings.TrimSpace(string(out)); kind {
+	case "ref", "tag":
+		return kind
+	}
+	return ""
+}
+
+// currentRunID returns the ID of the run being integrated: its launch batch
+// from run.json, or now for runs that were never launched by air
+func currentRunID() string {
+	if record, _ := readRunRecord(); record != nil && record.ID != "" {
+		return record.ID
+	}
+	return newRunID(time.Now())
+}
+
+// markRunInGit marks the run in dir's repository with an annotated tag object
+// on HEAD listing the plans merged and the commit each branch was at,
+// referenced as kind says. It returns the ref written.
+func markRunInGit(dir, kind, runID string, plans []string) (string, error) {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "air run %s\n\nMerged plans:\n", runID)
+	for _, plan := range plans {
+		sha, _ := exec.Command("git", "-C", dir, "rev-parse", "--short", "air/"+plan).Output()
+		fmt.Fprintf(&msg, "- %s (air/%s at %s)\n", plan, plan, strings.TrimSpace(string(sha)))
+	}
+
+	name := "air/runs/" + runID
+	if kind == "tag" {
+		if out, err := exec.Command("git", "-C", dir, "tag", "-f", "-a", name, "-m", msg.String(), "HEAD").CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to tag run %s: %s", runID, strings.TrimSpace(string(out)))
+		}
+		return "refs/tags/" + name, nil
+	}
+
+	// A ref outside refs/tags keeps the marker out of 'git tag' and clones,
+	// so write the tag object by hand and point refs/air/runs/<id> at it
+	head, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD in %s: %w", dir, err)
+	}
+	ident, err := exec.Command("git", "-C", dir, "var", "GIT_COMMITTER_IDENT").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read committer identity in %s: %w", dir, err)
+	}
+	tag := fmt.Sprintf("object %s\ntype commit\ntag %s\ntagger %s\n\n%s",
+		strings.TrimSpace(string(head)), name, strings.TrimSpace(string(ident)), msg.String())
+	hashCmd := exec.Command("git", "-C", dir, "hash-object", "-t", "tag", "-w", "--stdin")
+	hashCmd.Stdin = strings.NewReader(tag)
+	obj, err := hashCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to write run record %s: %w", runID, err)
+	}
+	ref := "refs/" + name
+	if out, err := exec.Command("git", "-C", dir, "update-ref", ref, strings.TrimSpace(string(obj))).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %s", ref, strings.TrimSpace(string(out)))
+	}
+	return ref, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// failuresDirName holds an agent's failure snapshots inside its data
+// directory. 'air clean' leaves it in place so postmortems outlive the worktree.
+const failuresDirName = "failures"
+
+// getFailuresDir returns agents/<name>/failures/
+func getFailuresDir(agent string) string {
+	return filepath.Join(getAgentDir(agent), failuresDirName)
+}
+
+// snapshotFailure quarantines what a failed agent left behind in
+// agents/<name>/failures/<timestamp>/ (suffixed -2, -3... when it fails again
+// within the second): the failure payload, its plan, the commits and diff
+// since its base, uncommitted and untracked files, its Claude transcripts and
+// tmux pane, and the channels directory as it stood.
+// Best effort past creating the directory: a partial snapshot beats none.
+func snapshotFailure(agent, worktree string, payload *ChannelPayload) (string, error) {
+	dir, err := newSnapshotDir(getFailuresDir(agent), newRunID(payload.Timestamp))
+	if err != nil {
+		return "", fmt.Errorf("failed to create failure snapshot: %w", err)
+	}
+	write := func(name string, data []byte) {
+		if len(data) > 0 {
+			os.WriteFile(filepath.Join(dir, name), data, 0644)
+		}
+	}
+
+	if data, err := json.MarshalIndent(payload, "", "  "); err == nil {
+		write("failure.json", data)
+	}
+	if plan, err := os.ReadFile(planPath(agent)); err == nil {
+		write("plan.md", plan)
+	}
+
+	git := func(args ...string) []byte {
+		out, _ := exec.Command("git", append([]string{"-C", worktree}, args...)...).Output()
+		return out
+	}
+	if base := baseSHA(agent); base != "" {
+		write("commits.txt", git("log", "--oneline", base+"..HEAD"))
+		// Against the working tree, so uncommitted edits to tracked files are included
+		write("diff.patch", git("diff", base))
+	}
+	write("status.txt", git("status", "--porcelain"))
+
+	for _, path := range transcriptFiles(worktree) {
+		copyFile(path, filepath.Join(dir, "transcripts", filepath.Base(path)), 0644)
+	}
+
+	pane := os.Getenv("TMUX_PANE")
+	if pane == "" || os.Getenv("AIR_AGENT_ID") != agent {
+		pane = findAgentWindow(runSession(), agent)
+	}
+	if pane != "" {
+		if out, _ := exec.Command("tmux", "capture-pane", "-p", "-J", "-S", "-", "-t", pane).Output(); strings.TrimSpace(string(out)) != "" {
+			write("pane.log", []byte(strings.TrimRight(string(out), "\n")+"\n"))
+		}
+	}
+
+	copyPath(getChannelsDir(), filepath.Join(dir, "channels"))
+	return dir, nil
+}
+
+// newSnapshotDir creates parent/name, or parent/name-N for the first N free,
+// so a snapshot never lands in an earlier one
+func newSnapshotDir(parent, name string) (string, error) {
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(parent, name)
+	for n := 2; ; n++ {
+		err := os.Mkdir(dir, 0755)
+		if err == nil {
+			return dir, nil
+		}
+		if !os.IsExist(err) {
+			return "", err
+		}
+		dir = filepath.Join(parent, fmt.Sprintf("%s-%d", name, n))
+	}
+}
+
+// listFailureSnapshots returns an agent's failure snapshot directories, oldest first
+func listFailureSnapshots(agent string) []string {
+	entries, _ := os.ReadDir(getFailuresDir(agent))
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs = append(dirs, filepath.Join(getFailuresDir(agent), e.Name()))
+		}
+	}
+	return dirs
+}
+
+// removeAgentData deletes an agent's data directory except its failure
+// snapshots. Reports whether any snapshots were kept.
+func removeAgentData(agentDir string) (kept bool, err error) {
+	entries, err := os.ReadDir(agentDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, e := range entries {
+		if e.Name() == failuresDirName && e.IsDir() {
+			kept = true
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(agentDir, e.Name())); err != nil {
+			return kept, err
+		}
+	}
+	if !kept {
+		return false, os.Remove(agentDir)
+	}
+	return true, nil
+}
+
+// clearAgentsDir deletes all agent data except failure snapshots, returning
+// how many agents' snapshots were kept
+func clearAgentsDir(agentsDir string) (int, error) {
+	entries, err := os.ReadDir(agentsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	kept := 0
+	for _, e := range entries {
+		if !e.IsDir() {
+			os.Remove(filepath.Join(agentsDir, e.Name()))
+			continue
+		}
+		k, err := removeAgentData(filepath.Join(agentsDir, e.Name()))
+		if err != nil {
+			return kept, err
+		}
+		if k {
+			kept++
+		}
+	}
+	if kept == 0 {
+		return 0, os.Remove(agentsDir)
+	}
+	return kept, nil
+}
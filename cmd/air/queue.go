@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Queue channels live in channels/queues/<channel>/, one file per entry.
+// Entries are claimed by renaming them into .consumed/, which is atomic, so
+// concurrent consumers never pop the same entry.
+
+// getQueueDir returns the directory holding a queue channel's pending entries
+func getQueueDir(channel string) string {
+	return filepath.Join(getChannelsDir(), "queues", channel)
+}
+
+// enqueueChannel appends an entry to a queue channel
+func enqueueChannel(channel string, payload *ChannelPayload) error {
+	dir := getQueueDir(channel)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	// Zero-padded timestamp keeps entries in FIFO order when sorted by name.
+	// Write to a hidden temp file first so consumers never see a partial entry.
+	name := fmt.Sprintf("%020d-%s.json", payload.Timestamp.UnixNano(), payload.Agent)
+	tmp := filepath.Join(dir, "."+name+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write queue entry: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write queue entry: %w", err)
+	}
+	return nil
+}
+
+// pendingQueueEntries returns the unconsumed entry filenames, oldest first
+func pendingQueueEntries(channel string) ([]string, error) {
+	entries, err := os.ReadDir(getQueueDir(channel))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// dequeueChannel pops the oldest pending entry for consumer.
+// Returns nil if the queue is empty.
+func dequeueChannel(channel, consumer string) (*ChannelPayload, error) {
+	names, err := pendingQueueEntries(channel)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	dir := getQueueDir(channel)
+	consumedDir := filepath.Join(dir, ".consumed")
+	if err := os.MkdirAll(consumedDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	for _, name := range names {
+		claimed := filepath.Join(consumedDir, name)
+		if err := os.Rename(filepath.Join(dir, name), claimed); err != nil {
+			// Another consumer got it first
+			continue
+		}
+
+		data, err := os.ReadFile(claimed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read queue entry: %w", err)
+		}
+		var payload ChannelPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, fmt.Errorf("failed to parse queue entry %s: %w", name, err)
+		}
+
+		// Record who consumed it (best effort)
+		payload.ConsumedBy = consumer
+		if data, err := json.MarshalIndent(&payload, "", "  "); err == nil {
+			os.WriteFile(claimed, data, 0644)
+		}
+		return &payload, nil
+	}
+	return nil, nil
+}
+
+// runAgentConsume blocks until an entry is available on a queue channel, then pops it
+func runAgentConsume(channel string, progress io.Writer) error {
+	agentID := os.Getenv("AIR_AGENT_ID")
+	if agentID == "" {
+		return fmt.Errorf("AIR_AGENT_ID environment variable is required")
+	}
+
+	fmt.Fprintf(progress, "Waiting for an entry on queue '%s'...\n", channel)
+
+	interval := pollInterval()
+	start := time.Now()
+	for {
+		payload, err := dequeueChannel(channel, agentID)
+		if err != nil {
+			return err
+		}
+		if payload != nil {
+			return writeWaitResult(fmt.Sprintf("Consumed entry from queue '%s' (queued by agent '%s')", channel, payload.Agent), channel, payload)
+		}
+		if waitTimeout > 0 && time.Since(start) > waitTimeout {
+			return fmt.Errorf("timed out after %s waiting for an entry on queue '%s'", waitTimeout, channel)
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Launches pause for rateLimitBackoffBase after an agent hits Claude's rate
+// limits, doubling with each further hit up to rateLimitBackoffMax
+const (
+	rateLimitBackoffBase = time.Minute
+	rateLimitBackoffMax  = 30 * time.Minute
+)
+
+// rateLimitRegex matches the API errors Claude records when the account is
+// rate limited, overloaded, or out of usage
+var rateLimitRegex = regexp.MustCompile(`(?i)rate_limit_error|overloaded_error|API Error: (429|529)\b|usage limit reached|limit reached.*resets`)
+
+// usageResetRegex captures the reset time Claude appends to usage limit
+// errors, as seconds since the epoch
+var usageResetRegex = regexp.MustCompile(`(?i)usage limit reached\|(\d+)`)
+
+// slotBackoff pauses the launch of new agents machine-wide after one hits
+// Claude's rate limits, which are per account rather than per project
+type slotBackoff struct {
+	Until   time.Time `json:"until"`
+	Agent   string    `json:"agent"`  // <project>/<agent> that hit the limit
+	Reason  string    `json:"reason"` // the API error, shortened
+	Seen    time.Time `json:"seen"`   // when the newest error backed off for was recorded
+	Strikes int       `json:"strikes"`
+}
+
+// active reports whether b still holds launches back
+func (b *slotBackoff) active(now time.Time) bool {
+	return b != nil && now.Before(b.Until)
+}
+
+// rateLimitBackoff returns how long to pause launches after the strikes-th
+// rate limit hit in a row
+func rateLimitBackoff(strikes int) time.Duration {
+	d := rateLimitBackoffBase
+	for i := 1; i < strikes && d < rateLimitBackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, rateLimitBackoffMax)
+}
+
+// transcriptRateLimit returns the rate limit error that ended the most recent
+// session in dir, when it was recorded, and when Claude said the limit resets
+// (zero if it didn't). reason is "" if the session's last reply wasn't one.
+func transcriptRateLimit(dir string) (reason string, at, reset time.Time) {
+	files := transcriptFiles(dir)
+	if len(files) == 0 {
+		return "", time.Time{}, time.Time{}
+	}
+	sort.Slice(files, func(i, j int) bool { return modTime(files[i]).After(modTime(files[j])) })
+
+	f, err := os.Open(files[0])
+	if err != nil {
+		return "", time.Time{}, time.Time{}
+	}
+	defer f.Close()
+	const tail = 512 * 1024
+	if fi, err := f.Stat(); err == nil && fi.Size() > tail {
+		f.Seek(fi.Size()-tail, io.SeekStart)
+	}
+
+	var last *transcriptEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var entry transcriptEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.IsSidechain {
+			continue
+		}
+		if entry.Type == "user" || entry.Type == "assistant" {
+			last = &entry
+		}
+	}
+	if last == nil || last.Type != "assistant" {
+		return "", time.Time{}, time.Time{}
+	}
+	for _, b := range last.blocks() {
+		text := strings.TrimSpace(b.Text)
+		// Only errors from the API, not Claude talking about rate limits
+		if b.Type != "text" || !(last.IsAPIError || strings.HasPrefix(text, "API Error") || strings.Contains(text, "usage limit reached")) {
+			continue
+		}
+		if !rateLimitRegex.MatchString(text) {
+			continue
+		}
+		if m := usageResetRegex.FindStringSubmatch(text); m != nil {
+			if secs, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+				reset = time.Unix(secs, 0).UTC()
+			}
+		}
+		reason = strings.Join(strings.Fields(text), " ")
+		if len(reason) > 120 {
+			reason = reason[:120] + "..."
+		}
+		return reason, last.Timestamp, reset
+	}
+	return "", time.Time{}, time.Time{}
+}
+
+// noteRateLimit pauses launches machine-wide if the agent's transcript in
+// wtPath ends in a rate limit error newer than any already backed off for.
+// Heartbeats call it, so a hit is noticed within a heartbeat interval.
+func noteRateLimit(project, agent, wtPath string) error {
+	reason, at, reset := transcriptRateLimit(wtPath)
+	if reason == "" {
+		return nil
+	}
+	now := time.Now()
+	return updateSlots(func(t *slotTable) error {
+		prev := t.Backoff
+		if prev != nil && !at.After(prev.Seen) {
+			return nil
+		}
+		// Hits soon after the last pause ended mean it wasn't long enough
+		strikes := 1
+		if prev != nil && at.Before(prev.Until.Add(rateLimitBackoffMax)) {
+			strikes = prev.Strikes + 1
+		}
+		until := at.Add(rateLimitBackoff(strikes))
+		if reset.After(until) {
+			until = reset
+		}
+		if !until.After(now) {
+			return nil
+		}
+		t.Backoff = &slotBackoff{Until: until.UTC(), Agent: project + "/" + agent, Reason: reason, Seen: at, Strikes: strikes}
+		return nil
+	})
+}
+
+// launchBackoff returns the rate limit pause holding back launches, or nil
+func launchBackoff() *slotBackoff {
+	path, err := getSlotsPath()
+	if err != nil {
+		return nil
+	}
+	if b := readSlots(path).Backoff; b.active(time.Now()) {
+		return b
+	}
+	return nil
+}
+
+// formatBackoff describes a rate limit pause for humans
+func formatBackoff(b *slotBackoff) string {
+	return fmt.Sprintf("%s hit Claude's rate limit (%s); new agents wait until %s", b.Agent, b.Reason, b.Until.Local().Format(time.Kitchen))
+}
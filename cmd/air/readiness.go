@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Verification records a passing run of the repo's verify command on an
+// agent's branch, made by 'air agent done'
+type Verification struct {
+	Command string    `json:"command"`
+	SHA     string    `json:"sha"`
+	Time    time.Time `json:"time"`
+}
+
+// readiness says whether an agent's branch is ready for 'air integrate', and
+// why not
+type readiness struct {
+	Ready  bool             `json:"ready"`
+	Checks []readinessCheck `json:"checks"`
+}
+
+// readinessCheck is one condition for merging: done, verified, conflicts, or
+// boundaries
+type readinessCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// verifyCommand returns the repo's 'git config air.verify' command, run in
+// the agent's worktree by 'air agent done', or "" if it has none
+func verifyCommand(dir string) string {
+	out, _ := exec.Command("git", "-C", dir, "config", "--get", "air.verify").Output()
+	return strings.TrimSpace(string(out))
+}
+
+// runVerification runs the verify command of the repo in dir on HEAD, showing
+// its output. It returns nil if the repo has no verify command.
+func runVerification(dir string) (*Verification, error) {
+	command := verifyCommand(dir)
+	if command == "" {
+		return nil, nil
+	}
+	sha, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	fmt.Printf("Verifying: %s\n", command)
+	verify := exec.Command("sh", "-c", command)
+	verify.Dir = dir
+	verify.Stdout = os.Stdout
+	verify.Stderr = os.Stderr
+	if err := verify.Run(); err != nil {
+		return nil, fmt.Errorf("verification failed (%s: %v); fix it and commit, then run 'air agent done' again", command, err)
+	}
+	return &Verification{Command: command, SHA: strings.TrimSpace(string(sha)), Time: time.Now().UTC()}, nil
+}
+
+// assessReadiness checks whether agent's branch can be merged: it signaled
+// done, the branch is what passed verification (if the repo has a verify
+// command), it merges cleanly into its base branch, and it changed only files
+// in its plan's scope. pd is nil if the agent's plan is gone.
+func assessReadiness(agent worktreeInfo, pd *PlanDependencies, done *ChannelPayload) *readiness {
+	r := &readiness{}
+	check := func(name string, ok bool, detail string) {
+		r.Checks = append(r.Checks, readinessCheck{Name: name, OK: ok, Detail: detail})
+	}
+	if done == nil {
+		check("done", false, "not done")
+		return r
+	}
+	check("done", true, "")
+
+	branch := "air/" + agent.name
+	tip, _ := exec.Command("git", "-C", agent.repoPath, "rev-parse", branch).Output()
+	switch command := verifyCommand(agent.repoPath); {
+	case command == "":
+		check("verified", true, "no verify command")
+	case done.Verified == nil:
+		check("verified", false, "not verified")
+	case done.Verified.SHA != strings.TrimSpace(string(tip)):
+		check("verified", false, "commits since verification")
+	default:
+		check("verified", true, "")
+	}
+
+	target := "HEAD"
+	if base := readAgentBase(agent.name); base != nil && base.Branch != "" {
+		target = base.Branch
+	}
+	files, conflicts, err := mergeTreeConflicts(agent.repoPath, target, branch)
+	switch {
+	case err != nil:
+		check("conflicts", false, err.Error())
+	case conflicts:
+		check("conflicts", false, fmt.Sprintf("conflicts with %s in %s", target, formatFileList(files)))
+	default:
+		check("conflicts", true, "")
+	}
+
+	if scope := planScope(pd); len(scope) > 0 {
+		out, _ := exec.Command("git", "-C", agent.repoPath, "diff", "--name-only", target+"..."+branch).Output()
+		var outside []string
+		for _, file := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if file != "" && !inScope(file, scope) {
+				outside = append(outside, file)
+			}
+		}
+		if len(outside) > 0 {
+			check("boundaries", false, "changed outside its scope: "+formatFileList(outside))
+		} else {
+			check("boundaries", true, "")
+		}
+	}
+
+	r.Ready = true
+	for _, c := range r.Checks {
+		r.Ready = r.Ready && c.OK
+	}
+	return r
+}
+
+// buildReadinessContext gives the integration session the go/no-go list of
+// done agents, as 'air status' shows it
+func buildReadinessContext(info *WorkspaceInfo) string {
+	report, err := collectStatus(info)
+	if err != nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, a := range report.Agents {
+		if a.State == "done" && a.Readiness != nil {
+			fmt.Fprintf(&sb, "- %s: %s\n", a.Name, strings.TrimLeft(formatReadiness(a.Readiness), "✓✗ "))
+		}
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return "\n\n## Readiness\n\nWhether each done agent's branch is ready to merge (done, verified, merges cleanly into its base, and stayed in scope). Start with the ready ones, and raise the others with the user before merging them.\n\n" + sb.String()
+}
+
+// planScope returns the paths pd's agent may change: its **In scope:** paths,
+// else its component
+func planScope(pd *PlanDependencies) []string {
+	switch {
+	case pd == nil:
+		return nil
+	case len(pd.InScope) > 0:
+		return pd.InScope
+	case pd.Component != "":
+		return []string{pd.Component}
+	}
+	return nil
+}
+
+// inScope reports whether the repo-relative file is one of scope's paths,
+// inside one of its directories, or matches one of its globs
+func inScope(file string, scope []string) bool {
+	for _, s := range scope {
+		s = strings.TrimSuffix(filepath.ToSlash(filepath.Clean(s)), "/")
+		if s == "." || file == s || strings.HasPrefix(file, s+"/") {
+			return true
+		}
+		if ok, _ := filepath.Match(s, file); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// formatFileList joins files, eliding all but the first five
+func formatFileList(files []string) string {
+	if len(files) > 5 {
+		return fmt.Sprintf("%s (+%d more)", strings.Join(files[:5], ", "), len(files)-5)
+	}
+	return strings.Join(files, ", ")
+}
+
+// formatReadiness is the status line of a done agent's readiness
+func formatReadiness(r *readiness) string {
+	if r.Ready {
+		return "✓ ready to merge"
+	}
+	var reasons []string
+	for _, c := range r.Checks {
+		if !c.OK {
+			reasons = append(reasons, c.Detail)
+		}
+	}
+	return "✗ not ready to merge: " + strings.Join(reasons, "; ")
+}
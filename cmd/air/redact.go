@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// defaultSecretPatterns match credentials that shouldn't be copied into agent
+// files, which are stored world-readable under agents/. Repos add their own
+// with 'git config --add air.secretPattern <regexp>'.
+var defaultSecretPatterns = []string{
+	`AKIA[0-9A-Z]{16}`,             // AWS access key ID
+	`gh[pousr]_[A-Za-z0-9]{36,}`,   // GitHub token
+	`github_pat_[A-Za-z0-9_]{22,}`, // GitHub fine-grained token
+	`sk-ant-[A-Za-z0-9_-]{20,}`,    // Anthropic API key
+	`sk-[A-Za-z0-9]{32,}`,          // OpenAI-style API key
+	`xox[abprs]-[A-Za-z0-9-]{10,}`, // Slack token
+	`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`,
+	`(?i)\b(password|passwd|secret|api[_-]?key|access[_-]?token)\b\s*[:=]\s*["']?[^\s"']{8,}`,
+}
+
+// secretMask replaces each secret found
+const secretMask = "[REDACTED]"
+
+// loadSecretPatterns compiles the default secret patterns and any configured
+// for the repo at root. Invalid configured patterns are skipped with a warning.
+func loadSecretPatterns(root string) []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, p := range defaultSecretPatterns {
+		patterns = append(patterns, regexp.MustCompile(p))
+	}
+	out, _ := exec.Command("git", "-C", root, "config", "--get-all", "air.secretPattern").Output()
+	for _, p := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		re, err := regexp.Compile(p)
+		if err != nil {
+			fmt.Printf("Warning: ignoring invalid air.secretPattern %q: %v\n", p, err)
+			continue
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns
+}
+
+// redactSecrets masks every match of patterns in text, returning the result
+// and how many secrets it masked
+func redactSecrets(text string, patterns []*regexp.Regexp) (string, int) {
+	count := 0
+	for _, re := range patterns {
+		text = re.ReplaceAllStringFunc(text, func(string) string {
+			count++
+			return secretMask
+		})
+	}
+	return text, count
+}
+
+// warnRedacted tells the user secrets were kept out of an agent's file
+func warnRedacted(agent, what string, count int) {
+	if count > 0 {
+		fmt.Printf("Warning: masked %d secret(s) in %s's %s; pass secrets through the environment, not plans or context\n", count, agent, what)
+	}
+}
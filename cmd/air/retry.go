@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Attempt is one launch of an agent. Every launch after the first is a retry,
+// and records why the previous attempt didn't stick.
+type Attempt struct {
+	Number  int       `json:"number"`
+	Started time.Time `json:"started"`
+	Reason  string    `json:"reason,omitempty"` // why it was retried (retries only)
+}
+
+// lastAttempt returns the agent's latest attempt, or nil if it never launched
+func (a *RunAgent) lastAttempt() *Attempt {
+	if len(a.Attempts) == 0 {
+		return nil
+	}
+	return &a.Attempts[len(a.Attempts)-1]
+}
+
+// retryReason explains why an agent that already had an attempt is being
+// launched again, from what its last attempt left behind
+func retryReason(name string, now time.Time) string {
+	if f := readFailure(name); f != nil {
+		return "failed: " + f.Message
+	}
+	if _, err := os.Stat(filepath.Join(getChannelsDir(), "done", name+".json")); err == nil {
+		return "rerun after done"
+	}
+	if record, _ := readRunRecord(); record != nil {
+		if a := record.agent(name); a != nil && a.Stopped != nil {
+			return "stopped with 'air stop'"
+		}
+	}
+	if alive, known := agentAlive(name, now); known && !alive {
+		return "stopped (no heartbeat)"
+	}
+	return "relaunched"
+}
+
+// nextAttempts numbers the coming launch of each agent, refusing if any has
+// used up the run's --max-attempts
+func nextAttempts(agents []worktreeInfo, now time.Time) (map[string]Attempt, error) {
+	record, _ := readRunRecord()
+	attempts := make(map[string]Attempt)
+	var exhausted []string
+	for _, agent := range agents {
+		next := Attempt{Number: 1, Started: now.UTC()}
+		if record != nil {
+			if a := record.agent(agent.name); a != nil && len(a.Attempts) > 0 {
+				next.Number = len(a.Attempts) + 1
+				next.Reason = retryReason(agent.name, now)
+				if max := record.Flags.MaxAttempts; max > 0 && next.Number > max {
+					exhausted = append(exhausted, fmt.Sprintf("%s (%d/%d; last attempt %s)", agent.name, len(a.Attempts), max, next.Reason))
+				}
+			}
+		}
+		attempts[agent.name] = next
+	}
+	if len(exhausted) > 0 {
+		return nil, fmt.Errorf("out of attempts: %s; raise --max-attempts, or 'air clean <plan>' to start over", strings.Join(exhausted, ", "))
+	}
+	return attempts, nil
+}
+
+// recordAttempts appends each agent's new attempt to run.json
+func recordAttempts(attempts map[string]Attempt) error {
+	return updateRunRecord(func(r *RunRecord) *RunRecord {
+		if r == nil {
+			return nil
+		}
+		for name, attempt := range attempts {
+			if a := r.agent(name); a != nil {
+				a.Attempts = append(a.Attempts, attempt)
+			}
+		}
+		return r
+	})
+}
+
+// formatAttempt describes an agent's attempt for status, e.g. "attempt 2/3".
+// Returns "" for a first attempt, which isn't worth mentioning.
+func formatAttempt(number, max int) string {
+	if number < 2 {
+		return ""
+	}
+	if max > 0 {
+		return fmt.Sprintf("attempt %d/%d", number, max)
+	}
+	return fmt.Sprintf("attempt %d", number)
+}
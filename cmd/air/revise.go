@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/scotro/air/cmd/air/prompts"
+	"github.com/spf13/cobra"
+)
+
+var planReviseCmd = &cobra.Command{
+	Use:   "revise <plan>",
+	Short: "Re-plan an agent's unfinished or failed work",
+	Long: `Starts an orchestration session scoped to one plan, preloaded with what its
+agent left behind: the commits and diff on air/<plan>, its completion summary,
+why it was blocked (escalations, timed-out waits, merge conflicts), and any
+review notes you pass. Claude proposes an updated plan, or follow-up plans for
+the remaining work, and writes them with 'air plan create'.
+
+Use --print to see the briefing without starting Claude.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPlanRevise,
+}
+
+var reviseNotes string
+var reviseNotesFile string
+var revisePrint bool
+
+// reviseDiffLimit caps how much of the branch diff goes into the briefing
+const reviseDiffLimit = 30000
+
+func init() {
+	planCmd.AddCommand(planReviseCmd)
+	planReviseCmd.Flags().StringVar(&reviseNotes, "notes", "", "Review notes on what went wrong or what to change")
+	planReviseCmd.Flags().StringVar(&reviseNotesFile, "notes-file", "", "Read review notes from a file")
+	planReviseCmd.Flags().BoolVar(&revisePrint, "print", false, "Print the revision briefing instead of starting Claude")
+}
+
+func runPlanRevise(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	content, err := os.ReadFile(planPath(name))
+	if err != nil {
+		return errPlanNotFound(name)
+	}
+
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+
+	notes := reviseNotes
+	if reviseNotesFile != "" {
+		if notes != "" {
+			return withCode(codeUsage, fmt.Errorf("use either --notes or --notes-file"))
+		}
+		data, err := os.ReadFile(reviseNotesFile)
+		if err != nil {
+			return fmt.Errorf("failed to read notes: %w", err)
+		}
+		notes = string(data)
+	}
+
+	pd := parsePlanDependencies(name, string(content))
+	if plans, err := loadAllPlanDependencies(); err == nil {
+		for _, p := range plans {
+			if p.Name == name {
+				pd = p
+			}
+		}
+	}
+	briefing := buildRevisionContext(info, pd, string(content), strings.TrimSpace(notes))
+	if revisePrint {
+		fmt.Print(briefing)
+		return nil
+	}
+
+	context, err := os.ReadFile(getContextPath())
+	if err != nil {
+		return fmt.Errorf("failed to read context: %w", err)
+	}
+	orchestration := prompts.Orchestration
+	if info.Mode == ModeWorkspace {
+		orchestration = buildWorkspaceRepoContext(info) + "\n\n" + prompts.OrchestrationWorkspace
+	} else if info.Mode == ModeMonorepo {
+		orchestration += "\n\n" + buildMonorepoComponentContext(info)
+	}
+	systemPrompt := string(context) + "\n\n" + orchestration + "\n\n" + briefing
+
+	initialPrompt := fmt.Sprintf("Revise plan '%s'. Summarize what its agent got done and what went wrong, then propose an updated plan or follow-up plans for the remaining work. Ask before writing anything.", name)
+	claudeCmd := exec.Command("claude",
+		"--allowedTools", "Bash(air plan:*)",
+		"--append-system-prompt", systemPrompt,
+		initialPrompt)
+	claudeCmd.Stdin = os.Stdin
+	claudeCmd.Stdout = os.Stdout
+	claudeCmd.Stderr = os.Stderr
+	return claudeCmd.Run()
+}
+
+// buildRevisionContext gathers what a plan's agent left behind, for re-planning
+func buildRevisionContext(info *WorkspaceInfo, pd PlanDependencies, planContent, notes string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Revising Plan: %s\n\n", pd.Name)
+	sb.WriteString("You are revising one plan whose agent didn't finish cleanly, not planning from scratch. ")
+	create := "air plan create --name " + pd.Name
+	if pd.Group != "" {
+		create += " --group " + pd.Group
+	}
+	sb.WriteString("Keep what was done and plan only the remaining work: either rewrite this plan with `" + create + " --force ...` ")
+	sb.WriteString("(rerunning it while its worktree exists continues on the same branch), or write follow-up plans for what's left. ")
+	sb.WriteString("Don't change other plans unless they depend on something that moved. Run `air plan validate` when done.\n\n")
+
+	sb.WriteString("### Current Plan\n\n```markdown\n")
+	sb.WriteString(strings.TrimSpace(planContent))
+	sb.WriteString("\n```\n\n")
+
+	if notes != "" {
+		sb.WriteString("### Review Notes\n\n")
+		sb.WriteString(notes)
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("### What Happened\n\n")
+	for _, h := range planHistory(pd.Name) {
+		fmt.Fprintf(&sb, "- %s\n", h)
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(buildBranchContext(info, pd))
+	return sb.String()
+}
+
+// planHistory lists what happened to a plan's agent: escalations, timed-out
+// waits, merge conflicts, failure, and whether it finished
+func planHistory(name string) []string {
+	var happened []string
+	if e := readEscalation(name); e != nil {
+		happened = append(happened, fmt.Sprintf("Escalated: blocked since %s: %s", e.Since.Format("2006-01-02 15:04"), e.Reason))
+	}
+	for _, t := range listWaitTimeouts() {
+		if t.Agent == name {
+			happened = append(happened, fmt.Sprintf("Wait on `%s` timed out after %s (fallback: %s)", t.Channel, t.Timeout, t.Fallback))
+		}
+	}
+	events, _ := readEvents()
+	for _, e := range events {
+		if e.Kind == eventFailed && e.Agent == name {
+			happened = append(happened, fmt.Sprintf("Gave up with 'air agent fail' at %s: %s", e.Time.Local().Format("2006-01-02 15:04"), e.Reason))
+		}
+		if e.Kind == eventConflict && (e.Agent == name || contains(e.With, name)) {
+			line := fmt.Sprintf("Merge conflict integrating %s", e.Agent)
+			if len(e.With) > 0 {
+				line += " with " + strings.Join(e.With, ", ")
+			}
+			if len(e.Files) > 0 {
+				line += " in " + strings.Join(e.Files, ", ")
+			}
+			happened = append(happened, line)
+		}
+	}
+	if record, _ := readRunRecord(); record != nil {
+		if a := record.agent(name); a != nil {
+			for _, attempt := range a.Attempts {
+				if attempt.Number > 1 {
+					happened = append(happened, fmt.Sprintf("Retried as attempt %d: %s", attempt.Number, attempt.Reason))
+				}
+			}
+		}
+	}
+	if snapshots := listFailureSnapshots(name); len(snapshots) > 0 {
+		happened = append(happened, fmt.Sprintf("Failure snapshots (diff, transcript, channel state): %s", strings.Join(snapshots, ", ")))
+	}
+	if summary, ok := readDoneSummaries()[name]; ok {
+		happened = append(happened, "Marked done with summary: "+summary)
+	} else {
+		happened = append(happened, "Never ran 'air agent done'")
+	}
+	return happened
+}
+
+// buildBranchContext describes the commits and diff on a plan's branch
+func buildBranchContext(info *WorkspaceInfo, pd PlanDependencies) string {
+	repoName, repoPath, _ := agentPaths(info, pd)
+	branch := "air/" + pd.Name
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### Work on %s\n\n", branch)
+	if exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", branch).Run() != nil {
+		sb.WriteString("The branch doesn't exist (never created, or already cleaned up).\n")
+		return sb.String()
+	}
+
+	base := ""
+	if recorded := readAgentBase(pd.Name); recorded != nil {
+		base = recorded.SHA
+	}
+	if base == "" {
+		out, _ := exec.Command("git", "-C", repoPath, "merge-base", branch, resolveAgentBase(repoPath, info.baseBranch(repoName)).SHA).Output()
+		base = strings.TrimSpace(string(out))
+	}
+	if base == "" {
+		sb.WriteString("Couldn't determine where the branch started.\n")
+		return sb.String()
+	}
+
+	log, _ := exec.Command("git", "-C", repoPath, "log", "--oneline", base+".."+branch).Output()
+	if strings.TrimSpace(string(log)) == "" {
+		sb.WriteString("No commits on the branch.\n")
+		return sb.String()
+	}
+	sb.WriteString("Commits:\n```\n")
+	sb.Write(log)
+	sb.WriteString("```\n\n")
+
+	stat, _ := exec.Command("git", "-C", repoPath, "diff", "--stat", base, branch).Output()
+	sb.WriteString("Changed files:\n```\n")
+	sb.Write(stat)
+	sb.WriteString("```\n\n")
+
+	diff, _ := exec.Command("git", "-C", repoPath, "diff", base, branch).Output()
+	text := string(diff)
+	truncated := len(text) > reviseDiffLimit
+	if truncated {
+		text = text[:reviseDiffLimit]
+	}
+	sb.WriteString("Diff:\n```diff\n")
+	sb.WriteString(text)
+	if truncated {
+		fmt.Fprintf(&sb, "\n... (truncated; see `git diff %s %s` in %s)", shortRef(base), branch, repoPath)
+	}
+	sb.WriteString("\n```\n")
+	return sb.String()
+}
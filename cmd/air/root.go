@@ -22,8 +22,15 @@ var rootCmd = &cobra.Command{
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("air v%s (commit: %s, built: %s)\n", version, commit, date)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info := struct {
+			Version string `json:"version"`
+			Commit  string `json:"commit"`
+			Date    string `json:"date"`
+		}{version, commit, date}
+		return render(info, func() {
+			fmt.Printf("air v%s (commit: %s, built: %s)\n", version, commit, date)
+		})
 	},
 }
 
@@ -38,14 +45,40 @@ func init() {
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(planCmd)
 	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(prepareCmd)
+	rootCmd.AddCommand(launchCmd)
+	rootCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(enqueueCmd)
+	rootCmd.AddCommand(adoptCmd)
+	rootCmd.AddCommand(explainCmd)
+	rootCmd.AddCommand(exportBriefCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(topCmd)
+	rootCmd.AddCommand(signalCmd)
 	rootCmd.AddCommand(integrateCmd)
+	rootCmd.AddCommand(changelogCmd)
+	rootCmd.AddCommand(conflictsCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(cleanCmd)
 
 	// Utility commands
+	rootCmd.AddCommand(workspaceCmd)
+	rootCmd.AddCommand(contextCmd)
 	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(duCmd)
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(usageCmd)
+	rootCmd.AddCommand(slotsCmd)
+	rootCmd.AddCommand(postmortemCmd)
 	rootCmd.AddCommand(versionCmd)
 
 	// Agent commands (used during execution, not by users)
 	rootCmd.AddCommand(agentCmd)
+
+	supportsOutput(versionCmd)
 }
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -15,23 +21,94 @@ var runCmd = &cobra.Command{
 	Short: "Create worktrees and launch agents",
 	Long: `Creates git worktrees for each plan and launches Claude agents in a tmux session.
 
-Use 'air run all' to run all plans, or specify plan names.
-With no arguments, shows available plans.`,
+Use 'air run all' to run all plans, or specify plan names. 'air run' is
+'air prepare' followed by 'air launch'.
+With no arguments on a terminal, picks plans from an interactive list;
+otherwise shows available plans.
+
+Before creating worktrees, prints a summary (plan, target, branch, base,
+dependencies, model) and asks for confirmation on a terminal (skip with --yes).
+
+With --pipeline, runs the stages in air.pipeline.yaml at the project root in
+order. Each stage launches its plans (a plan group or a list), waits for every
+agent to finish, integrates them with 'air integrate --auto', then passes its
+gate (a verify command, human approval) before the next stage starts:
+
+  stages:
+    - name: auth
+      group: m1-auth
+      gate:
+        verify: go test ./...
+        approve: true
+    - name: billing
+      plans: [billing-core, billing-api]
+
+Pipeline progress is saved in ~/.air/<project>/pipeline.json. After a failure
+or reboot, 'air run --pipeline --resume' continues from the last incomplete
+stage: completed stages are skipped, and agents that aren't done are relaunched
+in their existing worktrees if they lost their tmux window.`,
 	RunE: runRun,
 }
 
 var noAutoAccept bool
 var dryRun bool
+var runChannelTTL time.Duration
+var runYes bool
+var runForce bool
+var runModel string
+var runEscalateAfter time.Duration
+var runMaxAttempts int
+var runBaseUpdates string
+var runEscalateCmd string
+var runPauseDownstream bool
+var runGroup string
+var runPhase int
+var runNextPhase bool
+var runPipelineFlag bool
+var runResume bool
+
+var noAttach bool
+var tmuxStatusBar bool
+
+// prepareOnly stops 'air run' after worktrees and launch scripts exist (air prepare)
+var prepareOnly bool
 
 func init() {
-	runCmd.Flags().BoolVar(&noAutoAccept, "no-auto-accept", false, "Disable auto-accept mode (require permission for edits)")
-	runCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate plans and show what would run, without launching")
+	addRunFlags(runCmd)
+	addAttachFlags(runCmd)
+	runCmd.Flags().BoolVar(&runPipelineFlag, "pipeline", false, "Run the stages in "+pipelineFile+" end to end")
+	runCmd.Flags().BoolVar(&runResume, "resume", false, "With --pipeline, continue an unfinished pipeline from its last incomplete stage")
+}
+
+// addRunFlags registers the flags shared by 'air run' and 'air prepare'
+func addRunFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&noAutoAccept, "no-auto-accept", false, "Disable auto-accept mode (require permission for edits)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate plans and show what would run, without launching")
+	cmd.Flags().DurationVar(&runChannelTTL, "channel-ttl", 0, "Expire channel signals after this duration (sets AIR_CHANNEL_TTL for agents)")
+	cmd.Flags().BoolVarP(&runYes, "yes", "y", false, "Proceed without confirming the summary")
+	cmd.Flags().BoolVar(&runForce, "force", false, "Replace a run that is still active in this project")
+	cmd.Flags().StringVar(&runModel, "model", "", "Claude model for the agents (default: claude's default)")
+	cmd.Flags().DurationVar(&runEscalateAfter, "escalate-after", defaultEscalateAfter, "Escalate agents blocked or stalled this long (0 disables)")
+	cmd.Flags().StringVar(&runEscalateCmd, "escalate-cmd", "", "Shell command run on escalation (gets AIR_ESCALATION_AGENT, _REASON, _SINCE)")
+	cmd.Flags().BoolVar(&runPauseDownstream, "pause-downstream", false, "Don't launch plans that wait on an escalated agent")
+	cmd.Flags().IntVar(&runMaxAttempts, "max-attempts", 0, "Refuse to launch an agent more than this many times (0: no limit)")
+	cmd.Flags().StringVar(&runBaseUpdates, "base-updates", baseUpdatesWarn, "When base branches gain commits: off, warn in status, or auto (sync idle agents)")
+	cmd.Flags().StringVar(&runGroup, "group", "", "Run all plans in this plan group (plans/<group>/)")
+	cmd.Flags().IntVar(&runPhase, "phase", 0, "Run the plans in this **Phase:** (earlier phases must be done and integrated)")
+	cmd.Flags().BoolVar(&runNextPhase, "next-phase", false, "Run the first phase that isn't done and integrated yet")
+}
+
+// addAttachFlags registers the flags for commands that start the tmux session
+func addAttachFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&noAttach, "no-attach", false, "Start the tmux session and return without attaching")
+	cmd.Flags().BoolVar(&noAttach, "detach", false, "Alias for --no-attach")
+	cmd.Flags().BoolVar(&tmuxStatusBar, "status-bar", false, "Show each agent's state (running/waiting/done/failed/blocked) in the tmux status bar")
 }
 
 func runRun(cmd *cobra.Command, args []string) error {
 	// Check initialization
 	if !isInitialized() {
-		return fmt.Errorf("not initialized (run 'air init' first)")
+		return errNotInitialized()
 	}
 
 	// Detect mode
@@ -40,6 +117,19 @@ func runRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to detect mode: %w", err)
 	}
 
+	if !validBaseUpdatePolicy(runBaseUpdates) {
+		return withCode(codeUsage, fmt.Errorf("--base-updates must be off, warn, or auto, got %q", runBaseUpdates))
+	}
+	if runResume && !runPipelineFlag {
+		return withCode(codeUsage, fmt.Errorf("--resume continues a pipeline; use it with --pipeline"))
+	}
+	if runPipelineFlag {
+		if len(args) > 0 {
+			return withCode(codeUsage, fmt.Errorf("--pipeline runs the stages in %s; don't also name plans", pipelineFile))
+		}
+		return runPipeline(cmd, info)
+	}
+
 	plansDir := getPlansDir()
 
 	// Get available plans
@@ -53,6 +143,53 @@ func runRun(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// --group selects every plan in the group
+	if runGroup != "" {
+		if len(args) > 0 && !(len(args) == 1 && args[0] == "all") {
+			return withCode(codeUsage, fmt.Errorf("--group runs every plan in the group; don't also name plans"))
+		}
+		files, err := listPlanFiles(plansDir)
+		if err != nil {
+			return err
+		}
+		args = nil
+		for _, f := range files {
+			if f.group == runGroup {
+				args = append(args, f.name)
+			}
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("no plans in group '%s'", runGroup)
+		}
+	}
+
+	// --phase and --next-phase pick from every plan (in the group, with --group)
+	phased := runPhase > 0 || runNextPhase
+	if phased {
+		if runPhase > 0 && runNextPhase {
+			return withCode(codeUsage, fmt.Errorf("use either --phase or --next-phase"))
+		}
+		if runGroup == "" {
+			if len(args) > 0 && !(len(args) == 1 && args[0] == "all") {
+				return withCode(codeUsage, fmt.Errorf("--phase and --next-phase select plans by phase; don't also name plans"))
+			}
+			args = []string{"all"}
+		}
+	}
+
+	// No args on a terminal: pick plans interactively
+	if len(args) == 0 && isTerminal(os.Stdin) && isTerminal(os.Stdout) {
+		selected, err := pickPlans(planPickerItems(info, available))
+		if err != nil {
+			return err
+		}
+		if len(selected) == 0 {
+			fmt.Println("No plans selected.")
+			return nil
+		}
+		args = selected
+	}
+
 	// No args: show available plans
 	if len(args) == 0 {
 		fmt.Println("Available plans:")
@@ -72,21 +209,33 @@ func runRun(cmd *cobra.Command, args []string) error {
 		// Validate plan names
 		for _, name := range args {
 			if !contains(available, name) {
-				return fmt.Errorf("plan '%s' not found", name)
+				return errPlanNotFound(name)
 			}
 		}
 		planNames = args
 	}
 
+	// Hold back plans downstream of an escalated agent (--pause-downstream)
+	if planNames = skipPausedPlans(planNames); len(planNames) == 0 {
+		fmt.Println("No plans to run.")
+		return nil
+	}
+
 	// Validate dependency graph before launching (with mode awareness)
-	planDeps, validationErrs := ValidatePlansWithMode(info)
+	var planDeps []PlanDependencies
+	var validationErrs []error
+	if runGroup != "" {
+		planDeps, validationErrs = ValidatePlanGroup(info, runGroup)
+	} else {
+		planDeps, validationErrs = ValidatePlansWithMode(info)
+	}
 	if len(validationErrs) > 0 {
 		fmt.Println("Dependency validation failed:")
 		for _, err := range validationErrs {
 			fmt.Printf("  ✗ %s\n", err)
 		}
 		fmt.Println("\nRun 'air plan validate' for details, or fix plans before running.")
-		return fmt.Errorf("invalid dependency graph")
+		return withCode(codeValidationFailed, fmt.Errorf("invalid dependency graph"))
 	}
 
 	// Build a map of plan name -> PlanDependencies for repo lookup
@@ -95,6 +244,39 @@ func runRun(cmd *cobra.Command, args []string) error {
 		planInfoMap[pd.Name] = pd
 	}
 
+	// Narrow to one phase, once the phases before it are done and integrated
+	if phased {
+		phase, inPhase, err := selectPhase(info, planDeps, runPhase)
+		if err != nil {
+			return err
+		}
+		var kept []string
+		for _, name := range planNames {
+			if contains(inPhase, name) {
+				kept = append(kept, name)
+			}
+		}
+		if planNames = kept; len(planNames) == 0 {
+			fmt.Printf("No plans to run in phase %d.\n", phase)
+			return nil
+		}
+		fmt.Printf("Phase %d: %s\n\n", phase, strings.Join(planNames, ", "))
+	}
+
+	// Warn about waits that signals from a previous run would satisfy immediately
+	var selected []PlanDependencies
+	for _, name := range planNames {
+		selected = append(selected, planInfoMap[name])
+	}
+	warnings := append(optionalWaitWarnings(planDeps), staleSignalWarnings(selected, time.Now())...)
+	if len(warnings) > 0 {
+		fmt.Println("Warnings:")
+		for _, w := range warnings {
+			fmt.Printf("  ⚠ %s\n", w)
+		}
+		fmt.Println()
+	}
+
 	// Dry run: show what would happen and exit
 	if dryRun {
 		fmt.Println("Validation passed. Would launch agents for:")
@@ -102,6 +284,8 @@ func runRun(cmd *cobra.Command, args []string) error {
 			pd := planInfoMap[name]
 			if info.Mode == ModeWorkspace && pd.Repository != "" {
 				fmt.Printf("  %s [repo: %s] (branch: air/%s)\n", name, pd.Repository, name)
+			} else if info.Mode == ModeMonorepo && pd.Component != "" {
+				fmt.Printf("  %s [component: %s] (branch: air/%s)\n", name, pd.Component, name)
 			} else {
 				fmt.Printf("  %s (branch: air/%s)\n", name, name)
 			}
@@ -110,6 +294,32 @@ func runRun(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	command := "run"
+	if prepareOnly {
+		command = "prepare"
+	}
+	if err := acquireRunLock(command, runForce); err != nil {
+		return err
+	}
+
+	// Show what will be created and confirm before any branch exists
+	printLaunchSummary(info, planNames, planInfoMap)
+	if !runYes && isTerminal(os.Stdin) {
+		verb := "Launch"
+		if prepareOnly {
+			verb = "Prepare"
+		}
+		fmt.Printf("\n%s %d agents? [y/N] ", verb, len(planNames))
+		reader := bufio.NewReader(os.Stdin)
+		response, _ := reader.ReadString('\n')
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+	fmt.Println()
+
 	// Read context once
 	contextContent, err := os.ReadFile(getContextPath())
 	if err != nil {
@@ -131,166 +341,322 @@ func runRun(cmd *cobra.Command, args []string) error {
 	if err := os.MkdirAll(channelsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create channels directory: %w", err)
 	}
-
-	// Permission and allowed tools flags for claude
-	permFlag := ""
-	if !noAutoAccept {
-		permFlag = "--permission-mode acceptEdits"
+	if err := writeRunStarted(channelsDir, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to record run start: %w", err)
 	}
 
-	// Language-agnostic allowed tools: air commands, read-only git, info gathering
-	allowedTools := `--allowedTools "Bash(air:*) Bash(git status:*) Bash(git log:*) Bash(git diff:*) Bash(git branch:*) Bash(git merge-tree:*) Bash(mkdir:*) Bash(ls:*) Bash(find:*) Bash(cat:*) Bash(head:*) Bash(tail:*) Bash(wc:*)"`
-
-	// Settings: disable co-authored-by to keep commits clean
-	settings := `--settings '{"includeCoAuthoredBy": false}'`
+	// Track worktree paths for tmux, and their run.json entries
+	var agents []worktreeInfo
+	var runAgents []RunAgent
 
-	// Track worktree paths for tmux
-	type agentInfo struct {
-		name       string
-		wtPath     string
-		agentDir   string
-		repoName   string
-		repoPath   string
-	}
-	var agents []agentInfo
+	// Ctrl-C or SIGTERM while worktrees are being created, or a failure, rolls
+	// back the ones this run created instead of leaving the project half-prepared
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	var created []worktreeInfo
 
 	// Create worktrees for each plan
 	for _, name := range planNames {
-		pd := planInfoMap[name]
-
-		// Determine target repo and paths based on mode
-		var repoName, repoPath, wtPath string
-		if info.Mode == ModeWorkspace {
-			repoName = pd.Repository
-			repoPath = filepath.Join(info.Root, repoName)
-			// In workspace mode: worktrees/<repo>/<plan>
-			repoWorktreeDir := filepath.Join(worktreesDir, repoName)
-			os.MkdirAll(repoWorktreeDir, 0755)
-			wtPath = filepath.Join(repoWorktreeDir, name)
-		} else {
-			repoName = ""
-			repoPath = info.Root
-			// In single mode: worktrees/<plan>
-			wtPath = filepath.Join(worktreesDir, name)
+		if agent, ok := newAgent(info, planInfoMap[name]); ok {
+			created = append(created, agent)
 		}
-
-		branch := "air/" + name
-
-		// Check if worktree already exists
-		if _, err := os.Stat(wtPath); err == nil {
-			fmt.Printf("Worktree %s already exists\n", name)
-		} else {
-			// Create worktree in the target repo
-			createCmd := exec.Command("git", "worktree", "add", wtPath, "-b", branch)
-			createCmd.Dir = repoPath
-			createCmd.Stdout = os.Stdout
-			createCmd.Stderr = os.Stderr
-			if err := createCmd.Run(); err != nil {
-				return fmt.Errorf("failed to create worktree for %s: %w", name, err)
-			}
-			if info.Mode == ModeWorkspace {
-				fmt.Printf("Created worktree: %s [repo: %s] (branch: %s)\n", name, repoName, branch)
-			} else {
-				fmt.Printf("Created worktree: %s (branch: %s)\n", wtPath, branch)
-			}
+		agent, runAgent, err := prepareAgent(info, planInfoMap[name], planDeps, contextContent)
+		// A Ctrl-C also reaches git, so the signal may come with an error
+		select {
+		case sig := <-interrupted:
+			err = withCode(codeInterrupted, fmt.Errorf("interrupted (%s) while preparing agents", sig))
+		default:
 		}
-
-		// Read plan content
-		planContent, err := os.ReadFile(filepath.Join(plansDir, name+".md"))
 		if err != nil {
-			return fmt.Errorf("failed to read plan %s: %w", name, err)
+			signal.Stop(interrupted)
+			return rollbackPrepared(created, err)
 		}
+		agents = append(agents, agent)
+		runAgents = append(runAgents, runAgent)
+	}
+	signal.Stop(interrupted)
 
-		// Build the assignment prompt
-		assignment := fmt.Sprintf("Your assignment:\n\n%s\n\nImplement this.", string(planContent))
+	if err := recordRun(info, runAgents); err != nil {
+		return fmt.Errorf("failed to write run.json: %w", err)
+	}
+
+	if prepareOnly {
+		fmt.Printf("\nPrepared %d agents. Inspect or seed their worktrees, then start them with 'air launch'.\n", len(agents))
+		return nil
+	}
+	return launchAgents(info, agents)
+}
 
-		// Create agent data directory
-		agentDir := filepath.Join(agentsDir, name)
-		os.MkdirAll(agentDir, 0755)
+// prepareAgent creates a plan's worktree (unless it exists), agent directory,
+// briefing, and launch script, and returns the agent and its run.json entry
+func prepareAgent(info *WorkspaceInfo, pd PlanDependencies, planDeps []PlanDependencies, contextContent []byte) (worktreeInfo, RunAgent, error) {
+	name := pd.Name
+	permFlag, allowedTools, settings := claudeFlags()
 
-		// Write context and assignment files
-		if err := os.WriteFile(filepath.Join(agentDir, "context"), contextContent, 0644); err != nil {
-			return fmt.Errorf("failed to write context for %s: %w", name, err)
+	repoName, repoPath, wtPath := agentPaths(info, pd)
+	os.MkdirAll(filepath.Dir(wtPath), 0755)
+
+	branch := "air/" + name
+
+	// Create agent data directory
+	agentDir := filepath.Join(getAgentsDir(), name)
+	os.MkdirAll(agentDir, 0755)
+
+	// Check if worktree already exists
+	if _, err := os.Stat(wtPath); err == nil {
+		fmt.Printf("Worktree %s already exists\n", name)
+	} else {
+		// Record what the branch is created from, for ahead/behind tracking
+		base := resolveAgentBase(repoPath, info.baseBranch(repoName))
+		if err := writeAgentBase(agentDir, base); err != nil {
+			return worktreeInfo{}, RunAgent{}, fmt.Errorf("failed to record base for %s: %w", name, err)
 		}
-		if err := os.WriteFile(filepath.Join(agentDir, "assignment"), []byte(assignment), 0644); err != nil {
-			return fmt.Errorf("failed to write assignment for %s: %w", name, err)
+
+		// Create worktree in the target repo (from the configured base branch, if any)
+		worktreeArgs := []string{"worktree", "add", wtPath, "-b", branch}
+		if configured := info.baseBranch(repoName); configured != "" {
+			worktreeArgs = append(worktreeArgs, configured)
+		}
+		createCmd := exec.Command("git", worktreeArgs...)
+		createCmd.Dir = repoPath
+		createCmd.Stdout = os.Stdout
+		createCmd.Stderr = os.Stderr
+		if err := createCmd.Run(); err != nil {
+			return worktreeInfo{}, RunAgent{}, fmt.Errorf("failed to create worktree for %s: %w", name, err)
+		}
+		if info.Mode == ModeWorkspace {
+			fmt.Printf("Created worktree: %s [repo: %s] (branch: %s)\n", name, repoName, branch)
+		} else {
+			fmt.Printf("Created worktree: %s (branch: %s)\n", wtPath, branch)
 		}
+	}
 
-		// Generate launcher script with workspace-aware environment variables
-		sshAuthSock := os.Getenv("SSH_AUTH_SOCK")
-		sshExport := ""
-		if sshAuthSock != "" {
-			sshExport = fmt.Sprintf("export SSH_AUTH_SOCK=\"%s\"\n", sshAuthSock)
+	// A malformed identity is an error here, before it goes in launch.sh
+	if _, _, _, err := agentAuthor(repoPath, name); err != nil {
+		return worktreeInfo{}, RunAgent{}, err
+	}
+	if format := commitFormat(repoPath, pd); format != "" && commitHookEnabled(repoPath) {
+		if err := installCommitHook(wtPath, name, format); err != nil {
+			return worktreeInfo{}, RunAgent{}, err
 		}
+	}
 
-		// Workspace-specific env vars
-		workspaceEnv := ""
-		if info.Mode == ModeWorkspace {
-			workspaceEnv = fmt.Sprintf(`export AIR_REPO="%s"
-export AIR_WORKSPACE="%s"
-export AIR_WORKSPACE_ROOT="%s"
-`, repoName, info.Name, info.Root)
-		}
-
-		launcherScript := fmt.Sprintf(`#!/bin/bash
-%s%sexport AIR_AGENT_ID="%s"
-export AIR_WORKTREE="%s"
-export AIR_PROJECT_ROOT="%s"
-export AIR_CHANNELS_DIR="%s"
-cd "$AIR_WORKTREE"
+	if err := writeAgentBriefing(info, pd, planDeps, contextContent); err != nil {
+		return worktreeInfo{}, RunAgent{}, err
+	}
+
+	// Generate launcher script with workspace-aware environment variables
+	var exports strings.Builder
+	patterns, redacted := loadSecretPatterns(info.Root), 0
+	for _, v := range agentEnv(info, pd, readAgentBase(name)) {
+		value, n := redactSecrets(v.Value, patterns)
+		redacted += n
+		fmt.Fprintf(&exports, "export %s=%s\n", v.Name, shellQuote(value))
+	}
+	warnRedacted(name, "launch.sh", redacted)
+	launcherScript := fmt.Sprintf(`#!/bin/bash
+%scd "$AIR_WORKTREE"
+# Wait for a machine-wide agent slot, held by this process (see 'air slots')
+%s agent slot --pid $$
+# Heartbeat while this process (claude, after exec) runs
+(while kill -0 $$ 2>/dev/null; do %s agent heartbeat --pid $$ >/dev/null 2>&1; sleep %d; done) &
 exec claude %s %s %s --append-system-prompt "$(cat %s/context)" "$(cat %s/assignment)"
-`, sshExport, workspaceEnv, name, wtPath, repoPath, channelsDir, permFlag, allowedTools, settings, agentDir, agentDir)
+`, exports.String(), shellQuote(airExecutable()), shellQuote(airExecutable()), int(heartbeatInterval.Seconds()), permFlag, allowedTools, settings, agentDir, agentDir)
+
+	scriptPath := filepath.Join(agentDir, "launch.sh")
+	if err := os.WriteFile(scriptPath, []byte(launcherScript), 0755); err != nil {
+		return worktreeInfo{}, RunAgent{}, fmt.Errorf("failed to write launcher script for %s: %w", name, err)
+	}
 
-		scriptPath := filepath.Join(agentDir, "launch.sh")
-		if err := os.WriteFile(scriptPath, []byte(launcherScript), 0755); err != nil {
-			return fmt.Errorf("failed to write launcher script for %s: %w", name, err)
+	agent := worktreeInfo{
+		name:     name,
+		wtPath:   wtPath,
+		repoName: repoName,
+		repoPath: repoPath,
+	}
+	return agent, RunAgent{
+		Name:      name,
+		State:     agentPrepared,
+		Repo:      repoName,
+		Component: pd.Component,
+		RepoPath:  repoPath,
+		Branch:    branch,
+		Worktree:  wtPath,
+		Base:      readAgentBase(name),
+		Prepared:  time.Now().UTC(),
+	}, nil
+}
+
+// newAgent returns pd's agent if neither its worktree nor its branch exists,
+// so preparing it creates both; ok is false if either is already there
+func newAgent(info *WorkspaceInfo, pd PlanDependencies) (agent worktreeInfo, ok bool) {
+	repoName, repoPath, wtPath := agentPaths(info, pd)
+	if _, err := os.Stat(wtPath); err == nil {
+		return worktreeInfo{}, false
+	}
+	if exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/air/"+pd.Name).Run() == nil {
+		return worktreeInfo{}, false
+	}
+	return worktreeInfo{name: pd.Name, repoName: repoName, repoPath: repoPath, wtPath: wtPath}, true
+}
+
+// rollbackPrepared removes the worktrees, branches, and agent data of agents an
+// interrupted or failed run created, and releases its run lock, so the next
+// command finds the project as it was. Returns cause.
+func rollbackPrepared(created []worktreeInfo, cause error) error {
+	if len(created) > 0 {
+		cleanWorkspaceWorktrees(created, cleanOptions{deleteBranches: true, keepPlans: true, quiet: true})
+		var names []string
+		for _, agent := range created {
+			names = append(names, agent.name)
 		}
+		fmt.Printf("\nRolled back %s (worktrees, branches, and agent data)\n", strings.Join(names, ", "))
+	}
+	releaseRunLock()
+	return cause
+}
 
-		agents = append(agents, agentInfo{
-			name:     name,
-			wtPath:   wtPath,
-			agentDir: agentDir,
-			repoName: repoName,
-			repoPath: repoPath,
-		})
+// agentAllowedTools are the commands agents may run without asking:
+// language-agnostic air commands, read-only git, and info gathering. Projects
+// add their stack's in allowed-tools (see projectAllowedTools).
+var agentAllowedTools = []string{
+	"Bash(air:*)", "Bash(git status:*)", "Bash(git log:*)", "Bash(git diff:*)", "Bash(git branch:*)", "Bash(git merge-tree:*)",
+	"Bash(mkdir:*)", "Bash(ls:*)", "Bash(find:*)", "Bash(cat:*)", "Bash(head:*)", "Bash(tail:*)", "Bash(wc:*)",
+}
+
+// claudeFlags returns the permission, allowed tools, and settings flags
+// launch scripts pass to claude, from the run flags
+func claudeFlags() (permFlag, allowedTools, settings string) {
+	if !noAutoAccept {
+		permFlag = "--permission-mode acceptEdits"
+	}
+	allowedTools = fmt.Sprintf("--allowedTools %q", strings.Join(projectAllowedTools(), " "))
+
+	// Settings: disable co-authored-by to keep commits clean
+	settings = `--settings '{"includeCoAuthoredBy": false}'`
+	if runModel != "" {
+		settings += " --model " + shellQuote(runModel)
+	}
+	return permFlag, allowedTools, settings
+}
+
+// agentPaths returns the repo a plan's agent works in and its worktree path:
+// worktrees/<repo>/<plan> in workspace mode, worktrees/<plan> otherwise
+func agentPaths(info *WorkspaceInfo, pd PlanDependencies) (repoName, repoPath, wtPath string) {
+	if info.Mode == ModeWorkspace {
+		return pd.Repository, info.repoDir(pd.Repository), filepath.Join(getWorktreesDir(), pd.Repository, pd.Name)
+	}
+	return "", info.Root, filepath.Join(getWorktreesDir(), pd.Name)
+}
+
+// envVar is one variable exported by an agent's launch script
+type envVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// agentEnv returns the environment launch.sh exports for a plan's agent, in order
+func agentEnv(info *WorkspaceInfo, pd PlanDependencies, base *agentBase) []envVar {
+	repoName, repoPath, wtPath := agentPaths(info, pd)
+	var env []envVar
+	add := func(name, value string) { env = append(env, envVar{name, value}) }
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		add("SSH_AUTH_SOCK", sock)
+	}
+	env = append(env, agentAuthorEnv(repoPath, pd.Name)...)
+	env = append(env, commitHookEnv(repoPath, pd)...)
+	switch info.Mode {
+	case ModeWorkspace:
+		add("AIR_REPO", repoName)
+		add("AIR_WORKSPACE", info.Name)
+		add("AIR_WORKSPACE_ROOT", info.Root)
+	case ModeMonorepo:
+		add("AIR_COMPONENT", pd.Component)
+	}
+	if base != nil && base.SHA != "" {
+		add("AIR_BASE_SHA", base.SHA)
 	}
+	if len(pd.Consumes) > 0 {
+		var specs []string
+		for _, c := range pd.Consumes {
+			specs = append(specs, c.String())
+		}
+		add("AIR_CONSUMES", strings.Join(specs, ","))
+	}
+	if runChannelTTL > 0 {
+		add("AIR_CHANNEL_TTL", runChannelTTL.String())
+	}
+	add("AIR_AGENT_ID", pd.Name)
+	add("AIR_WORKTREE", wtPath)
+	add("AIR_PROJECT_ROOT", repoPath)
+	add("AIR_CHANNELS_DIR", getChannelsDir())
+	add("AIR_ARTIFACTS_DIR", getArtifactsDir())
+	add("AIR_AGENT_DIR", filepath.Join(getAgentsDir(), pd.Name))
+	return env
+}
 
-	// Start tmux session
-	sessionName := "air"
+// launchAgents starts a tmux session with a window per agent running its
+// launch.sh, plus a dashboard window, and attaches to it
+func launchAgents(info *WorkspaceInfo, agents []worktreeInfo) error {
+	sessionName := tmuxSession()
 
-	// Kill existing session if present
-	exec.Command("tmux", "kill-session", "-t", sessionName).Run()
+	now := time.Now()
+	attempts, err := nextAttempts(agents, now)
+	if err != nil {
+		return err
+	}
+
+	if b := launchBackoff(); b != nil {
+		fmt.Printf("Rate limited: %s. Agents will wait in their windows ('air slots --resume' to start them now).\n", formatBackoff(b))
+	}
 
 	// Create new session with first agent
 	firstAgent := agents[0]
 
-	// Create session
-	tmuxNew := exec.Command("tmux", "new-session", "-d", "-s", sessionName, "-n", firstAgent.name, "-c", firstAgent.wtPath)
+	// Create session, replacing any existing one in the same tmux command:
+	// killing the server's last session shuts the server down, and a separate
+	// new-session that reaches it while it exits fails
+	tmuxArgs := []string{"new-session", "-d", "-s", sessionName, "-n", firstAgent.name, "-c", firstAgent.wtPath}
+	if exec.Command("tmux", "has-session", "-t", "="+sessionName).Run() == nil {
+		tmuxArgs = append([]string{"kill-session", "-t", "=" + sessionName, ";"}, tmuxArgs...)
+	}
+	tmuxNew := exec.Command("tmux", tmuxArgs...)
 	if err := tmuxNew.Run(); err != nil {
 		return fmt.Errorf("failed to create tmux session: %w", err)
 	}
 
 	// Run launcher script for first agent
-	exec.Command("tmux", "send-keys", "-t", sessionName+":"+firstAgent.name, firstAgent.agentDir+"/launch.sh", "Enter").Run()
+	startAgentWindow(sessionName, firstAgent)
 
 	// Create windows for remaining agents
 	for _, agent := range agents[1:] {
-		// Create window
 		exec.Command("tmux", "new-window", "-t", sessionName, "-n", agent.name, "-c", agent.wtPath).Run()
+		startAgentWindow(sessionName, agent)
+	}
 
-		// Run launcher script
-		exec.Command("tmux", "send-keys", "-t", sessionName+":"+agent.name, agent.agentDir+"/launch.sh", "Enter").Run()
+	if err := lockRunSession(sessionName, agents); err != nil {
+		fmt.Printf("Warning: failed to update %s: %v\n", runLockFile, err)
 	}
+	recordLaunches(agents, attempts, newRunID(now), now)
 
-	// Create dashboard window
+	// Create dashboard window running live status (Ctrl-C drops to a shell)
 	dashDir := info.Root
 	exec.Command("tmux", "new-window", "-t", sessionName, "-n", "dash", "-c", dashDir).Run()
+	exec.Command("tmux", "send-keys", "-t", sessionName+":dash", shellQuote(airExecutable())+" status --watch", "Enter").Run()
+
+	if tmuxStatusBar {
+		configureStatusBar(sessionName, info, agents)
+	}
 
 	// Select first agent window
 	exec.Command("tmux", "select-window", "-t", sessionName+":"+firstAgent.name).Run()
 
 	fmt.Printf("\nLaunched %d agents in tmux session '%s'\n", len(agents), sessionName)
 	fmt.Println("Attach with: tmux attach -t", sessionName)
+	if noAttach {
+		return nil
+	}
 
 	// Attach to session
 	attachCmd := exec.Command("tmux", "attach", "-t", sessionName)
@@ -300,22 +666,468 @@ exec claude %s %s %s --append-system-prompt "$(cat %s/context)" "$(cat %s/assign
 	return attachCmd.Run()
 }
 
-func getAvailablePlans(plansDir string) ([]string, error) {
-	entries, err := os.ReadDir(plansDir)
+// addAgentsToSession opens a window running launch.sh for each agent in the
+// run's tmux session, leaving the session and the agents already in it alone.
+// The agents join the current run rather than starting a new one.
+func addAgentsToSession(info *WorkspaceInfo, agents []worktreeInfo) error {
+	sessionName := runSession()
+	if exec.Command("tmux", "has-session", "-t", "="+sessionName).Run() != nil {
+		return fmt.Errorf("no tmux session '%s' to add agents to", sessionName)
+	}
+	now := time.Now()
+	attempts, err := nextAttempts(agents, now)
+	if err != nil {
+		return err
+	}
+
+	// -d: don't pull whoever is watching away from their window. A finished
+	// agent's old window is replaced.
+	for _, agent := range agents {
+		if id := findAgentWindow(sessionName, agent.name); id != "" {
+			exec.Command("tmux", "kill-window", "-t", id).Run()
+		}
+		exec.Command("tmux", "new-window", "-d", "-t", sessionName, "-n", agent.name, "-c", agent.wtPath).Run()
+		startAgentWindow(sessionName, agent)
+	}
+	if tmuxStatusBar {
+		configureStatusBar(sessionName, info, agents)
+	}
+
+	// The run stays active while any of its agents, old or new, is at work
+	if err := addToRunSession(sessionName, agents); err != nil {
+		fmt.Printf("Warning: failed to update %s: %v\n", runLockFile, err)
+	}
+	recordLaunches(agents, attempts, currentRunID(), now)
+	return nil
+}
+
+// startAgentWindow runs an agent's launch.sh in its window of the session
+func startAgentWindow(sessionName string, agent worktreeInfo) {
+	tagAgentWindow(sessionName, agent.name)
+	exec.Command("tmux", "send-keys", "-t", sessionName+":"+agent.name, filepath.Join(getAgentsDir(), agent.name, "launch.sh"), "Enter").Run()
+}
+
+// recordLaunches records launched agents in the event log and run.json, as
+// part of run runID
+func recordLaunches(agents []worktreeInfo, attempts map[string]Attempt, runID string, now time.Time) {
+	var names []string
+	for _, agent := range agents {
+		recordEvent(Event{Kind: eventLaunched, Agent: agent.name, Run: runID, Repo: agent.repoName, Attempt: attempts[agent.name].Number})
+		clearFailure(agent.name)
+		names = append(names, agent.name)
+	}
+	if err := markRunAgents(names, agentRunning, runID, now.UTC()); err != nil {
+		fmt.Printf("Warning: failed to update run.json: %v\n", err)
+	}
+	if err := recordAttempts(attempts); err != nil {
+		fmt.Printf("Warning: failed to record attempts in run.json: %v\n", err)
+	}
+}
+
+// tagAgentWindow records the agent a window belongs to in the @air-agent window
+// option, which stays put when the window is renamed to show its state
+func tagAgentWindow(sessionName, agent string) {
+	exec.Command("tmux", "set-option", "-w", "-t", sessionName+":"+agent, "@air-agent", agent).Run()
+}
+
+// findAgentWindow returns the tmux window id of an agent's window in session,
+// matched by its @air-agent tag, or "" if it has none
+func findAgentWindow(session, agent string) string {
+	out, err := exec.Command("tmux", "list-windows", "-t", "="+session, "-F", "#{window_id}\t#{@air-agent}").Output()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
+		return ""
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if id, tag, ok := strings.Cut(line, "\t"); ok && tag == agent {
+			return id
 		}
-		return nil, fmt.Errorf("failed to read plans: %w", err)
 	}
+	return ""
+}
 
-	var plans []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
-			name := strings.TrimSuffix(entry.Name(), ".md")
-			plans = append(plans, name)
+// airExecutable returns the path of the running air binary, for commands run
+// inside tmux where air may not be on PATH
+func airExecutable() string {
+	if path, err := os.Executable(); err == nil {
+		return path
+	}
+	return "air"
+}
+
+// configureStatusBar makes each agent window's status-bar entry show the
+// agent's state, refreshed from channel files via 'air status --tmux'
+func configureStatusBar(sessionName string, info *WorkspaceInfo, agents []worktreeInfo) {
+	airBin := airExecutable()
+	for _, agent := range agents {
+		state := fmt.Sprintf("#(cd %s && %s status --tmux %s)", shellQuote(info.Root), shellQuote(airBin), shellQuote(agent.name))
+		target := sessionName + ":" + agent.name
+		exec.Command("tmux", "set-window-option", "-t", target, "window-status-format", "#I:#W "+state).Run()
+		exec.Command("tmux", "set-window-option", "-t", target, "window-status-current-format", "#I:#W* "+state).Run()
+	}
+	exec.Command("tmux", "set-option", "-t", sessionName, "status-interval", "5").Run()
+}
+
+// printLaunchSummary prints a table of what 'air run' is about to launch: each
+// plan's target, branch, the base it will branch from, dependencies, and model
+func printLaunchSummary(info *WorkspaceInfo, planNames []string, planInfoMap map[string]PlanDependencies) {
+	model := runModel
+	if model == "" {
+		model = "default"
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	switch info.Mode {
+	case ModeWorkspace:
+		fmt.Fprintln(w, "PLAN\tREPO\tBRANCH\tBASE\tDEPENDENCIES\tMODEL")
+	case ModeMonorepo:
+		fmt.Fprintln(w, "PLAN\tCOMPONENT\tBRANCH\tBASE\tDEPENDENCIES\tMODEL")
+	default:
+		fmt.Fprintln(w, "PLAN\tBRANCH\tBASE\tDEPENDENCIES\tMODEL")
+	}
+
+	for _, name := range planNames {
+		pd := planInfoMap[name]
+
+		repoName, repoPath := "", info.Root
+		if info.Mode == ModeWorkspace {
+			repoName, repoPath = pd.Repository, info.repoDir(pd.Repository)
+		}
+
+		// Existing worktrees keep the base they were created from
+		var base string
+		if recorded := readAgentBase(name); recorded != nil {
+			base = fmt.Sprintf("%s@%s (existing)", recorded.Branch, shortRef(recorded.SHA))
+		} else {
+			resolved := resolveAgentBase(repoPath, info.baseBranch(repoName))
+			base = fmt.Sprintf("%s@%s", resolved.Branch, shortRef(resolved.SHA))
+		}
+
+		var deps []string
+		if len(pd.WaitsOn) > 0 {
+			deps = append(deps, "waits: "+strings.Join(pd.WaitsOn, ","))
+		}
+		if len(pd.Optional) > 0 {
+			deps = append(deps, "optional: "+strings.Join(pd.Optional, ","))
+		}
+		if len(pd.Signals) > 0 {
+			deps = append(deps, "signals: "+strings.Join(pd.Signals, ","))
+		}
+		depText := strings.Join(deps, "; ")
+		if depText == "" {
+			depText = "-"
+		}
+
+		row := []string{name}
+		switch info.Mode {
+		case ModeWorkspace:
+			row = append(row, pd.Repository)
+		case ModeMonorepo:
+			row = append(row, pd.Component)
+		}
+		row = append(row, "air/"+name, base, depText, model)
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
+
+// planPickerItems describes each plan for the interactive picker: its objective,
+// plus its repository or component outside single-repo mode
+func planPickerItems(info *WorkspaceInfo, plans []string) []pickerItem {
+	items := make([]pickerItem, len(plans))
+	for i, name := range plans {
+		content, _ := os.ReadFile(planPath(name))
+		detail := parsePlanObjective(string(content))
+		pd := parsePlanDependencies(name, string(content))
+		if info.Mode == ModeWorkspace && pd.Repository != "" {
+			detail = fmt.Sprintf("[repo: %s] %s", pd.Repository, detail)
+		} else if info.Mode == ModeMonorepo && pd.Component != "" {
+			detail = fmt.Sprintf("[component: %s] %s", pd.Component, detail)
+		}
+		items[i] = pickerItem{name: name, detail: detail}
+	}
+	return items
+}
+
+// buildAgentContext appends the plan's context additions to the shared context:
+// context/<repo>.md (workspace) or context/<component>.md (monorepo) if present,
+// then each file listed under **Context files:**. The shared context's version
+// stamp is for air init, not the agent, so it's left out.
+func buildAgentContext(shared []byte, pd PlanDependencies, repoName string) ([]byte, error) {
+	_, body := readContextStamp(string(shared))
+	result := []byte(body)
+	for _, path := range agentContextFiles(pd, repoName) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, "\n\n"...)
+		result = append(result, content...)
+	}
+	return result, nil
+}
+
+// agentContextFiles returns the context/ files appended to a plan's context:
+// its repo or component's file if one exists, then its **Context files:**
+func agentContextFiles(pd PlanDependencies, repoName string) []string {
+	contextDir := getContextDir()
+
+	var paths []string
+	scope := repoName
+	if pd.Component != "" {
+		scope = pd.Component
+	}
+	if scope != "" {
+		path := contextFilePath(contextDir, scope)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	for _, name := range pd.Context {
+		if path := contextFilePath(contextDir, name); !contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// writeAgentBriefing writes an agent's context and assignment files from its
+// plan and the shared context. launch.sh reads them each time Claude starts.
+func writeAgentBriefing(info *WorkspaceInfo, pd PlanDependencies, plans []PlanDependencies, contextContent []byte) error {
+	name := pd.Name
+	agentDir := filepath.Join(getAgentsDir(), name)
+	if err := os.MkdirAll(agentDir, 0755); err != nil {
+		return fmt.Errorf("failed to create agent directory for %s: %w", name, err)
+	}
+
+	agentContext, assignment, err := assembleBriefing(info, pd, plans, contextContent)
+	if err != nil {
+		return err
+	}
+
+	// Both files are world-readable, so keep credentials out of them
+	patterns := loadSecretPatterns(info.Root)
+	agentContext, n := redactSecrets(agentContext, patterns)
+	warnRedacted(name, "context", n)
+	assignment, n = redactSecrets(assignment, patterns)
+	warnRedacted(name, "assignment", n)
+
+	if err := os.WriteFile(filepath.Join(agentDir, "context"), []byte(agentContext), 0644); err != nil {
+		return fmt.Errorf("failed to write context for %s: %w", name, err)
+	}
+	if err := os.WriteFile(filepath.Join(agentDir, "assignment"), []byte(assignment), 0644); err != nil {
+		return fmt.Errorf("failed to write assignment for %s: %w", name, err)
+	}
+	return nil
+}
+
+// assembleBriefing builds an agent's briefing before redaction: the context
+// appended to Claude's system prompt (the shared context plus its repo's and
+// plan's context files) and the assignment it starts with (the plan, then its
+// coordination steps and commit instructions)
+func assembleBriefing(info *WorkspaceInfo, pd PlanDependencies, plans []PlanDependencies, contextContent []byte) (agentContext, assignment string, err error) {
+	name := pd.Name
+	planContent, err := os.ReadFile(planPath(name))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read plan %s: %w", name, err)
+	}
+
+	assignment = fmt.Sprintf("Your assignment:\n\n%s\n\nImplement this.", string(planContent))
+	if info.Mode == ModeMonorepo && pd.Component != "" {
+		assignment += fmt.Sprintf("\n\nYour component is `%s`. Only modify files inside ./%s/.", pd.Component, pd.Component)
+	}
+	assignment += buildCoordinationSteps(pd, plansInGroup(plans, pd.Group), info.Mode)
+	repoName, repoPath, _ := agentPaths(info, pd)
+	assignment += buildCommitInstructions(commitFormat(repoPath, pd), name, commitHookEnabled(repoPath))
+
+	context, err := buildAgentContext(contextContent, pd, repoName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to build context for %s: %w", name, err)
+	}
+	return string(context), assignment, nil
+}
+
+// buildCoordinationSteps turns a plan's dependencies into the exact commands
+// the agent runs, in order: wait (and merge, for producers in the same repo)
+// on each channel, do the work, signal, then report done. plans is the full
+// plan set, used to find who signals each waited channel.
+func buildCoordinationSteps(pd PlanDependencies, plans []PlanDependencies, mode Mode) string {
+	producers := make(map[string]PlanDependencies)
+	for _, p := range plans {
+		for _, ch := range p.Signals {
+			producers[ch] = p
 		}
 	}
+
+	var steps []string
+	timeouts := false
+	for _, ch := range append(append([]string{}, pd.WaitsOn...), pd.Optional...) {
+		wait := fmt.Sprintf("`air agent wait %s`", ch)
+		if policy, ok := pd.waitPolicyFor(ch); ok {
+			wait = fmt.Sprintf("`air agent wait --timeout %s --on-timeout %s %s`", policy.Timeout, policy.Fallback, ch)
+			timeouts = true
+		}
+		merge := fmt.Sprintf("`air agent merge %s`", ch)
+		if contains(pd.Optional, ch) {
+			merge = "if it was signaled, " + merge
+		}
+
+		producer, ok := producers[ch]
+		switch {
+		case !ok:
+			steps = append(steps, fmt.Sprintf("Run %s (signaled outside the plan set; there is nothing to merge)", wait))
+		case mode == ModeWorkspace && producer.Repository != pd.Repository:
+			step := fmt.Sprintf("Run %s (%s works in repo `%s`, so don't merge it", wait, producer.Name, producer.Repository)
+			var consumed []string
+			for _, c := range pd.Consumes {
+				if c.Repo == producer.Repository {
+					consumed = append(consumed, c.String())
+				}
+			}
+			if len(consumed) > 0 {
+				step += fmt.Sprintf("; the wait copies %s into your worktree - review and commit it)", strings.Join(consumed, ", "))
+			} else {
+				step += fmt.Sprintf("; read its work with `air agent merge --fetch %s` if you need to)", ch)
+			}
+			steps = append(steps, step)
+		default:
+			steps = append(steps, fmt.Sprintf("Run %s, then %s", wait, merge))
+		}
+	}
+	steps = append(steps, "Implement your assignment and commit your changes")
+	for _, ch := range pd.Signals {
+		steps = append(steps, fmt.Sprintf("Run `air agent signal %s` once the work it stands for is committed", ch))
+	}
+	steps = append(steps, "Run `air agent done` when complete")
+
+	var sb strings.Builder
+	sb.WriteString("\n\n## Coordination\n\nFollow these steps in order, using exactly these commands:\n")
+	for i, step := range steps {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, step)
+	}
+	if timeouts {
+		sb.WriteString("\nOn timeout: `fail` exits with an error - signal BLOCKED. `proceed` returns without the dependency - continue without it and mention it in your done summary. `ask` keeps waiting while a human steps in.")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// agentBase records the branch and commit an agent's branch was created from
+type agentBase struct {
+	Branch string `json:"branch"`
+	SHA    string `json:"sha"`
+}
+
+// resolveAgentBase determines the base for a new agent branch: the configured
+// base branch if set, otherwise the branch currently checked out in the repo
+func resolveAgentBase(repoPath, configured string) agentBase {
+	branch := configured
+	if branch == "" {
+		out, _ := exec.Command("git", "-C", repoPath, "rev-parse", "--abbrev-ref", "HEAD").Output()
+		branch = strings.TrimSpace(string(out))
+	}
+	ref := branch
+	if ref == "" {
+		ref = "HEAD"
+	}
+	out, _ := exec.Command("git", "-C", repoPath, "rev-parse", ref).Output()
+	return agentBase{Branch: branch, SHA: strings.TrimSpace(string(out))}
+}
+
+// writeAgentBase stores the base record in the agent's data directory
+func writeAgentBase(agentDir string, base agentBase) error {
+	data, err := json.MarshalIndent(base, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(agentDir, "base.json"), data, 0644)
+}
+
+// readAgentBase loads the base record for an agent, or nil if none was recorded
+func readAgentBase(name string) *agentBase {
+	data, err := os.ReadFile(filepath.Join(getAgentDir(name), "base.json"))
+	if err != nil {
+		return nil
+	}
+	var base agentBase
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil
+	}
+	return &base
+}
+
+// baseSHA returns the commit an agent's work is measured from: its recorded
+// base, which 'air sync' advances, else the AIR_BASE_SHA it was launched with
+func baseSHA(agent string) string {
+	if base := readAgentBase(agent); base != nil && base.SHA != "" {
+		return base.SHA
+	}
+	if agent == os.Getenv("AIR_AGENT_ID") {
+		return os.Getenv("AIR_BASE_SHA")
+	}
+	return ""
+}
+
+// recordRun adds prepared agents to run.json, starting a new record if there is none
+func recordRun(info *WorkspaceInfo, agents []RunAgent) error {
+	return updateRunRecord(func(r *RunRecord) *RunRecord {
+		if r == nil {
+			r = &RunRecord{Started: time.Now().UTC(), Mode: info.Mode, Root: info.Root}
+			if info.Mode == ModeWorkspace {
+				r.Workspace = info.Name
+				r.Repos = info.Repos
+			}
+		}
+		r.Flags = RunFlags{
+			Model:           runModel,
+			NoAutoAccept:    noAutoAccept,
+			StatusBar:       tmuxStatusBar,
+			EscalateAfter:   runEscalateAfter.String(),
+			EscalateCmd:     runEscalateCmd,
+			PauseDownstream: runPauseDownstream,
+			MaxAttempts:     runMaxAttempts,
+			BaseUpdates:     runBaseUpdates,
+		}
+		if runChannelTTL > 0 {
+			r.Flags.ChannelTTL = runChannelTTL.String()
+		}
+		for _, a := range agents {
+			// Preparing again keeps the agent's attempt history
+			if existing := r.agent(a.Name); existing != nil {
+				a.Attempts = existing.Attempts
+			}
+			r.putAgent(a)
+		}
+		return r
+	})
+}
+
+// runStartedFile marks when the current run launched, inside the channels directory
+const runStartedFile = ".run-started"
+
+// writeRunStarted records the run start time in the channels directory
+func writeRunStarted(channelsDir string, t time.Time) error {
+	return os.WriteFile(filepath.Join(channelsDir, runStartedFile), []byte(t.Format(time.RFC3339)), 0644)
+}
+
+// readRunStarted returns when the current run launched, or the zero time if unknown
+func readRunStarted() time.Time {
+	data, err := os.ReadFile(filepath.Join(getChannelsDir(), runStartedFile))
+	if err != nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	return t
+}
+
+func getAvailablePlans(plansDir string) ([]string, error) {
+	files, err := listPlanFiles(plansDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []string
+	for _, f := range files {
+		plans = append(plans, f.name)
+	}
 	return plans, nil
 }
 
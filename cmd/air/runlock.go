@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// runLockFile marks a project's run in ~/.air/<project>/: the air command
+// starting or cleaning it up, then the tmux session and agents it launched
+const runLockFile = "run.lock"
+
+// runLock is the contents of run.lock
+type runLock struct {
+	PID     int       `json:"pid"` // the air command that took the lock
+	Command string    `json:"command"`
+	Started time.Time `json:"started"`
+	Session string    `json:"session,omitempty"`
+	Agents  []string  `json:"agents,omitempty"`
+}
+
+// getRunLockPath returns ~/.air/<project>/run.lock
+func getRunLockPath() string {
+	return filepath.Join(mustGetAirDir(), runLockFile)
+}
+
+// readRunLock returns the project's run lock, or nil if there is none
+func readRunLock() *runLock {
+	data, err := os.ReadFile(getRunLockPath())
+	if err != nil {
+		return nil
+	}
+	var l runLock
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil
+	}
+	return &l
+}
+
+// tmuxSession returns the name of the project's tmux session, air-<project>,
+// so runs in different projects never replace each other's agents. tmux
+// doesn't allow '.' or ':' in session names.
+func tmuxSession() string {
+	name, err := getProjectName()
+	if err != nil {
+		return "air"
+	}
+	return "air-" + strings.NewReplacer(".", "_", ":", "_").Replace(name)
+}
+
+// runSession returns the tmux session of the project's run: the one recorded
+// in run.lock, or the project's session if no run is recorded
+func runSession() string {
+	if l := readRunLock(); l != nil && l.Session != "" {
+		return l.Session
+	}
+	return tmuxSession()
+}
+
+// active explains why the locked run is still going, or returns "" once it's
+// over: the air command that took it is still running, or its tmux session
+// has agents that aren't done, failed, or stopped with 'air stop' (per
+// run.json, or their channels for records without states)
+func (l *runLock) active() string {
+	if l.PID > 0 && l.PID != os.Getpid() && processExists(l.PID) {
+		return fmt.Sprintf("'air %s' (pid %d) is in progress", l.Command, l.PID)
+	}
+	if l.Session == "" || exec.Command("tmux", "has-session", "-t", "="+l.Session).Run() != nil {
+		return ""
+	}
+	record, _ := readRunRecord()
+	var working []string
+	for _, agent := range l.Agents {
+		if record != nil {
+			if a := record.agent(agent); a != nil && a.finished() {
+				continue
+			}
+		}
+		if state := agentBarState(agent); state != "done" && state != "failed" {
+			working = append(working, agent)
+		}
+	}
+	if len(working) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("tmux session '%s' has agents at work (%s)", l.Session, strings.Join(working, ", "))
+}
+
+// requireActiveRun refuses unless a run's tmux session is up with agents at
+// work, for commands that add agents to it
+func requireActiveRun() error {
+	if l := readRunLock(); l == nil || l.Session == "" || l.active() == "" {
+		return fmt.Errorf("no active run to add plans to; start one with 'air run'")
+	}
+	return nil
+}
+
+// acquireRunLock takes the project's run lock for command, refusing while
+// another run is active unless force. The lock is written aside and linked into
+// place, so it never exists half-written and of two commands started together
+// only one gets it; a lock left by a finished run is broken as agent locks are.
+func acquireRunLock(command string, force bool) error {
+	data, err := json.MarshalIndent(runLock{PID: os.Getpid(), Command: command, Started: time.Now().UTC()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := getRunLockPath()
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to take run lock: %w", err)
+	}
+	defer os.Remove(tmp)
+
+	for try := 0; ; try++ {
+		err := os.Link(tmp, path)
+		if err == nil {
+			return nil
+		}
+		if !os.IsExist(err) || try == 2 {
+			return fmt.Errorf("failed to take run lock: %w", err)
+		}
+		held, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var l runLock
+		if json.Unmarshal(held, &l) == nil && !force {
+			if why := l.active(); why != "" {
+				return withCode(codeRunActive, fmt.Errorf("a run is already active in this project: %s, started %s ago.\nAttach with 'tmux attach -t %s', stop it with 'air clean', or pass --force to replace it",
+					why, formatDuration(time.Since(l.Started)), runSession()))
+			}
+		}
+		breakStaleLock(path, held)
+	}
+}
+
+// updateRunLock applies fn to run.lock under a lock file, since commands
+// adding agents to a run update it concurrently. fn receives a lock for this
+// command if there is none.
+func updateRunLock(fn func(l *runLock)) error {
+	path := getRunLockPath()
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	l := readRunLock()
+	if l == nil {
+		l = &runLock{PID: os.Getpid(), Command: "run", Started: time.Now().UTC()}
+	}
+	fn(l)
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write then rename so readers never see a partial file
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// lockRunSession records the tmux session and agents a run launched, which
+// keep the run active after the command that launched it exits
+func lockRunSession(session string, agents []worktreeInfo) error {
+	return updateRunLock(func(l *runLock) {
+		l.Session = session
+		l.Agents = nil
+		for _, agent := range agents {
+			l.Agents = append(l.Agents, agent.name)
+		}
+	})
+}
+
+// addToRunSession records agents added to the run's session beside those
+// already in it
+func addToRunSession(session string, agents []worktreeInfo) error {
+	return updateRunLock(func(l *runLock) {
+		l.Session = session
+		for _, agent := range agents {
+			if !contains(l.Agents, agent.name) {
+				l.Agents = append(l.Agents, agent.name)
+			}
+		}
+	})
+}
+
+// releaseRunLock removes the project's run lock
+func releaseRunLock() {
+	os.Remove(getRunLockPath())
+}
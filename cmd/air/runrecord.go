@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RunRecord is ~/.air/<project>/run.json: what 'air run' set up and how far
+// each agent has got. Written when agents are prepared, updated as they launch
+// and finish, and removed by a full 'air clean'. It's the registry of the
+// run's agents: commands look agents up here rather than in the worktrees
+// directory, which crashes and manual changes leave out of step.
+type RunRecord struct {
+	ID        string     `json:"id"` // launch batch of the most recent launch, matching events.jsonl
+	Started   time.Time  `json:"started"`
+	Mode      Mode       `json:"mode"`
+	Root      string     `json:"root"`
+	Workspace string     `json:"workspace,omitempty"`
+	Repos     []string   `json:"repos,omitempty"`
+	Flags     RunFlags   `json:"flags"`
+	Agents    []RunAgent `json:"agents"`
+
+	// Queue holds plans added with 'air enqueue' that haven't launched yet
+	Queue []QueuedPlan `json:"queue,omitempty"`
+}
+
+// RunFlags are the 'air run' flags that shaped the agents' launch scripts
+type RunFlags struct {
+	Model        string `json:"model,omitempty"`
+	NoAutoAccept bool   `json:"no_auto_accept,omitempty"`
+	ChannelTTL   string `json:"channel_ttl,omitempty"`
+	StatusBar    bool   `json:"status_bar,omitempty"`
+
+	// Escalation policy for blocked agents (see escalate.go)
+	EscalateAfter   string `json:"escalate_after,omitempty"`
+	EscalateCmd     string `json:"escalate_cmd,omitempty"`
+	PauseDownstream bool   `json:"pause_downstream,omitempty"`
+
+	// MaxAttempts caps how many times an agent may be launched (0: no cap)
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// BaseUpdates is the policy for new commits on agents' base branches:
+	// off, warn, or auto (see baseupdate.go). Empty means warn.
+	BaseUpdates string `json:"base_updates,omitempty"`
+}
+
+// use makes f the current run flags, so agents added to the run later get the
+// launch scripts its first agents got
+func (f RunFlags) use() {
+	runModel = f.Model
+	noAutoAccept = f.NoAutoAccept
+	tmuxStatusBar = f.StatusBar
+	runChannelTTL, _ = time.ParseDuration(f.ChannelTTL)
+	if d, err := time.ParseDuration(f.EscalateAfter); err == nil {
+		runEscalateAfter = d
+	}
+	runEscalateCmd = f.EscalateCmd
+	runPauseDownstream = f.PauseDownstream
+	runMaxAttempts = f.MaxAttempts
+	runBaseUpdates = f.BaseUpdates
+	if runBaseUpdates == "" {
+		runBaseUpdates = baseUpdatesWarn
+	}
+}
+
+// Agent states in run.json
+const (
+	agentPrepared = "prepared" // worktree and launch script ready, not launched
+	agentRunning  = "running"  // launched, and hasn't finished yet
+	agentDone     = "done"     // ran 'air agent done'
+	agentFailed   = "failed"   // ran 'air agent fail'
+	agentStopped  = "stopped"  // ended by 'air stop'
+)
+
+// RunAgent is one agent in the run
+type RunAgent struct {
+	Name      string     `json:"name"`
+	State     string     `json:"state,omitempty"`     // "" in records from before states were kept
+	Repo      string     `json:"repo,omitempty"`      // workspace mode
+	Component string     `json:"component,omitempty"` // monorepo mode
+	RepoPath  string     `json:"repo_path"`
+	Branch    string     `json:"branch"`
+	Worktree  string     `json:"worktree"`
+	Session   string     `json:"session,omitempty"` // tmux session it was launched in
+	PID       int        `json:"pid,omitempty"`     // its Claude process, from heartbeats
+	Base      *agentBase `json:"base,omitempty"`
+	Prepared  time.Time  `json:"prepared"`
+	Launched  *time.Time `json:"launched,omitempty"`
+	Done      *time.Time `json:"done,omitempty"`
+	Failed    *time.Time `json:"failed,omitempty"`
+	Stopped   *time.Time `json:"stopped,omitempty"`  // by 'air stop', since it last launched
+	Attempts  []Attempt  `json:"attempts,omitempty"` // one per launch, oldest first
+}
+
+// finished reports whether the agent's latest launch has ended
+func (a *RunAgent) finished() bool {
+	return a.State == agentDone || a.State == agentFailed || a.State == agentStopped
+}
+
+// worktree converts the agent to the worktreeInfo used by clean, status, and launch
+func (a RunAgent) worktree() worktreeInfo {
+	return worktreeInfo{name: a.Name, repoName: a.Repo, repoPath: a.RepoPath, wtPath: a.Worktree}
+}
+
+// getRunRecordPath returns ~/.air/<project>/run.json. Agents find it beside
+// their AIR_CHANNELS_DIR, like the event log.
+func getRunRecordPath() string {
+	if dir := os.Getenv("AIR_CHANNELS_DIR"); dir != "" {
+		return filepath.Join(filepath.Dir(dir), "run.json")
+	}
+	return filepath.Join(mustGetAirDir(), "run.json")
+}
+
+// readRunRecord loads run.json, or returns nil if no run has been prepared
+func readRunRecord() (*RunRecord, error) {
+	data, err := os.ReadFile(getRunRecordPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var record RunRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", getRunRecordPath(), err)
+	}
+	return &record, nil
+}
+
+// updateRunRecord applies fn to run.json under a lock file, since agents
+// finishing together update it concurrently. fn receives nil if there is no
+// record yet; returning nil leaves the file unchanged.
+func updateRunRecord(fn func(*RunRecord) *RunRecord) error {
+	path := getRunRecordPath()
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, err := readRunRecord()
+	if err != nil {
+		return err
+	}
+	record = fn(record)
+	if record == nil {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write then rename so readers never see a partial file
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// lockFile takes an exclusive lock by creating path, waiting for a holder to
+// release it. The file names its holder's PID: a lock over a few seconds old
+// whose holder has exited, or over a minute old, was left behind by a crash and
+// is broken. unlock removes the file only while it is still this lock.
+func lockFile(path string) (unlock func(), err error) {
+	const staleAfter = 5 * time.Second
+	token := []byte(fmt.Sprintf("%d %d\n", os.Getpid(), time.Now().UnixNano()))
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = f.Write(token)
+			f.Close()
+			if err != nil {
+				os.Remove(path)
+				return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+			}
+			return func() { breakStaleLock(path, token) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+
+		// A holder writes its PID right after creating the file, so only a
+		// lock older than staleAfter is judged
+		data, err := os.ReadFile(path)
+		fi, statErr := os.Stat(path)
+		if err == nil && statErr == nil && time.Since(fi.ModTime()) > staleAfter {
+			var pid int
+			fmt.Sscanf(string(data), "%d", &pid)
+			if (pid == 0 || !processExists(pid) || time.Since(fi.ModTime()) > time.Minute) && breakStaleLock(path, data) {
+				continue
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// agent returns the named agent's entry, or nil
+func (r *RunRecord) agent(name string) *RunAgent {
+	for i := range r.Agents {
+		if r.Agents[i].Name == name {
+			return &r.Agents[i]
+		}
+	}
+	return nil
+}
+
+// putAgent adds a, replacing any entry with the same name
+func (r *RunRecord) putAgent(a RunAgent) {
+	if existing := r.agent(a.Name); existing != nil {
+		*existing = a
+		return
+	}
+	r.Agents = append(r.Agents, a)
+}
+
+// runWorktrees returns the run's agent worktrees from run.json, falling back
+// to listing the worktrees directory for runs prepared before run.json existed
+func runWorktrees(info *WorkspaceInfo) ([]worktreeInfo, error) {
+	record, err := readRunRecord()
+	if err != nil || record == nil {
+		return listWorktrees(info)
+	}
+	var worktrees []worktreeInfo
+	for _, a := range record.Agents {
+		worktrees = append(worktrees, a.worktree())
+	}
+	return worktrees, nil
+}
+
+// markRunAgents moves the named agents to state in run.json, stamped with t.
+// A launch (agentRunning) is part of batch runID, and clears how the previous
+// one ended.
+func markRunAgents(names []string, state string, runID string, t time.Time) error {
+	return updateRunRecord(func(r *RunRecord) *RunRecord {
+		if r == nil {
+			return nil
+		}
+		if state == agentRunning {
+			r.ID = runID
+		}
+		for _, name := range names {
+			a := r.agent(name)
+			if a == nil {
+				continue
+			}
+			a.State = state
+			stamp := t
+			switch state {
+			case agentRunning:
+				// Launches always open a window in the project's tmux session
+				a.Launched, a.Session, a.PID = &stamp, tmuxSession(), 0
+				a.Done, a.Failed, a.Stopped = nil, nil, nil
+			case agentDone:
+				a.Done = &stamp
+			case agentFailed:
+				a.Failed = &stamp
+			case agentStopped:
+				a.Stopped = &stamp
+			}
+		}
+		return r
+	})
+}
+
+// recordAgentPID notes the agent's Claude process in run.json, when it's new
+func recordAgentPID(name string, pid int) error {
+	if r, _ := readRunRecord(); r == nil || r.agent(name) == nil || r.agent(name).PID == pid {
+		return nil
+	}
+	return updateRunRecord(func(r *RunRecord) *RunRecord {
+		if r == nil || r.agent(name) == nil {
+			return nil
+		}
+		r.agent(name).PID = pid
+		return r
+	})
+}
+
+// dropRunAgents removes agents from run.json, deleting it once none remain
+func dropRunAgents(names []string) error {
+	return updateRunRecord(func(r *RunRecord) *RunRecord {
+		if r == nil {
+			return nil
+		}
+		var kept []RunAgent
+		for _, a := range r.Agents {
+			if !contains(names, a.Name) {
+				kept = append(kept, a)
+			}
+		}
+		r.Agents = kept
+		if len(kept) == 0 {
+			os.Remove(getRunRecordPath())
+			return nil
+		}
+		return r
+	})
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var signalCmd = &cobra.Command{
+	Use:   "signal <channel>",
+	Short: "Signal a channel as a human",
+	Long: `Signals a channel from the main repo, with the agent recorded as "human".
+Use this for channels declared **External:** in plans (e.g. design-approved),
+or to stand in for an agent that failed to signal.
+
+The payload points at HEAD of the main repo, or at --sha if given. In workspace
+mode, pass --repo to choose the repo; without it the signal carries no commit.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSignal,
+}
+
+var humanSignalSHA string
+var humanSignalRepo string
+var humanSignalForce bool
+var humanSignalMessage string
+
+func init() {
+	signalCmd.Flags().StringVar(&humanSignalSHA, "sha", "", "Commit or branch to signal (default: HEAD)")
+	signalCmd.Flags().StringVar(&humanSignalRepo, "repo", "", "Repo the commit is in (workspace mode)")
+	signalCmd.Flags().BoolVar(&humanSignalForce, "force", false, "Overwrite an existing signal, keeping it in the channel history")
+	signalCmd.Flags().BoolVar(&humanSignalForce, "update", false, "Alias for --force")
+	signalCmd.Flags().StringVarP(&humanSignalMessage, "message", "m", "", "Attach a message to the signal")
+}
+
+// humanAgent is the agent name recorded for signals issued with 'air signal'
+const humanAgent = "human"
+
+func runSignal(cmd *cobra.Command, args []string) error {
+	channel := args[0]
+
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+
+	// Resolve the repo the signal points into (none for a bare workspace signal)
+	repoPath := ""
+	if info.Mode != ModeWorkspace || humanSignalRepo != "" {
+		repoPath, err = info.getRepoPath(humanSignalRepo)
+		if err != nil {
+			return err
+		}
+	} else if humanSignalSHA != "" {
+		return fmt.Errorf("--sha requires --repo in workspace mode")
+	}
+
+	var previous *ChannelPayload
+	if channelExists(channel) {
+		if !humanSignalForce {
+			return fmt.Errorf("channel '%s' has already been signaled (use --force to re-signal)", channel)
+		}
+		if previous, err = readChannel(channel); err != nil {
+			return err
+		}
+	}
+
+	payload := &ChannelPayload{
+		Agent:     humanAgent,
+		Worktree:  repoPath,
+		Timestamp: time.Now().UTC(),
+		Message:   humanSignalMessage,
+	}
+	if info.Mode == ModeWorkspace {
+		payload.Repo = humanSignalRepo
+		payload.Workspace = info.Name
+	}
+	if repoPath != "" {
+		payload.SHA, payload.Branch, err = resolveSignalRef(repoPath, humanSignalSHA)
+		if err != nil {
+			return err
+		}
+	}
+	if ttl := channelTTL(); ttl > 0 {
+		expires := payload.Timestamp.Add(ttl)
+		payload.ExpiresAt = &expires
+	}
+	appendHistory(payload, previous)
+
+	if err := writeChannel(channel, payload); err != nil {
+		return err
+	}
+
+	verb := "Signaled"
+	if previous != nil {
+		verb = "Re-signaled"
+	}
+	if payload.SHA != "" {
+		fmt.Printf("%s channel '%s' as %s (branch: %s, sha: %s)\n", verb, channel, humanAgent, payload.Branch, shortRef(payload.SHA))
+	} else {
+		fmt.Printf("%s channel '%s' as %s\n", verb, channel, humanAgent)
+	}
+	return nil
+}
+
+// resolveSignalRef resolves ref (default HEAD) in repoPath to a commit SHA and
+// the branch to record: ref itself if it names a branch, else the checked-out branch
+func resolveSignalRef(repoPath, ref string) (sha, branch string, err error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	out, err := exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("'%s' is not a commit in %s", ref, repoPath)
+	}
+	sha = strings.TrimSpace(string(out))
+
+	if exec.Command("git", "-C", repoPath, "show-ref", "--verify", "--quiet", "refs/heads/"+ref).Run() == nil {
+		return sha, ref, nil
+	}
+	out, _ = exec.Command("git", "-C", repoPath, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	return sha, strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var slotsCmd = &cobra.Command{
+	Use:   "slots",
+	Short: "Show or cap the agents running across all projects",
+	Long: `Claude's rate limits are per account, not per project, so air can cap how many
+agents run at once on this machine across every project. Each agent takes a
+slot when its launch script starts Claude, and waits for one to free up if the
+cap is reached; the slot is freed when its Claude process exits.
+
+When an agent's session ends in a rate limit or overload error, new agents
+wait too, for a minute after the first hit and twice as long after each hit
+that follows soon after (up to 30m), or until the limit resets if Claude said
+when. Agents already running aren't interrupted.
+
+Without flags, lists the agents holding slots and any rate limit pause. --max
+sets the machine-wide cap (0 removes it), and --resume lifts the pause. The
+cap, holders, and pause are kept in ~/.air/slots.json.`,
+	Args: cobra.NoArgs,
+	RunE: runSlots,
+}
+
+var agentSlotCmd = &cobra.Command{
+	Use:   "slot",
+	Short: "Wait for a machine-wide agent slot (called by launch.sh)",
+	Long: `Blocks until fewer agents than the cap set with 'air slots --max' hold
+slots across all projects, then takes one for --pid. Called by an agent's
+launch script before it starts Claude in the same process, so the slot is
+freed once Claude exits.`,
+	Args: cobra.NoArgs,
+	RunE: runAgentSlot,
+}
+
+var slotsMax int
+var slotsResume bool
+var slotPID int
+
+func init() {
+	supportsOutput(slotsCmd)
+	slotsCmd.Flags().IntVar(&slotsMax, "max", 0, "Cap agents running at once across all projects (0: no cap)")
+	slotsCmd.Flags().BoolVar(&slotsResume, "resume", false, "Let agents start again before a rate limit pause ends")
+
+	agentCmd.AddCommand(agentSlotCmd)
+	agentSlotCmd.Flags().IntVar(&slotPID, "pid", 0, "Process that holds the slot until it exits (default: the parent)")
+}
+
+// slotTable is ~/.air/slots.json: the machine-wide cap and who holds slots
+type slotTable struct {
+	Max     int          `json:"max"` // 0: no cap
+	Holders []slotHolder `json:"holders"`
+
+	// Backoff pauses launches after a rate limit hit (see noteRateLimit)
+	Backoff *slotBackoff `json:"backoff,omitempty"`
+}
+
+// slotHolder is an agent holding a slot while its process runs
+type slotHolder struct {
+	Project string    `json:"project"`
+	Agent   string    `json:"agent"`
+	PID     int       `json:"pid"`
+	Since   time.Time `json:"since"`
+}
+
+// getSlotsPath returns ~/.air/slots.json. It's a file, not a directory, so
+// it isn't mistaken for a project.
+func getSlotsPath() (string, error) {
+	root, err := getAirRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "slots.json"), nil
+}
+
+// updateSlots runs fn on the slot table under an exclusive lock, with holders
+// whose process has exited already dropped, and saves what fn leaves
+func updateSlots(fn func(t *slotTable) error) error {
+	path, err := getSlotsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	t := readSlots(path)
+	if err := fn(t); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readSlots loads the slot table at path, keeping only live holders
+func readSlots(path string) *slotTable {
+	t := &slotTable{}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, t)
+	}
+	live := []slotHolder{}
+	for _, h := range t.Holders {
+		if processExists(h.PID) {
+			live = append(live, h)
+		}
+	}
+	t.Holders = live
+	return t
+}
+
+func runSlots(cmd *cobra.Command, args []string) error {
+	var table *slotTable
+	err := updateSlots(func(t *slotTable) error {
+		if cmd.Flags().Changed("max") {
+			if slotsMax < 0 {
+				return withCode(codeUsage, fmt.Errorf("--max must be 0 or more"))
+			}
+			t.Max = slotsMax
+		}
+		// Ending the pause keeps what it was for, so the same error doesn't start another
+		if slotsResume && t.Backoff.active(time.Now()) {
+			t.Backoff.Until = time.Now().UTC()
+		}
+		table = t
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return render(table, func() {
+		if table.Max > 0 {
+			fmt.Printf("Agent slots in use across projects: %d/%d\n", len(table.Holders), table.Max)
+		} else {
+			fmt.Printf("Agents running across projects: %d (no cap; set one with 'air slots --max N')\n", len(table.Holders))
+		}
+		for _, h := range table.Holders {
+			fmt.Printf("  %-24s %-20s since %s\n", h.Project, h.Agent, h.Since.Local().Format(time.Kitchen))
+		}
+		if table.Backoff.active(time.Now()) {
+			fmt.Printf("\nPaused: %s ('air slots --resume' to start them now)\n", formatBackoff(table.Backoff))
+		}
+	})
+}
+
+func runAgentSlot(cmd *cobra.Command, args []string) error {
+	agentID := os.Getenv("AIR_AGENT_ID")
+	if agentID == "" {
+		return fmt.Errorf("AIR_AGENT_ID environment variable is required")
+	}
+	project := ""
+	if dir := os.Getenv("AIR_CHANNELS_DIR"); dir != "" {
+		project = filepath.Base(filepath.Dir(dir))
+	}
+	pid := slotPID
+	if pid == 0 {
+		pid = os.Getppid()
+	}
+
+	announced := ""
+	for {
+		taken, inUse, max := false, 0, 0
+		var backoff *slotBackoff
+		err := updateSlots(func(t *slotTable) error {
+			// A relaunched agent gives up the slot of its previous process
+			holders := t.Holders[:0]
+			for _, h := range t.Holders {
+				if h.Project != project || h.Agent != agentID {
+					holders = append(holders, h)
+				}
+			}
+			t.Holders = holders
+			inUse, max = len(t.Holders), t.Max
+			if t.Backoff.active(time.Now()) {
+				backoff = t.Backoff
+				return nil
+			}
+			if max == 0 || inUse < max {
+				t.Holders = append(t.Holders, slotHolder{Project: project, Agent: agentID, PID: pid, Since: time.Now().UTC()})
+				taken = true
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if taken {
+			if announced != "" {
+				fmt.Println("Got an agent slot, starting")
+			}
+			return nil
+		}
+		switch {
+		case backoff != nil && announced != "backoff":
+			fmt.Printf("Waiting: %s (see 'air slots')...\n", formatBackoff(backoff))
+			announced = "backoff"
+		case backoff == nil && announced != "cap":
+			fmt.Printf("Waiting for an agent slot (%d/%d in use across projects; see 'air slots')...\n", inUse, max)
+			announced = "cap"
+		}
+		time.Sleep(pollInterval())
+	}
+}
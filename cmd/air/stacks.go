@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scotro/air/cmd/air/prompts"
+)
+
+// stackTemplate is what 'air init' adds for one ecosystem: a section of
+// context.md and the commands its agents may run without asking
+type stackTemplate struct {
+	context string
+	tools   []string
+}
+
+// stackTemplates are keyed by the project types detectProjectType returns
+var stackTemplates = map[string]stackTemplate{
+	"Go": {prompts.StackGo, []string{
+		"Bash(go build:*)", "Bash(go test:*)", "Bash(go vet:*)", "Bash(gofmt:*)", "Bash(go mod tidy:*)",
+	}},
+	"Node.js/TypeScript": {prompts.StackNode, []string{
+		"Bash(npm test:*)", "Bash(npm run:*)", "Bash(yarn test:*)", "Bash(yarn run:*)", "Bash(pnpm test:*)", "Bash(pnpm run:*)", "Bash(npx tsc:*)",
+	}},
+	"Python": {prompts.StackPython, []string{
+		"Bash(pytest:*)", "Bash(python -m pytest:*)", "Bash(ruff:*)", "Bash(mypy:*)",
+	}},
+	"Rust": {prompts.StackRust, []string{
+		"Bash(cargo build:*)", "Bash(cargo test:*)", "Bash(cargo clippy:*)", "Bash(cargo fmt:*)", "Bash(cargo check:*)",
+	}},
+}
+
+// allowedToolsHeader starts the allowed-tools file 'air init' writes
+const allowedToolsHeader = "# Commands agents may run without asking, on top of air's own (one per line)\n"
+
+// projectStacks returns the project types of the project's repos (or
+// components) that have a template, in order and without repeats
+func projectStacks(info *WorkspaceInfo) []string {
+	dirs := []string{info.Root}
+	switch info.Mode {
+	case ModeWorkspace:
+		dirs = nil
+		for _, repo := range info.Repos {
+			dirs = append(dirs, info.repoDir(repo))
+		}
+	case ModeMonorepo:
+		for _, c := range info.Components {
+			dirs = append(dirs, filepath.Join(info.Root, c))
+		}
+	}
+	var stacks []string
+	for _, dir := range dirs {
+		stack := detectProjectType(dir)
+		if _, ok := stackTemplates[stack]; ok && !contains(stacks, stack) {
+			stacks = append(stacks, stack)
+		}
+	}
+	return stacks
+}
+
+// writeStackTools writes the allowed-tools file for stacks, unless one
+// already exists (it may have been edited)
+func writeStackTools(stacks []string) error {
+	path := getAllowedToolsPath()
+	if _, err := os.Stat(path); err == nil || len(stacks) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	sb.WriteString(allowedToolsHeader)
+	for _, stack := range stacks {
+		for _, tool := range stackTemplates[stack].tools {
+			sb.WriteString(tool + "\n")
+		}
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
+
+// projectAllowedTools returns the commands agents may run without asking:
+// air's own, then those in the project's allowed-tools file
+func projectAllowedTools() []string {
+	tools := append([]string{}, agentAllowedTools...)
+	data, err := os.ReadFile(getAllowedToolsPath())
+	if err != nil {
+		return tools
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") && !contains(tools, line) {
+			tools = append(tools, line)
+		}
+	}
+	return tools
+}
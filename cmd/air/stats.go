@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize historical runs",
+	Long: `Summarizes the project's run history from its event log
+(~/.air/<project>/events.jsonl), which 'air clean' leaves in place:
+
+  - launches, completions, and average agent duration (launch to 'air agent done')
+  - the longest-running plans
+  - the plans most often involved in merge conflicts
+  - tokens per run, from each agent's Claude transcripts when it finished
+
+Pass --price (USD per million tokens) to add an estimated cost column.`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+var statsTop int
+var statsPrice float64
+
+func init() {
+	statsCmd.Flags().IntVar(&statsTop, "top", 5, "Number of plans to list as longest and most conflicting")
+	statsCmd.Flags().Float64Var(&statsPrice, "price", 0, "USD per million tokens, for estimated cost")
+	supportsOutput(statsCmd)
+}
+
+// statsReport is the summary printed by 'air stats'
+type statsReport struct {
+	Launched        int             `json:"launched"`
+	Completed       int             `json:"completed"`
+	AverageDuration float64         `json:"average_duration_seconds"`
+	Longest         []planDuration  `json:"longest"`
+	Conflicts       []planConflicts `json:"conflicts"`
+	Runs            []runSummary    `json:"runs"`
+}
+
+// planDuration is one completed agent's launch-to-done time
+type planDuration struct {
+	Plan     string  `json:"plan"`
+	Run      string  `json:"run"`
+	Duration float64 `json:"duration_seconds"`
+}
+
+// planConflicts counts the merge conflicts a plan was part of
+type planConflicts struct {
+	Plan      string `json:"plan"`
+	Conflicts int    `json:"conflicts"`
+}
+
+// runSummary summarizes one launch batch
+type runSummary struct {
+	Run       string    `json:"run"`
+	Started   time.Time `json:"started"`
+	Agents    int       `json:"agents"`
+	Completed int       `json:"completed"`
+	Tokens    int64     `json:"tokens"`
+	Cost      float64   `json:"cost,omitempty"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+
+	events, err := readEvents()
+	if err != nil {
+		return fmt.Errorf("failed to read event log: %w", err)
+	}
+	report := computeStats(events, statsTop, statsPrice)
+	return render(report, func() { printStats(report) })
+}
+
+// computeStats summarizes events. A done event belongs to the agent's latest
+// launch before it; repeat done signals from the same launch count once.
+func computeStats(events []Event, top int, price float64) *statsReport {
+	report := &statsReport{Longest: []planDuration{}, Conflicts: []planConflicts{}, Runs: []runSummary{}}
+
+	type launch struct {
+		run  string
+		at   time.Time
+		done bool
+	}
+	launches := make(map[string]*launch) // latest launch per agent
+	runs := make(map[string]*runSummary)
+	var runOrder []string
+	conflicts := make(map[string]int)
+	var durations []planDuration
+
+	for _, e := range events {
+		switch e.Kind {
+		case eventLaunched:
+			report.Launched++
+			launches[e.Agent] = &launch{run: e.Run, at: e.Time}
+			r, ok := runs[e.Run]
+			if !ok {
+				r = &runSummary{Run: e.Run, Started: e.Time}
+				runs[e.Run] = r
+				runOrder = append(runOrder, e.Run)
+			}
+			r.Agents++
+		case eventDone:
+			l := launches[e.Agent]
+			if l == nil || l.done {
+				continue
+			}
+			l.done = true
+			report.Completed++
+			durations = append(durations, planDuration{Plan: e.Agent, Run: l.run, Duration: e.Time.Sub(l.at).Seconds()})
+			r := runs[l.run]
+			r.Completed++
+			if e.Tokens != nil {
+				r.Tokens += e.Tokens.total()
+			}
+		case eventConflict:
+			// Count each plan once per conflict
+			involved := append([]string{e.Agent}, e.With...)
+			seen := make(map[string]bool)
+			for _, p := range involved {
+				if p != "" && !seen[p] {
+					seen[p] = true
+					conflicts[p]++
+				}
+			}
+		}
+	}
+
+	if len(durations) > 0 {
+		var total float64
+		for _, d := range durations {
+			total += d.Duration
+		}
+		report.AverageDuration = total / float64(len(durations))
+	}
+
+	sort.SliceStable(durations, func(i, j int) bool { return durations[i].Duration > durations[j].Duration })
+	if len(durations) > top {
+		durations = durations[:top]
+	}
+	report.Longest = append(report.Longest, durations...)
+
+	for plan, n := range conflicts {
+		report.Conflicts = append(report.Conflicts, planConflicts{Plan: plan, Conflicts: n})
+	}
+	sort.Slice(report.Conflicts, func(i, j int) bool {
+		a, b := report.Conflicts[i], report.Conflicts[j]
+		if a.Conflicts != b.Conflicts {
+			return a.Conflicts > b.Conflicts
+		}
+		return a.Plan < b.Plan
+	})
+	if len(report.Conflicts) > top {
+		report.Conflicts = report.Conflicts[:top]
+	}
+
+	for _, id := range runOrder {
+		r := runs[id]
+		r.Cost = float64(r.Tokens) / 1_000_000 * price
+		report.Runs = append(report.Runs, *r)
+	}
+	return report
+}
+
+// printStats prints a stats report for humans
+func printStats(report *statsReport) {
+	if report.Launched == 0 {
+		fmt.Println("No run history yet. Launch agents with 'air run' to start recording it.")
+		return
+	}
+
+	fmt.Printf("Runs: %d   Agents launched: %d   Completed: %d\n", len(report.Runs), report.Launched, report.Completed)
+	if report.Completed > 0 {
+		fmt.Printf("Average agent duration: %s\n", formatDuration(secondsToDuration(report.AverageDuration)))
+	}
+
+	if len(report.Longest) > 0 {
+		fmt.Println("\nLongest plans")
+		for _, d := range report.Longest {
+			fmt.Printf("  %-24s %8s  (run %s)\n", d.Plan, formatDuration(secondsToDuration(d.Duration)), d.Run)
+		}
+	}
+
+	if len(report.Conflicts) > 0 {
+		fmt.Println("\nMost conflicting plans")
+		for _, c := range report.Conflicts {
+			fmt.Printf("  %-24s %d\n", c.Plan, c.Conflicts)
+		}
+	}
+
+	fmt.Println("\nTokens by run")
+	header := fmt.Sprintf("  %-16s %-17s %6s %6s %8s", "RUN", "STARTED", "AGENTS", "DONE", "TOKENS")
+	if statsPrice > 0 {
+		header += fmt.Sprintf(" %9s", "EST. COST")
+	}
+	fmt.Println(header)
+	for _, r := range report.Runs {
+		line := fmt.Sprintf("  %-16s %-17s %6d %6d %8s", r.Run, r.Started.Local().Format("2006-01-02 15:04"), r.Agents, r.Completed, formatCount(r.Tokens))
+		if statsPrice > 0 {
+			line += fmt.Sprintf(" %9s", fmt.Sprintf("$%.2f", r.Cost))
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+}
+
+// secondsToDuration converts float seconds to a time.Duration
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// formatDuration renders d compactly, e.g. 45s, 12m, 1h23m
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
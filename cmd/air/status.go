@@ -7,7 +7,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -15,22 +17,194 @@ import (
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Check status of running agents",
-	RunE:  runStatus,
+	Long: `Shows each agent's state, runtime, last commit, and uncommitted changes,
+followed by channels, files two agents at work are both editing (see
+'air conflicts --live'), timed-out waits, and held locks.
+
+Branches at least --stale-commits behind their base, or missing base commits
+older than --stale-days, are flagged stale: the longer they're left, the
+harder they are to integrate.
+
+Agents whose Claude transcript shows them stopped at a prompt for longer than
+--idle-after are flagged "needs attention": their turn ended without
+'air agent done', or a tool call has no result (usually a permission prompt).
+
+With --watch, refreshes every --interval until interrupted (the tmux "dash"
+window runs this), and renames and recolors the air session's agent windows by
+state (e.g. "✓api", "⚠auth") so the window list doubles as a status board.`,
+	RunE: runStatus,
+}
+
+var statusWatch bool
+var statusInterval time.Duration
+var statusIdleAfter time.Duration
+var statusStaleCommits int
+var statusStaleDays int
+
+// statusTmuxAgent prints one agent's state for the tmux status bar (air run --status-bar)
+var statusTmuxAgent string
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusWatch, "watch", false, "Refresh continuously until interrupted")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", 2*time.Second, "Refresh interval for --watch")
+	statusCmd.Flags().DurationVar(&statusIdleAfter, "idle-after", 2*time.Minute, "Flag agents idle at a prompt for longer than this")
+	statusCmd.Flags().IntVar(&statusStaleCommits, "stale-commits", 50, "Flag branches this many commits behind their base (0 disables)")
+	statusCmd.Flags().IntVar(&statusStaleDays, "stale-days", 7, "Flag branches missing base commits this many days old (0 disables)")
+	statusCmd.Flags().StringVar(&statusTmuxAgent, "tmux", "", "Print an agent's state as a tmux status symbol")
+	statusCmd.Flags().MarkHidden("tmux")
+	supportsOutput(statusCmd)
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
+	if statusTmuxAgent != "" {
+		fmt.Print(tmuxStateSymbols[agentBarState(statusTmuxAgent)])
+		return nil
+	}
+
+	if !statusWatch {
+		return printStatus()
+	}
+	if outputFormat != "text" {
+		return withCode(codeUsage, fmt.Errorf("--watch only supports text output"))
+	}
+	for {
+		// Clear screen and move cursor home
+		fmt.Print("\033[H\033[2J")
+		fmt.Printf("air status - %s\n\n", time.Now().Format("15:04:05"))
+		if err := printStatus(); err != nil {
+			return err
+		}
+		renameAgentWindows(runSession())
+		time.Sleep(statusInterval)
+	}
+}
+
+// statusReport is one snapshot of a project's agents and coordination state
+type statusReport struct {
+	Workspace string          `json:"workspace,omitempty"`
+	Agents    []agentStatus   `json:"agents"`
+	Channels  []channelStatus `json:"channels"`
+	Timeouts  []WaitTimeout   `json:"timeouts"`
+	Locks     []LockInfo      `json:"locks"`
+
+	// BaseUpdates is the run's --base-updates policy
+	BaseUpdates string `json:"base_updates"`
+
+	// Overlaps are files two agents still at work have both changed, an early
+	// warning of merge conflicts
+	Overlaps []fileOverlap `json:"overlaps"`
+
+	// Escalations are agents blocked past the run's --escalate-after
+	Escalations []Escalation `json:"escalations"`
+
+	// RateLimit is the machine-wide pause on launches after an agent hit
+	// Claude's rate limits, if one is in effect
+	RateLimit *slotBackoff `json:"rate_limit,omitempty"`
+
+	// Queue is the plans 'air enqueue' added that haven't launched yet
+	Queue []queuedStatus `json:"queue"`
+}
+
+// agentStatus is one agent's line in 'air status'
+type agentStatus struct {
+	Name        string `json:"name"`
+	Repo        string `json:"repo,omitempty"`
+	State       string `json:"state"` // done, failed, running, stopped (heartbeats ceased, or 'air stop'), or missing (worktree gone)
+	LastCommit  string `json:"last_commit"`
+	Uncommitted int    `json:"uncommitted"`
+	Divergence  string `json:"divergence,omitempty"`
+	BaseUpdates int    `json:"base_updates,omitempty"` // new commits on its base branch it lacks (unless --base-updates off)
+	Stale       string `json:"stale,omitempty"`        // how far it's behind, once past --stale-commits or --stale-days
+	Summary     string `json:"summary,omitempty"`
+	Failure     string `json:"failure,omitempty"` // reason given to 'air agent fail'
+
+	// Launched and Done come from run.json. Elapsed is launch to done, or to now while running.
+	Launched     *time.Time `json:"launched,omitempty"`
+	Done         *time.Time `json:"done,omitempty"`
+	Stopped      *time.Time `json:"stopped,omitempty"` // by 'air stop'
+	Elapsed      float64    `json:"elapsed_seconds,omitempty"`
+	LastCommitAt *time.Time `json:"last_commit_at,omitempty"`
+	NoCommits    bool       `json:"no_commits,omitempty"` // HEAD is still the base commit
+
+	// Attempt counts launches from run.json, capped by MaxAttempts (0: no cap),
+	// and RetryReason says why the latest one was a retry
+	Attempt     int    `json:"attempt,omitempty"`
+	MaxAttempts int    `json:"max_attempts,omitempty"`
+	RetryReason string `json:"retry_reason,omitempty"`
+
+	// Attention explains why a running agent looks stuck (stopped, or idle at a
+	// prompt per its Claude transcript), and BlockedSince when that began
+	Attention    string     `json:"attention,omitempty"`
+	BlockedSince *time.Time `json:"blocked_since,omitempty"`
+
+	LastHeartbeat *time.Time `json:"last_heartbeat,omitempty"`
+
+	// Readiness says whether the branch is ready for 'air integrate'
+	Readiness *readiness `json:"readiness"`
+}
+
+// channelStatus is a signaled coordination channel and who has consumed it
+type channelStatus struct {
+	Name      string                `json:"name"`
+	Agent     string                `json:"agent"`
+	SHA       string                `json:"sha"`
+	Timestamp time.Time             `json:"timestamp"`
+	Expired   bool                  `json:"expired"`
+	Consumers []string              `json:"consumers"`
+	Acks      map[string]ChannelAck `json:"acks,omitempty"`
+}
+
+// printStatus prints one snapshot of agent, channel, wait, and lock status
+func printStatus() error {
 	// Detect mode
 	info, err := detectMode()
 	if err != nil {
 		return fmt.Errorf("failed to detect mode: %w", err)
 	}
 
-	worktreesDir := getWorktreesDir()
-	channelsDir := getChannelsDir()
+	report, err := collectStatus(info)
+	if err != nil {
+		return err
+	}
+	if err := render(report, func() { printStatusText(report) }); err != nil {
+		return err
+	}
+
+	// The watching dashboard is what notifies about escalations, and what
+	// keeps idle agents up to date under --base-updates auto
+	if statusWatch {
+		for _, name := range launchQueued(info) {
+			fmt.Printf("\nLaunched queued plan %s\n", name)
+		}
+		fireEscalations(report.Escalations, readEscalationPolicy(), time.Now())
+		if report.BaseUpdates == baseUpdatesAuto {
+			agents, _ := runWorktrees(info)
+			for _, r := range autoSyncIdle(report, agents) {
+				if r.Outcome == "synced" {
+					fmt.Printf("\nAuto-synced %s with %s (%d new commits)\n", r.Plan, r.Base, r.Commits)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// collectStatus gathers the status of every agent worktree and the project's channels
+func collectStatus(info *WorkspaceInfo) (*statusReport, error) {
+	report := &statusReport{
+		Agents:   []agentStatus{},
+		Channels: []channelStatus{},
+		Timeouts: listWaitTimeouts(),
+		Locks:    listLocks(),
+	}
+	report.BaseUpdates = readBaseUpdatePolicy()
+	if info.Mode == ModeWorkspace {
+		report.Workspace = info.Name
+	}
 
 	// Collect done agents
 	doneAgents := make(map[string]bool)
-	doneDir := filepath.Join(channelsDir, "done")
+	doneDir := filepath.Join(getChannelsDir(), "done")
 	if doneEntries, err := os.ReadDir(doneDir); err == nil {
 		for _, de := range doneEntries {
 			if strings.HasSuffix(de.Name(), ".json") {
@@ -39,141 +213,481 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	summaries := readDoneSummaries()
+	failures := readFailures()
+
 	// Collect agents based on mode
-	type agentStatus struct {
-		name     string
-		repoName string // only in workspace mode
-		wtPath   string
+	agents, err := runWorktrees(info)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read worktrees: %w", err)
 	}
-	var agents []agentStatus
-
-	if info.Mode == ModeWorkspace {
-		// Workspace mode: worktrees/<repo>/<plan>/
-		repoEntries, err := os.ReadDir(worktreesDir)
-		if err != nil {
-			if os.IsNotExist(err) {
-				fmt.Println("No active agents. Run 'air run' to start.")
-				return nil
+	record, _ := readRunRecord()
+	if record != nil {
+		for _, a := range record.Agents {
+			if a.Done != nil {
+				doneAgents[a.Name] = true
 			}
-			return fmt.Errorf("failed to read worktrees: %w", err)
 		}
+	}
+	now := time.Now()
+	policy := report.BaseUpdates
+	plans := make(map[string]*PlanDependencies)
+	if deps, err := loadAllPlanDependencies(); err == nil {
+		for i := range deps {
+			plans[deps[i].Name] = &deps[i]
+		}
+	}
 
-		for _, repoEntry := range repoEntries {
-			if !repoEntry.IsDir() {
-				continue
-			}
-			repoName := repoEntry.Name()
-			repoWorktreeDir := filepath.Join(worktreesDir, repoName)
+	var active []worktreeInfo
+	for _, agent := range agents {
+		// Get last commit subject, time, and hash
+		logCmd := exec.Command("git", "-C", agent.wtPath, "log", "-1", "--format=%s%x00%ct%x00%H")
+		logOut, _ := logCmd.Output()
+		subject, rest, _ := strings.Cut(strings.TrimSpace(string(logOut)), "\x00")
+		commitTime, head, _ := strings.Cut(rest, "\x00")
 
-			planEntries, err := os.ReadDir(repoWorktreeDir)
-			if err != nil {
-				continue
-			}
-			for _, planEntry := range planEntries {
-				if !planEntry.IsDir() {
-					continue
+		// Get uncommitted changes count, without taking the index lock the
+		// agent's own git commands need (the dashboard polls this)
+		diffCmd := exec.Command("git", "--no-optional-locks", "-C", agent.wtPath, "status", "--porcelain")
+		var diffOut bytes.Buffer
+		diffCmd.Stdout = &diffOut
+		diffCmd.Run()
+		changes := 0
+		if diffOut.Len() > 0 {
+			changes = len(strings.Split(strings.TrimSpace(diffOut.String()), "\n"))
+		}
+
+		base := readAgentBase(agent.name)
+		status := agentStatus{
+			Name:        agent.name,
+			State:       "running",
+			LastCommit:  subject,
+			Uncommitted: changes,
+			Divergence:  formatDivergence(agent.wtPath, base),
+			NoCommits:   base != nil && base.SHA != "" && head == base.SHA,
+		}
+		if secs, err := strconv.ParseInt(commitTime, 10, 64); err == nil {
+			at := time.Unix(secs, 0).UTC()
+			status.LastCommitAt = &at
+		}
+		if record != nil {
+			if a := record.agent(agent.name); a != nil {
+				if a.Launched != nil {
+					status.Launched, status.Done = a.Launched, a.Done
+					end := now
+					if a.Done != nil {
+						end = *a.Done
+					}
+					status.Elapsed = end.Sub(*a.Launched).Seconds()
+				}
+				status.Stopped = a.Stopped
+				if last := a.lastAttempt(); last != nil {
+					status.Attempt, status.MaxAttempts, status.RetryReason = last.Number, record.Flags.MaxAttempts, last.Reason
 				}
-				agents = append(agents, agentStatus{
-					name:     planEntry.Name(),
-					repoName: repoName,
-					wtPath:   filepath.Join(repoWorktreeDir, planEntry.Name()),
-				})
 			}
 		}
-	} else {
-		// Single mode: worktrees/<plan>/
-		entries, err := os.ReadDir(worktreesDir)
-		if err != nil {
-			if os.IsNotExist(err) {
-				fmt.Println("No active agents. Run 'air run <plans>' to start.")
-				return nil
+		if info.Mode == ModeWorkspace {
+			status.Repo = agent.repoName
+		}
+		var done *ChannelPayload
+		if doneAgents[agent.name] {
+			status.State = "done"
+			status.Summary = summaries[agent.name]
+			if done, _ = readChannel("done/" + agent.name); done == nil {
+				done = &ChannelPayload{}
 			}
-			return fmt.Errorf("failed to read worktrees: %w", err)
+		} else if f, ok := failures[agent.name]; ok {
+			status.State = "failed"
+			status.Failure = f.Message
+		} else if _, err := os.Stat(agent.wtPath); os.IsNotExist(err) {
+			// Registered in run.json, but removed behind air's back
+			status.State = "missing"
+			status.Attention = "worktree " + agent.wtPath + " is gone; 'air clean " + agent.name + "' forgets the agent"
+		} else if status.Stopped != nil {
+			// Stopped on purpose, so it doesn't need attention
+			status.State = "stopped"
+		} else if alive, known := agentAlive(agent.name, now); known && !alive {
+			lastBeat := readHeartbeat(agent.name).Time
+			status.State = "stopped"
+			status.Attention = "Claude is no longer running (no heartbeat since " + formatDuration(now.Sub(lastBeat)) + " ago)"
+			status.BlockedSince = &lastBeat
+		} else if reason, since := idleReason(agent.wtPath, statusIdleAfter, now); reason != "" {
+			status.Attention = reason
+			status.BlockedSince = &since
 		}
+		if hb := readHeartbeat(agent.name); hb != nil {
+			status.LastHeartbeat = &hb.Time
+		}
+		if policy != baseUpdatesOff && base != nil && base.Branch != "" && base.Branch != "HEAD" && (status.State == "running" || status.State == "stopped") {
+			if _, behind, ok := aheadBehind(agent.wtPath, base.Branch); ok {
+				status.BaseUpdates = behind
+			}
+		}
+		status.Readiness = assessReadiness(agent, plans[agent.name], done)
+		status.Stale = staleness(agent.wtPath, base, status.State == "done", statusStaleCommits, statusStaleDays, now)
+		report.Agents = append(report.Agents, status)
+		if status.State == "running" || status.State == "stopped" {
+			active = append(active, agent)
+		}
+	}
+	report.Overlaps = findOverlaps(active)
 
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				continue
+	// Coordination channels (exclude done markers)
+	if channels, err := collectChannelStatus(doneAgents); err == nil {
+		report.Channels = channels
+	}
+
+	if report.Timeouts == nil {
+		report.Timeouts = []WaitTimeout{}
+	}
+	if report.Locks == nil {
+		report.Locks = []LockInfo{}
+	}
+
+	report.RateLimit = launchBackoff()
+
+	report.Queue = []queuedStatus{}
+	if record, _ := readRunRecord(); record != nil {
+		for _, q := range record.Queue {
+			qs := queuedStatus{Plan: q.Name, Waiting: []string{}, Error: q.Error}
+			if pd := plans[q.Name]; pd != nil {
+				qs.Waiting = append(qs.Waiting, unmetWaits(*pd, now)...)
 			}
-			agents = append(agents, agentStatus{
-				name:   entry.Name(),
-				wtPath: filepath.Join(worktreesDir, entry.Name()),
-			})
+			report.Queue = append(report.Queue, qs)
 		}
 	}
 
-	if len(agents) == 0 {
+	report.Escalations = []Escalation{}
+	for _, e := range findEscalations(report, readEscalationPolicy().after, now) {
+		if fired := readEscalation(e.Agent); fired != nil {
+			e.Notified, e.Paused = fired.Notified, fired.Paused
+		}
+		report.Escalations = append(report.Escalations, e)
+	}
+	return report, nil
+}
+
+// idleReason describes why an agent needs attention, from its transcript: its
+// turn ended without 'air agent done', or a tool call has gone unanswered (most
+// often a permission prompt). Returns "" while it looks busy or for idleAfter.
+func idleReason(wtPath string, idleAfter time.Duration, now time.Time) (string, time.Time) {
+	state, since, tool := transcriptActivity(wtPath)
+	if since.IsZero() || now.Sub(since) < idleAfter {
+		return "", time.Time{}
+	}
+	idle := formatDuration(now.Sub(since))
+	switch state {
+	case activityInput:
+		return fmt.Sprintf("waiting for input for %s", idle), since
+	case activityToolPending:
+		return fmt.Sprintf("%s call unanswered for %s (permission prompt?)", tool, idle), since
+	}
+	return "", time.Time{}
+}
+
+// printStatusText prints a status report for humans
+func printStatusText(report *statusReport) {
+	if len(report.Agents) == 0 {
 		fmt.Println("No active agents. Run 'air run' to start.")
-		return nil
+		return
+	}
+
+	// Escalations first, where they can't be missed
+	if len(report.Escalations) > 0 {
+		bar := strings.Repeat("!", 72)
+		fmt.Println(bar)
+		for _, e := range report.Escalations {
+			fmt.Printf("!! ESCALATED: %s blocked for %s: %s\n", e.Agent, formatDuration(time.Since(e.Since)), e.Reason)
+			if len(e.Paused) > 0 {
+				fmt.Printf("!!   paused downstream: %s\n", strings.Join(e.Paused, ", "))
+			}
+		}
+		fmt.Println(bar)
+		fmt.Println()
+	}
+
+	if report.RateLimit != nil {
+		fmt.Printf("Rate limited: %s\n\n", formatBackoff(report.RateLimit))
 	}
 
 	// Print header
-	if info.Mode == ModeWorkspace {
-		fmt.Printf("Workspace: %s\n\n", info.Name)
+	if report.Workspace != "" {
+		fmt.Printf("Workspace: %s\n\n", report.Workspace)
 	}
 	fmt.Println("Agents")
 	fmt.Println()
 
-	for _, agent := range agents {
-		// Get last commit
-		logCmd := exec.Command("git", "-C", agent.wtPath, "log", "-1", "--format=%s (%ar)")
-		logOut, _ := logCmd.Output()
-		lastCommit := strings.TrimSpace(string(logOut))
+	for _, agent := range report.Agents {
+		statusIcon := "●"
+		switch agent.State {
+		case "done":
+			statusIcon = "✓"
+		case "stopped", "failed", "missing":
+			statusIcon = "✗"
+		}
 
-		// Get uncommitted changes count
-		diffCmd := exec.Command("git", "-C", agent.wtPath, "status", "--porcelain")
-		var diffOut bytes.Buffer
-		diffCmd.Stdout = &diffOut
-		diffCmd.Run()
-		changes := 0
-		if diffOut.Len() > 0 {
-			changes = len(strings.Split(strings.TrimSpace(diffOut.String()), "\n"))
+		// Build info line
+		agentLabel := agent.Name
+		if agent.Repo != "" {
+			agentLabel = fmt.Sprintf("%s [%s]", agent.Name, agent.Repo)
 		}
 
-		// Determine status
-		isDone := doneAgents[agent.name]
+		var infoLine string
+		switch {
+		case agent.NoCommits:
+			infoLine = "no commits yet"
+		case agent.LastCommitAt != nil:
+			infoLine = fmt.Sprintf("%s (%s ago)", agent.LastCommit, formatDuration(time.Since(*agent.LastCommitAt)))
+		default:
+			infoLine = agent.LastCommit
+		}
+		if agent.Uncommitted > 0 {
+			infoLine += fmt.Sprintf(", %d uncommitted", agent.Uncommitted)
+		}
+		if agent.Divergence != "" {
+			infoLine += ", " + agent.Divergence
+		}
 
-		var statusIcon, statusText string
-		if isDone {
-			statusIcon = "✓"
-			statusText = "done"
-		} else {
-			statusIcon = "●"
-			statusText = "running"
+		elapsed := ""
+		if agent.Launched != nil {
+			elapsed = formatDuration(secondsToDuration(agent.Elapsed))
+			if agent.State == "done" {
+				elapsed = "in " + elapsed
+			}
+		}
+		if attempt := formatAttempt(agent.Attempt, agent.MaxAttempts); attempt != "" {
+			elapsed = strings.TrimSpace(elapsed + "  " + attempt)
+		}
+		fmt.Println(strings.TrimRight(fmt.Sprintf("  %s %-24s %-8s %s", statusIcon, agentLabel, agent.State, elapsed), " "))
+		fmt.Printf("    %s\n", infoLine)
+		if agent.Stale != "" {
+			fmt.Printf("    ⏳ stale: %s; integrate or sync it before it drifts further\n", agent.Stale)
+		} else if agent.BaseUpdates > 0 {
+			fmt.Printf("    ↓ %s\n", formatBaseUpdates(agent, report.BaseUpdates))
+		}
+		if agent.RetryReason != "" {
+			fmt.Printf("    ↻ retried: %s\n", agent.RetryReason)
+		}
+		if agent.Failure != "" {
+			fmt.Printf("    ✗ gave up: %s\n", agent.Failure)
+		}
+		if agent.State == "stopped" && agent.Stopped != nil {
+			fmt.Printf("    ■ stopped with 'air stop' %s ago\n", formatDuration(time.Since(*agent.Stopped)))
+		}
+		if agent.Attention != "" {
+			fmt.Printf("    ⚠ needs attention: %s\n", agent.Attention)
+		}
+		if agent.Summary != "" {
+			for _, line := range strings.Split(agent.Summary, "\n") {
+				fmt.Printf("    │ %s\n", line)
+			}
 		}
+		if agent.State == "done" && agent.Readiness != nil {
+			fmt.Printf("    %s\n", formatReadiness(agent.Readiness))
+		}
+	}
 
-		// Build info line
-		agentLabel := agent.name
-		if info.Mode == ModeWorkspace && agent.repoName != "" {
-			agentLabel = fmt.Sprintf("%s [%s]", agent.name, agent.repoName)
+	if len(report.Queue) > 0 {
+		fmt.Println()
+		fmt.Println("Queued")
+		fmt.Println()
+		for _, q := range report.Queue {
+			switch {
+			case q.Error != "":
+				fmt.Printf("  ✗ %-16s failed to launch: %s\n", q.Plan, q.Error)
+			case len(q.Waiting) > 0:
+				fmt.Printf("  ○ %-16s waiting on %s\n", q.Plan, strings.Join(q.Waiting, ", "))
+			default:
+				fmt.Printf("  ○ %-16s waiting for an agent slot\n", q.Plan)
+			}
 		}
+	}
 
-		infoLine := lastCommit
-		if changes > 0 {
-			infoLine += fmt.Sprintf(", %d uncommitted", changes)
+	// Show coordination channels
+	if len(report.Channels) > 0 {
+		fmt.Println()
+		fmt.Println("Channels")
+		fmt.Println()
+		for _, ch := range report.Channels {
+			if ch.Expired {
+				fmt.Printf("  ✗ %-16s expired (signaled by %s at %s)\n", ch.Name, ch.Agent, ch.Timestamp.Local().Format(time.RFC822))
+				continue
+			}
+			fmt.Printf("  ✓ %-16s signaled by %s (%s)%s\n", ch.Name, ch.Agent, shortRef(ch.SHA), formatConsumerAcks(ch.Consumers, ch.Acks))
 		}
+	}
 
-		fmt.Printf("  %s %-24s %s\n", statusIcon, agentLabel, statusText)
-		fmt.Printf("    %s\n", infoLine)
+	// Warn about files two agents at work are both editing
+	if len(report.Overlaps) > 0 {
+		fmt.Println()
+		fmt.Println("Overlapping edits")
+		fmt.Println()
+		for _, o := range report.Overlaps {
+			fmt.Printf("  ⚠ %s\n", formatOverlap(o))
+		}
 	}
 
-	// Show coordination channels (exclude done markers)
-	if err := showChannelStatus(doneAgents); err != nil {
-		return nil
+	// Show bounded waits that ran out
+	if len(report.Timeouts) > 0 {
+		fmt.Println()
+		fmt.Println("Timed-out waits")
+		fmt.Println()
+		for _, t := range report.Timeouts {
+			outcome := "failed"
+			switch t.Fallback {
+			case "proceed":
+				outcome = "proceeded without it"
+			case "ask":
+				outcome = "needs a human (signal the channel to unblock)"
+			}
+			fmt.Printf("  ⚠ %-16s %s waited %s, %s\n", t.Channel, t.Agent, t.Timeout, outcome)
+		}
 	}
 
-	return nil
+	// Show held locks
+	if len(report.Locks) > 0 {
+		fmt.Println()
+		fmt.Println("Locks")
+		fmt.Println()
+		for _, lock := range report.Locks {
+			fmt.Printf("  ● %-16s held by %s (since %s)\n", lock.Resource, lock.Agent, lock.Timestamp.Local().Format(time.Kitchen))
+		}
+	}
+}
+
+// tmuxStateSymbols renders each agentBarState in tmux status-line markup
+var tmuxStateSymbols = map[string]string{
+	"done":    "#[fg=green]✓#[default]",
+	"failed":  "#[fg=red]✗#[default]",
+	"blocked": "#[fg=red]✗#[default]",
+	"ask":     "#[fg=yellow]?#[default]",
+	"waiting": "#[fg=yellow]…#[default]",
+	"running": "#[fg=cyan]●#[default]",
 }
 
-func showChannelStatus(doneAgents map[string]bool) error {
+// agentBarState derives an agent's state from channel files: done once it has
+// a done marker, failed once it has a failure marker, blocked or ask if a bounded wait ran out with fallback fail or
+// ask (or its transcript shows it idle at a prompt), blocked too if its
+// heartbeats stopped, waiting while a channel its plan waits on is unsignaled,
+// else running
+func agentBarState(agent string) string {
+	if _, err := os.Stat(filepath.Join(getChannelsDir(), "done", agent+".json")); err == nil {
+		return "done"
+	}
+	if readFailure(agent) != nil {
+		return "failed"
+	}
+	if alive, known := agentAlive(agent, time.Now()); known && !alive {
+		return "blocked"
+	}
+	if record, _ := readRunRecord(); record != nil {
+		if a := record.agent(agent); a != nil {
+			if reason, _ := idleReason(a.Worktree, statusIdleAfter, time.Now()); reason != "" {
+				return "ask"
+			}
+		}
+	}
+	for _, t := range listWaitTimeouts() {
+		if t.Agent != agent {
+			continue
+		}
+		switch t.Fallback {
+		case "fail":
+			return "blocked"
+		case "ask":
+			return "ask"
+		}
+	}
+	plans, _ := loadAllPlanDependencies()
+	for _, p := range plans {
+		if p.Name != agent {
+			continue
+		}
+		for _, ch := range p.WaitsOn {
+			if !channelExists(ch) {
+				return "waiting"
+			}
+		}
+	}
+	return "running"
+}
+
+// windowStatePrefixes and windowStateStyles mark agent windows by agentBarState
+var windowStatePrefixes = map[string]string{
+	"done":    "✓",
+	"failed":  "✗",
+	"blocked": "⚠",
+	"ask":     "⚠",
+	"waiting": "…",
+	"running": "",
+}
+
+var windowStateStyles = map[string]string{
+	"done":    "fg=green",
+	"failed":  "fg=red",
+	"blocked": "fg=red",
+	"ask":     "fg=yellow",
+	"waiting": "dim",
+	"running": "default",
+}
+
+// renameAgentWindows names each agent window in session after its agent's
+// state and colors it to match. Windows are matched by their @air-agent tag.
+func renameAgentWindows(session string) {
+	out, err := exec.Command("tmux", "list-windows", "-t", "="+session, "-F", "#{window_id}\t#{@air-agent}\t#{window_name}").Output()
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 || fields[1] == "" {
+			continue
+		}
+		id, agent, current := fields[0], fields[1], fields[2]
+		state := agentBarState(agent)
+		if name := windowStatePrefixes[state] + agent; name != current {
+			exec.Command("tmux", "rename-window", "-t", id, name).Run()
+			exec.Command("tmux", "set-option", "-w", "-t", id, "window-status-style", windowStateStyles[state]).Run()
+		}
+	}
+}
+
+// formatDivergence describes how far a worktree's branch has moved from its
+// base branch, e.g. "3 ahead, 1 behind main". Returns "" if unknown.
+func formatDivergence(wtPath string, base *agentBase) string {
+	if base == nil || base.Branch == "" || base.Branch == "HEAD" {
+		return ""
+	}
+	ahead, behind, ok := aheadBehind(wtPath, base.Branch)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%d ahead, %d behind %s", ahead, behind, base.Branch)
+}
+
+// aheadBehind counts commits on HEAD not in baseRef (ahead) and vice versa (behind)
+func aheadBehind(wtPath, baseRef string) (ahead, behind int, ok bool) {
+	out, err := exec.Command("git", "-C", wtPath, "rev-list", "--left-right", "--count", baseRef+"...HEAD").Output()
+	if err != nil {
+		return 0, 0, false
+	}
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(out)), "%d %d", &behind, &ahead); err != nil {
+		return 0, 0, false
+	}
+	return ahead, behind, true
+}
+
+// collectChannelStatus reads signaled coordination channels, skipping done
+// markers, with each channel's consumers (the plans that wait on it)
+func collectChannelStatus(doneAgents map[string]bool) ([]channelStatus, error) {
 	channelsDir := getChannelsDir()
 
 	entries, err := os.ReadDir(channelsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil
+			return nil, nil
 		}
-		return err
+		return nil, err
 	}
 
 	// Collect coordination channels (exclude done markers and agent-named files)
@@ -193,13 +707,19 @@ func showChannelStatus(doneAgents map[string]bool) error {
 	}
 
 	if len(channels) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	fmt.Println()
-	fmt.Println("Channels")
-	fmt.Println()
+	// Consumers of each channel are the plans that wait on it
+	consumers := make(map[string][]string)
+	plans, _ := loadAllPlanDependencies()
+	for _, p := range plans {
+		for _, ch := range p.WaitsOn {
+			consumers[ch] = append(consumers[ch], p.Name)
+		}
+	}
 
+	var statuses []channelStatus
 	for _, ch := range channels {
 		channelPath := filepath.Join(channelsDir, ch+".json")
 		data, err := os.ReadFile(channelPath)
@@ -212,13 +732,43 @@ func showChannelStatus(doneAgents map[string]bool) error {
 			continue
 		}
 
-		shortSHA := payload.SHA
-		if len(shortSHA) > 8 {
-			shortSHA = shortSHA[:8]
+		chConsumers := consumers[ch]
+		if chConsumers == nil {
+			chConsumers = []string{}
 		}
-
-		fmt.Printf("  ✓ %-16s signaled by %s (%s)\n", ch, payload.Agent, shortSHA)
+		statuses = append(statuses, channelStatus{
+			Name:      ch,
+			Agent:     payload.Agent,
+			SHA:       payload.SHA,
+			Timestamp: payload.Timestamp,
+			Expired:   payload.expired(time.Now()),
+			Consumers: chConsumers,
+			Acks:      readChannelAcks(ch, &payload),
+		})
 	}
 
-	return nil
+	return statuses, nil
+}
+
+// formatConsumerAcks summarizes how many of a channel's consumers have picked up
+// the current signal, e.g. ", 2/3 consumers merged"
+func formatConsumerAcks(consumers []string, acks map[string]ChannelAck) string {
+	if len(consumers) == 0 {
+		return ""
+	}
+	merged, received := 0, 0
+	for _, c := range consumers {
+		if ack, ok := acks[c]; ok {
+			if ack.Merged {
+				merged++
+			} else {
+				received++
+			}
+		}
+	}
+	summary := fmt.Sprintf(", %d/%d consumers merged", merged, len(consumers))
+	if received > 0 {
+		summary += fmt.Sprintf(" (%d received)", received)
+	}
+	return summary
 }
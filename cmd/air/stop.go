@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var stopCmd = &cobra.Command{
+	Use:   "stop [agents...]",
+	Short: "Ask agents to wrap up and exit, keeping their worktrees",
+	Long: `Ends the Claude session of each named agent (every running agent of the run
+if none are named): it's interrupted and asked to /exit in its tmux window, or
+sent SIGINT if it has none. Agents still running after --timeout are killed.
+
+Worktrees, branches, and the tmux session are left as they are, and each stop
+is recorded in run.json and the event log, with the tokens used so far. A
+stopped agent doesn't keep the run active or escalate; relaunch it with
+'air add <plan>', or remove it with 'air clean'.`,
+	RunE: runStop,
+}
+
+var stopTimeout time.Duration
+
+func init() {
+	stopCmd.Flags().DurationVar(&stopTimeout, "timeout", 30*time.Second, "How long to wait for agents to exit before killing them")
+}
+
+// stopping is an agent 'air stop' is waiting on
+type stopping struct {
+	agent  RunAgent
+	pid    int    // its Claude process, from its heartbeat (0 if unknown)
+	window string // its tmux window ("" if none)
+	killed bool
+}
+
+func runStop(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	record, err := readRunRecord()
+	if err != nil {
+		return fmt.Errorf("failed to read run.json: %w", err)
+	}
+	if record == nil {
+		fmt.Println("No agents to stop. Run 'air run' to start.")
+		return nil
+	}
+
+	var agents []RunAgent
+	if len(args) > 0 {
+		for _, name := range args {
+			a := record.agent(name)
+			if a == nil {
+				return fmt.Errorf("%s isn't part of the run (see 'air status')", name)
+			}
+			agents = append(agents, *a)
+		}
+	} else {
+		agents = record.Agents
+	}
+
+	// Ask every agent at once, so stopping N agents takes one timeout, not N
+	now := time.Now()
+	session := runSession()
+	var pending []*stopping
+	for _, a := range agents {
+		hb := readHeartbeat(a.Name)
+		if alive, known := heartbeatAlive(hb, now); !known || !alive {
+			if len(args) > 0 {
+				fmt.Printf("%s isn't running\n", a.Name)
+			}
+			continue
+		}
+		s := &stopping{agent: a, pid: hb.PID, window: findAgentWindow(session, a.Name)}
+		askToExit(s)
+		pending = append(pending, s)
+	}
+	if len(pending) == 0 {
+		if len(args) == 0 {
+			fmt.Println("No agents are running.")
+		}
+		return nil
+	}
+
+	fmt.Printf("Waiting up to %s for %d agent(s) to exit...\n", stopTimeout, len(pending))
+	deadline := time.Now().Add(stopTimeout)
+	for time.Now().Before(deadline) && anyRunning(pending) {
+		time.Sleep(200 * time.Millisecond)
+	}
+	for _, s := range pending {
+		if s.pid > 0 && processExists(s.pid) {
+			killAgent(s.pid)
+			s.killed = true
+		}
+	}
+
+	// Only agents known to have exited (or been killed) are recorded as stopped
+	stopped := time.Now().UTC()
+	var names []string
+	var gone []*stopping
+	for _, s := range pending {
+		if exited, _ := s.exited(); exited || s.killed {
+			names = append(names, s.agent.Name)
+			gone = append(gone, s)
+			continue
+		}
+		if s.window != "" {
+			fmt.Printf("✗ %s didn't exit within %s; see its window in tmux session '%s'\n", s.agent.Name, stopTimeout, session)
+		} else {
+			fmt.Printf("✗ %s was asked to exit, but without its PID or a tmux window there's no telling whether it did\n", s.agent.Name)
+		}
+	}
+	if len(names) > 0 {
+		if err := markRunAgents(names, agentStopped, "", stopped); err != nil {
+			fmt.Printf("Warning: failed to update run.json: %v\n", err)
+		}
+	}
+	for _, s := range gone {
+		e := Event{Kind: eventStopped, Agent: s.agent.Name, Repo: s.agent.Repo}
+		if s.agent.Worktree != "" {
+			usage := transcriptUsage(s.agent.Worktree)
+			e.Tokens = &usage
+		}
+		if s.killed {
+			e.Reason = fmt.Sprintf("killed after %s", stopTimeout)
+			fmt.Printf("✗ %s didn't exit within %s; killed\n", s.agent.Name, stopTimeout)
+		} else {
+			fmt.Printf("✓ %s stopped\n", s.agent.Name)
+		}
+		recordEvent(e)
+	}
+	fmt.Println("\nWorktrees kept. Relaunch with 'air add <plan>', or remove with 'air clean'.")
+	return nil
+}
+
+// askToExit interrupts an agent's Claude and asks it to exit: through its
+// tmux window when it has one, so Claude sees what a person would type, or
+// with SIGINT otherwise
+func askToExit(s *stopping) {
+	if s.window != "" {
+		exec.Command("tmux", "send-keys", "-t", s.window, "Escape").Run()
+		exec.Command("tmux", "send-keys", "-t", s.window, "/exit", "Enter").Run()
+		return
+	}
+	if p, err := os.FindProcess(s.pid); err == nil && s.pid > 0 {
+		p.Signal(os.Interrupt)
+	}
+}
+
+// exited reports whether the agent's Claude is known to have exited: its
+// process is gone, or, without a PID, its window's pane is dead or back at
+// the shell launch.sh was started from. ok is false if there's neither to watch.
+func (s *stopping) exited() (exited, ok bool) {
+	if s.pid > 0 {
+		return !processExists(s.pid), true
+	}
+	if s.window == "" {
+		return false, false
+	}
+	out, err := exec.Command("tmux", "display-message", "-p", "-t", s.window, "#{pane_dead}\t#{pane_current_command}").Output()
+	if err != nil {
+		// The window is gone, and Claude with it
+		return true, true
+	}
+	dead, command, _ := strings.Cut(strings.TrimSpace(string(out)), "\t")
+	if dead == "1" {
+		return true, true
+	}
+	shell, _ := exec.Command("tmux", "show-options", "-gv", "default-shell").Output()
+	return command == filepath.Base(strings.TrimSpace(string(shell))), true
+}
+
+// killAgent ends an agent's Claude that didn't exit when asked, with SIGTERM
+// where there is one
+func killAgent(pid int) {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return
+	}
+	if p.Signal(syscall.SIGTERM) != nil {
+		p.Kill()
+	}
+}
+
+// anyRunning reports whether any of the agents may still be running, among
+// those whose exit can be watched for
+func anyRunning(agents []*stopping) bool {
+	for _, s := range agents {
+		if exited, ok := s.exited(); ok && !exited {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [plans...]",
+	Short: "Bring agent branches up to date with their base branch",
+	Long: `Merges the latest commit of each agent's base branch (main, or the repo's
+configured base) into its worktree, so agents build on what landed while they
+were working. Without arguments, syncs every agent that isn't done or failed.
+
+Set the strategy with --strategy, or per repository with
+'git config air.sync rebase'. The default is merge, which never rewrites
+commits an agent may have already shared through a channel.
+
+Worktrees with uncommitted changes are skipped, since the agent is mid-edit.
+A sync that conflicts is aborted, leaving the branch as it was. Each synced
+agent gets an inbox message saying what changed under it, and its recorded
+base moves to the synced commit, so diffs and 'air agent done' only count its
+own work.`,
+	RunE: runSync,
+}
+
+var syncStrategy string
+
+func init() {
+	syncCmd.Flags().StringVar(&syncStrategy, "strategy", "", "merge or rebase (default: git config air.sync, else merge)")
+	supportsOutput(syncCmd)
+}
+
+// syncResult is the outcome of syncing one agent's branch
+type syncResult struct {
+	Plan     string   `json:"plan"`
+	Repo     string   `json:"repo,omitempty"` // workspace mode
+	Base     string   `json:"base,omitempty"`
+	Strategy string   `json:"strategy,omitempty"`
+	Outcome  string   `json:"outcome"`           // synced, up-to-date, skipped, or conflict
+	Commits  int      `json:"commits,omitempty"` // base commits brought in
+	Reason   string   `json:"reason,omitempty"`  // why it was skipped
+	Files    []string `json:"files,omitempty"`   // conflicting files
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	if !isInitialized() {
+		return errNotInitialized()
+	}
+	if syncStrategy != "" && syncStrategy != "merge" && syncStrategy != "rebase" {
+		return withCode(codeUsage, fmt.Errorf("--strategy must be merge or rebase, got %q", syncStrategy))
+	}
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+	agents, err := runWorktrees(info)
+	if err != nil {
+		return fmt.Errorf("failed to read worktrees: %w", err)
+	}
+
+	var targets []worktreeInfo
+	for _, name := range args {
+		found := false
+		for _, agent := range agents {
+			if agent.name == name {
+				targets = append(targets, agent)
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("no worktree for plan '%s' (run 'air run %s' first)", name, name)
+		}
+	}
+	if len(args) == 0 {
+		for _, agent := range agents {
+			if !channelExists("done/"+agent.name) && readFailure(agent.name) == nil {
+				targets = append(targets, agent)
+			}
+		}
+	}
+
+	results := []syncResult{}
+	conflicts := 0
+	for _, agent := range targets {
+		r := syncAgent(agent)
+		if info.Mode == ModeWorkspace {
+			r.Repo = agent.repoName
+		}
+		if r.Outcome == "conflict" {
+			conflicts++
+		}
+		results = append(results, r)
+	}
+	if err := render(results, func() { printSyncResults(results) }); err != nil {
+		return err
+	}
+	if conflicts > 0 {
+		return withCode(codeMergeConflict, fmt.Errorf("%d agent branches conflict with their base; resolve in their worktrees, or ask the agents to", conflicts))
+	}
+	return nil
+}
+
+// syncStrategyFor returns how to sync branches in repoPath: --strategy, else
+// the repo's 'git config air.sync', else merge
+func syncStrategyFor(repoPath string) string {
+	if syncStrategy != "" {
+		return syncStrategy
+	}
+	out, _ := exec.Command("git", "-C", repoPath, "config", "--get", "air.sync").Output()
+	if strings.TrimSpace(string(out)) == "rebase" {
+		return "rebase"
+	}
+	return "merge"
+}
+
+// syncAgent merges or rebases agent's worktree onto the tip of its base
+// branch, aborting on conflict, and advances its recorded base
+func syncAgent(agent worktreeInfo) syncResult {
+	r := syncResult{Plan: agent.name}
+	base := readAgentBase(agent.name)
+	if base == nil || base.Branch == "" || base.Branch == "HEAD" {
+		r.Outcome, r.Reason = "skipped", "no base branch recorded"
+		return r
+	}
+	r.Base = base.Branch
+
+	git := func(args ...string) *exec.Cmd {
+		return exec.Command("git", append([]string{"-C", agent.wtPath}, args...)...)
+	}
+	tipOut, err := git("rev-parse", "--verify", "--quiet", base.Branch).Output()
+	if err != nil {
+		r.Outcome, r.Reason = "skipped", fmt.Sprintf("base branch %s not found", base.Branch)
+		return r
+	}
+	tip := strings.TrimSpace(string(tipOut))
+	if git("merge-base", "--is-ancestor", tip, "HEAD").Run() == nil {
+		r.Outcome = "up-to-date"
+		return r
+	}
+	if out, _ := git("status", "--porcelain", "--untracked-files=no").Output(); len(strings.TrimSpace(string(out))) > 0 {
+		r.Outcome, r.Reason = "skipped", "uncommitted changes (agent is mid-edit; rerun later)"
+		return r
+	}
+	if count, err := git("rev-list", "--count", "HEAD.."+tip).Output(); err == nil {
+		fmt.Sscanf(strings.TrimSpace(string(count)), "%d", &r.Commits)
+	}
+
+	r.Strategy = syncStrategyFor(agent.repoPath)
+	var syncErr error
+	if r.Strategy == "rebase" {
+		syncErr = git("rebase", "--quiet", tip).Run()
+	} else {
+		syncErr = git("merge", "--no-edit", "-m", fmt.Sprintf("Merge %s into air/%s", base.Branch, agent.name), tip).Run()
+	}
+	if syncErr != nil {
+		r.Outcome, r.Files = "conflict", conflictedFiles(agent.wtPath)
+		git(r.Strategy, "--abort").Run()
+		recordEvent(Event{Kind: eventConflict, Agent: agent.name, Repo: agent.repoName, With: []string{base.Branch}, Files: r.Files})
+		return r
+	}
+
+	r.Outcome = "synced"
+	writeAgentBase(getAgentDir(agent.name), agentBase{Branch: base.Branch, SHA: tip})
+	how := fmt.Sprintf("merged %d new commits from %s into your branch", r.Commits, base.Branch)
+	if r.Strategy == "rebase" {
+		how = fmt.Sprintf("rebased your branch onto %s, which has %d new commits (your commit hashes changed)", base.Branch, r.Commits)
+	}
+	enqueueChannel(inboxChannel(agent.name), &ChannelPayload{
+		Agent:     "user",
+		Repo:      agent.repoName,
+		Timestamp: time.Now().UTC(),
+		Message:   fmt.Sprintf("'air sync' %s; %s is now your base. Check 'git log' for changes that affect your work.", how, shortRef(tip)),
+	})
+	return r
+}
+
+// printSyncResults prints one line per agent and a summary
+func printSyncResults(results []syncResult) {
+	if len(results) == 0 {
+		fmt.Println("No agents at work to sync.")
+		return
+	}
+	synced := 0
+	for _, r := range results {
+		name := r.Plan
+		if r.Repo != "" {
+			name = filepath.Join(r.Repo, r.Plan)
+		}
+		switch r.Outcome {
+		case "synced":
+			synced++
+			verb := "merged"
+			if r.Strategy == "rebase" {
+				verb = "rebased onto"
+			}
+			fmt.Printf("  ✓ %s: %s %s (%d new commits)\n", name, verb, r.Base, r.Commits)
+		case "up-to-date":
+			fmt.Printf("  · %s: already up to date with %s\n", name, r.Base)
+		case "conflict":
+			fmt.Printf("  ✗ %s: conflicts with %s in %s; left as it was\n", name, r.Base, strings.Join(r.Files, ", "))
+		default:
+			fmt.Printf("  - %s: skipped, %s\n", name, r.Reason)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("Synced %d of %d agents.\n", synced, len(results))
+}
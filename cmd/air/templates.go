@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// templatesFile records, in ~/.air/<project>/, the embedded templates the
+// project's context.md was last generated or updated from
+const templatesFile = "templates.json"
+
+// templateRecord is the contents of templates.json
+type templateRecord struct {
+	AirVersion string            `json:"air_version"`
+	Recorded   time.Time         `json:"recorded"`
+	Templates  map[string]string `json:"templates"` // name -> templateVersion
+}
+
+// projectTemplate is one embedded template that goes into context.md
+type projectTemplate struct {
+	name    string
+	content string
+}
+
+// projectTemplates returns the templates the project's context.md is made
+// of: the agent context for the mode, then a section per stack
+func projectTemplates(info *WorkspaceInfo) []projectTemplate {
+	templates := []projectTemplate{{"agent context", contextTemplate(info)}}
+	for _, stack := range projectStacks(info) {
+		templates = append(templates, projectTemplate{stack + " conventions", stackTemplates[stack].context})
+	}
+	return templates
+}
+
+// projectContextTemplate joins the project's templates into the context.md
+// 'air init' generates. Its version is the one stamped on context.md, so a
+// newer agent context or stack section in this binary makes it stale.
+func projectContextTemplate(info *WorkspaceInfo) string {
+	templates := projectTemplates(info)
+	if len(templates) == 1 {
+		return templates[0].content
+	}
+	var stacks []string
+	for _, t := range templates[1:] {
+		stacks = append(stacks, strings.TrimSpace(t.content))
+	}
+	return strings.TrimRight(templates[0].content, "\n") + "\n\n" + strings.Join(stacks, "\n\n") + "\n"
+}
+
+// getTemplatesPath returns ~/.air/<project>/templates.json
+func getTemplatesPath() string {
+	return filepath.Join(mustGetAirDir(), templatesFile)
+}
+
+// writeTemplateRecord records the versions of the project's templates in
+// this binary, and the binary's version
+func writeTemplateRecord(info *WorkspaceInfo) error {
+	record := templateRecord{AirVersion: version, Recorded: time.Now().UTC(), Templates: make(map[string]string)}
+	for _, t := range projectTemplates(info) {
+		record.Templates[t.name] = templateVersion(t.content)
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getTemplatesPath(), data, 0644)
+}
+
+// readTemplateRecord returns the project's templates.json, or nil for a
+// project initialized before it was kept
+func readTemplateRecord() *templateRecord {
+	data, err := os.ReadFile(getTemplatesPath())
+	if err != nil {
+		return nil
+	}
+	var record templateRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil
+	}
+	return &record
+}
+
+// changedTemplates lists the project's templates that this binary has newer
+// versions of than record, or that are new or gone since
+func changedTemplates(info *WorkspaceInfo, record *templateRecord) []string {
+	var changed []string
+	current := make(map[string]bool)
+	for _, t := range projectTemplates(info) {
+		current[t.name] = true
+		if v, ok := record.Templates[t.name]; !ok {
+			changed = append(changed, t.name+" (new)")
+		} else if v != templateVersion(t.content) {
+			changed = append(changed, t.name)
+		}
+	}
+	var gone []string
+	for name := range record.Templates {
+		if !current[name] {
+			gone = append(gone, name+" (no longer applies)")
+		}
+	}
+	sort.Strings(gone)
+	return append(changed, gone...)
+}
+
+// describeStaleTemplates explains why context.md is behind this binary's
+// templates, and how to catch up
+func describeStaleTemplates(info *WorkspaceInfo) string {
+	record := readTemplateRecord()
+	if record == nil {
+		return "context.md predates the current template (run 'air init --update-context')"
+	}
+	changed := changedTemplates(info, record)
+	if len(changed) == 0 {
+		changed = []string{"agent context"}
+	}
+	return fmt.Sprintf("air %s ships newer templates than this project's context.md came from (air %s, %s): %s; run 'air init --update-context' to merge them in",
+		version, record.AirVersion, record.Recorded.Local().Format("2006-01-02"), strings.Join(changed, ", "))
+}
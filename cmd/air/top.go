@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Show resource usage of running agents",
+	Long: `Shows per-agent CPU and memory of the processes in each agent's tmux window,
+worktree disk usage, and tokens consumed (from Claude session transcripts).
+
+Refreshes every --interval until interrupted. Use --once to print a single snapshot.`,
+	Args: cobra.NoArgs,
+	RunE: runTop,
+}
+
+var topInterval time.Duration
+var topOnce bool
+
+func init() {
+	topCmd.Flags().DurationVar(&topInterval, "interval", 2*time.Second, "Refresh interval")
+	topCmd.Flags().BoolVar(&topOnce, "once", false, "Print one snapshot and exit")
+}
+
+// agentResources is one row of the air top table
+type agentResources struct {
+	label   string
+	running bool    // a tmux window exists for this agent
+	cpu     float64 // percent, summed over the window's process tree
+	rssKB   int64
+	disk    int64
+	tokens  int64
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	info, err := detectMode()
+	if err != nil {
+		return fmt.Errorf("failed to detect mode: %w", err)
+	}
+
+	for {
+		worktrees, err := runWorktrees(info)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read worktrees: %w", err)
+		}
+
+		rows := collectAgentResources(worktrees)
+		if !topOnce {
+			// Clear screen and move cursor home
+			fmt.Print("\033[H\033[2J")
+		}
+		printTop(rows)
+
+		if topOnce {
+			return nil
+		}
+		time.Sleep(topInterval)
+	}
+}
+
+// collectAgentResources gathers a resource snapshot for each worktree
+func collectAgentResources(worktrees []worktreeInfo) []agentResources {
+	panes := tmuxPanePIDs(runSession())
+	procs := processTable()
+
+	var rows []agentResources
+	for _, wt := range worktrees {
+		row := agentResources{label: wt.name}
+		if wt.repoName != "" {
+			row.label = fmt.Sprintf("%s [%s]", wt.name, wt.repoName)
+		}
+		if pid, ok := panes[wt.name]; ok {
+			row.running = true
+			row.cpu, row.rssKB = procs.treeUsage(pid)
+		}
+		row.disk = dirSize(wt.wtPath)
+		row.tokens = transcriptUsage(wt.wtPath).total()
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func printTop(rows []agentResources) {
+	fmt.Printf("air top - %s\n\n", time.Now().Format("15:04:05"))
+	if len(rows) == 0 {
+		fmt.Println("No active agents. Run 'air run' to start.")
+		return
+	}
+
+	fmt.Printf("  %-28s %7s %8s %8s %8s\n", "AGENT", "CPU%", "MEM", "DISK", "TOKENS")
+	var totalCPU float64
+	var totalRSS, totalDisk, totalTokens int64
+	for _, r := range rows {
+		cpu, mem := "-", "-"
+		if r.running {
+			cpu = fmt.Sprintf("%.1f", r.cpu)
+			mem = formatBytes(r.rssKB * 1024)
+		}
+		fmt.Printf("  %-28s %7s %8s %8s %8s\n", r.label, cpu, mem, formatBytes(r.disk), formatCount(r.tokens))
+		totalCPU += r.cpu
+		totalRSS += r.rssKB
+		totalDisk += r.disk
+		totalTokens += r.tokens
+	}
+	fmt.Printf("  %-28s %7.1f %8s %8s %8s\n", "TOTAL", totalCPU, formatBytes(totalRSS*1024), formatBytes(totalDisk), formatCount(totalTokens))
+}
+
+// tmuxPanePIDs maps window name to the pid of its first pane's process
+func tmuxPanePIDs(session string) map[string]int {
+	panes := make(map[string]int)
+	// Windows are renamed by state (see renameAgentWindows), so prefer the @air-agent tag
+	out, err := exec.Command("tmux", "list-panes", "-s", "-t", "="+session, "-F", "#{?@air-agent,#{@air-agent},#{window_name}}\t#{pane_pid}").Output()
+	if err != nil {
+		return panes
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 {
+			continue
+		}
+		if pid, err := strconv.Atoi(fields[1]); err == nil {
+			if _, seen := panes[fields[0]]; !seen {
+				panes[fields[0]] = pid
+			}
+		}
+	}
+	return panes
+}
+
+// procInfo is one row of the process table
+type procInfo struct {
+	ppid  int
+	cpu   float64
+	rssKB int64
+}
+
+// procTable maps pid to process info
+type procTable map[int]procInfo
+
+// processTable snapshots all processes via ps (portable across Linux and macOS)
+func processTable() procTable {
+	procs := make(procTable)
+	out, err := exec.Command("ps", "-axo", "pid=,ppid=,pcpu=,rss=").Output()
+	if err != nil {
+		return procs
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		cpu, err3 := strconv.ParseFloat(fields[2], 64)
+		rss, err4 := strconv.ParseInt(fields[3], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			continue
+		}
+		procs[pid] = procInfo{ppid: ppid, cpu: cpu, rssKB: rss}
+	}
+	return procs
+}
+
+// treeUsage sums CPU and RSS for root and all of its descendants
+func (p procTable) treeUsage(root int) (cpu float64, rssKB int64) {
+	children := make(map[int][]int)
+	for pid, info := range p {
+		children[info.ppid] = append(children[info.ppid], pid)
+	}
+	queue := []int{root}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		if info, ok := p[pid]; ok {
+			cpu += info.cpu
+			rssKB += info.rssKB
+		}
+		queue = append(queue, children[pid]...)
+	}
+	return cpu, rssKB
+}
+
+// dirSize returns the total size of regular files under path (0 if missing)
+func dirSize(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if fi, err := d.Info(); err == nil {
+				size += fi.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// formatBytes renders a byte count as a short human-readable string (e.g. 12.3M)
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatCount renders a count with k/M suffixes (e.g. 1.2M)
+func formatCount(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return strconv.FormatInt(n, 10)
+	}
+}
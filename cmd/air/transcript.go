@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// tokenUsage is the summed API usage from an agent's Claude transcripts
+type tokenUsage struct {
+	Input         int64 `json:"input_tokens"`
+	Output        int64 `json:"output_tokens"`
+	CacheCreation int64 `json:"cache_creation_input_tokens"`
+	CacheRead     int64 `json:"cache_read_input_tokens"`
+}
+
+// total returns all tokens processed, including cache reads and writes
+func (u tokenUsage) total() int64 {
+	return u.Input + u.Output + u.CacheCreation + u.CacheRead
+}
+
+// transcriptEntry is the subset of a Claude transcript line we read
+type transcriptEntry struct {
+	Type        string    `json:"type"` // user, assistant, system, summary, ...
+	Timestamp   time.Time `json:"timestamp"`
+	IsSidechain bool      `json:"isSidechain"` // subagent (Task tool) traffic
+	IsAPIError  bool      `json:"isApiErrorMessage"`
+	Message     struct {
+		Usage      *tokenUsage     `json:"usage"`
+		StopReason string          `json:"stop_reason"`
+		Content    json.RawMessage `json:"content"` // a string, or a list of blocks
+	} `json:"message"`
+}
+
+// contentBlock is one block of a message's content list
+type contentBlock struct {
+	Type string `json:"type"` // text, tool_use, tool_result, ...
+	Name string `json:"name"` // tool name (tool_use)
+	Text string `json:"text"` // text
+}
+
+// blocks returns the entry's content blocks (none if the content is a plain string)
+func (e transcriptEntry) blocks() []contentBlock {
+	var blocks []contentBlock
+	json.Unmarshal(e.Message.Content, &blocks)
+	return blocks
+}
+
+// nonAlphanumericRegex matches characters Claude replaces when naming project directories
+var nonAlphanumericRegex = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// claudeConfigDir returns Claude's config directory (CLAUDE_CONFIG_DIR or ~/.claude)
+func claudeConfigDir() string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".claude")
+}
+
+// transcriptFiles returns the Claude session transcripts (*.jsonl) for sessions
+// started in dir. Claude keys its project directories by the sanitized cwd.
+func transcriptFiles(dir string) []string {
+	configDir := claudeConfigDir()
+	if configDir == "" {
+		return nil
+	}
+
+	candidates := []string{dir}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil && resolved != dir {
+		candidates = append(candidates, resolved)
+	}
+
+	var files []string
+	for _, c := range candidates {
+		projectDir := filepath.Join(configDir, "projects", nonAlphanumericRegex.ReplaceAllString(c, "-"))
+		matches, _ := filepath.Glob(filepath.Join(projectDir, "*.jsonl"))
+		files = append(files, matches...)
+	}
+	return files
+}
+
+// transcriptUsage sums token usage across all transcripts for sessions in dir
+func transcriptUsage(dir string) tokenUsage {
+	var usage tokenUsage
+	for _, path := range transcriptFiles(dir) {
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if !strings.Contains(string(line), `"usage"`) {
+				continue
+			}
+			var entry transcriptEntry
+			if err := json.Unmarshal(line, &entry); err != nil || entry.Message.Usage == nil {
+				continue
+			}
+			u := entry.Message.Usage
+			usage.Input += u.Input
+			usage.Output += u.Output
+			usage.CacheCreation += u.CacheCreation
+			usage.CacheRead += u.CacheRead
+		}
+		f.Close()
+	}
+	return usage
+}
+
+// Agent activity states read from the end of its transcript
+const (
+	activityUnknown     = ""             // no transcript, or nothing conclusive
+	activityWorking     = "working"      // Claude is mid-turn
+	activityInput       = "input"        // turn ended: Claude is waiting for the user
+	activityToolPending = "tool-pending" // a tool call has no result yet
+)
+
+// transcriptActivity reports what the agent's most recent session is doing,
+// from its last main-thread user or assistant entry. A pending tool call is
+// either running or held at a permission prompt; the caller decides by age.
+func transcriptActivity(dir string) (state string, since time.Time, tool string) {
+	files := transcriptFiles(dir)
+	if len(files) == 0 {
+		return activityUnknown, time.Time{}, ""
+	}
+	// The most recently written transcript is the live session
+	sort.Slice(files, func(i, j int) bool { return modTime(files[i]).After(modTime(files[j])) })
+
+	f, err := os.Open(files[0])
+	if err != nil {
+		return activityUnknown, time.Time{}, ""
+	}
+	defer f.Close()
+
+	// Only the tail matters; transcripts grow to many megabytes
+	const tail = 512 * 1024
+	if fi, err := f.Stat(); err == nil && fi.Size() > tail {
+		f.Seek(fi.Size()-tail, io.SeekStart)
+	}
+
+	var last *transcriptEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var entry transcriptEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.IsSidechain {
+			continue
+		}
+		if entry.Type == "user" || entry.Type == "assistant" {
+			last = &entry
+		}
+	}
+	if last == nil {
+		return activityUnknown, time.Time{}, ""
+	}
+
+	if last.Type == "user" {
+		return activityWorking, last.Timestamp, ""
+	}
+	for _, b := range last.blocks() {
+		if b.Type == "tool_use" {
+			return activityToolPending, last.Timestamp, b.Name
+		}
+	}
+	// Text with no tool call ends the turn (stop_reason isn't always recorded)
+	if sr := last.Message.StopReason; sr == "" || sr == "end_turn" {
+		return activityInput, last.Timestamp, ""
+	}
+	return activityWorking, last.Timestamp, ""
+}
+
+// modTime returns a file's modification time (zero if it can't be read)
+func modTime(path string) time.Time {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
+// transcriptTail returns the last n main-thread steps of the most recently
+// written transcript in files: prompts, Claude's replies, and the tools it called
+func transcriptTail(files []string, n int) []string {
+	if len(files) == 0 {
+		return nil
+	}
+	files = append([]string{}, files...)
+	sort.Slice(files, func(i, j int) bool { return modTime(files[i]).After(modTime(files[j])) })
+
+	f, err := os.Open(files[0])
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	const tail = 512 * 1024
+	if fi, err := f.Stat(); err == nil && fi.Size() > tail {
+		f.Seek(fi.Size()-tail, io.SeekStart)
+	}
+
+	// oneLine flattens and shortens a message for the report
+	oneLine := func(text string) string {
+		text = strings.Join(strings.Fields(text), " ")
+		if runes := []rune(text); len(runes) > 300 {
+			text = string(runes[:300]) + "..."
+		}
+		return text
+	}
+
+	var steps []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var entry transcriptEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.IsSidechain {
+			continue
+		}
+		var prompt string
+		if entry.Type == "user" && json.Unmarshal(entry.Message.Content, &prompt) == nil && strings.TrimSpace(prompt) != "" {
+			steps = append(steps, "User: "+oneLine(prompt))
+			continue
+		}
+		if entry.Type != "assistant" {
+			continue
+		}
+		for _, b := range entry.blocks() {
+			switch {
+			case b.Type == "text" && strings.TrimSpace(b.Text) != "":
+				steps = append(steps, "Claude: "+oneLine(b.Text))
+			case b.Type == "tool_use":
+				steps = append(steps, "→ "+b.Name)
+			}
+		}
+	}
+	if len(steps) > n {
+		steps = steps[len(steps)-n:]
+	}
+	return steps
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Summarize tokens and agent-hours across all projects",
+	Long: `Adds up what agents used over the last --days across every project under
+~/.air/, from each project's event log, broken down by project and plan:
+
+  - launches
+  - agent-hours, from launch to 'air agent done', 'air agent fail', or 'air
+    stop' (agents that haven't finished count up to their last heartbeat)
+  - tokens, from each agent's Claude transcripts when it finished
+
+Pass --price (USD per million tokens) to add an estimated cost column.`,
+	Args: cobra.NoArgs,
+	RunE: runUsage,
+}
+
+var usageDays int
+var usagePrice float64
+
+func init() {
+	usageCmd.Flags().IntVar(&usageDays, "days", 30, "Days of history to include")
+	usageCmd.Flags().Float64Var(&usagePrice, "price", 0, "USD per million tokens, for estimated cost")
+	supportsOutput(usageCmd)
+}
+
+// usageReport is the summary printed by 'air usage'
+type usageReport struct {
+	Since    time.Time      `json:"since"`
+	Total    usageTotals    `json:"total"`
+	Projects []usageProject `json:"projects"`
+}
+
+// usageTotals is what a project, a plan, or everything used
+type usageTotals struct {
+	Launches   int     `json:"launches"`
+	AgentHours float64 `json:"agent_hours"`
+	Tokens     int64   `json:"tokens"`
+	Cost       float64 `json:"cost,omitempty"`
+}
+
+// add counts u in t
+func (t *usageTotals) add(u usageTotals) {
+	t.Launches += u.Launches
+	t.AgentHours += u.AgentHours
+	t.Tokens += u.Tokens
+	t.Cost += u.Cost
+}
+
+// usageProject is one project's usage, by plan
+type usageProject struct {
+	Project string `json:"project"`
+	usageTotals
+	Plans []usagePlan `json:"plans"`
+}
+
+// usagePlan is one plan's usage within its project
+type usagePlan struct {
+	Plan string `json:"plan"`
+	usageTotals
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	if usageDays <= 0 {
+		return withCode(codeUsage, fmt.Errorf("--days must be 1 or more"))
+	}
+	root, err := getAirRoot()
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", root, err)
+	}
+
+	now := time.Now()
+	report := usageReport{Since: now.AddDate(0, 0, -usageDays).UTC(), Projects: []usageProject{}}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		events, err := readEventsFile(filepath.Join(dir, "events.jsonl"))
+		if err != nil {
+			return fmt.Errorf("failed to read %s's event log: %w", e.Name(), err)
+		}
+		lastBeat := func(agent string) time.Time {
+			if hb := readHeartbeatFile(filepath.Join(dir, "agents", agent, "heartbeat.json")); hb != nil {
+				return hb.Time
+			}
+			return time.Time{}
+		}
+		p := computeUsage(e.Name(), events, lastBeat, report.Since, usagePrice)
+		if p.Launches > 0 || p.Tokens > 0 || p.AgentHours > 0 {
+			report.Projects = append(report.Projects, p)
+			report.Total.add(p.usageTotals)
+		}
+	}
+	sort.Slice(report.Projects, func(i, j int) bool {
+		a, b := report.Projects[i], report.Projects[j]
+		if a.Tokens != b.Tokens {
+			return a.Tokens > b.Tokens
+		}
+		return a.Project < b.Project
+	})
+
+	return render(report, func() { printUsage(&report) })
+}
+
+// computeUsage sums a project's usage since the given time from its events.
+// A launch runs until the agent's next done, failed, or stopped event, or, if
+// it has none, until lastBeat(agent); only the part after since counts. Done
+// and stopped events carry the total tokens of the plan's worktree so far, so each counts what
+// was added since the plan's previous one.
+func computeUsage(project string, events []Event, lastBeat func(agent string) time.Time, since time.Time, price float64) usageProject {
+	plans := make(map[string]*usagePlan)
+	plan := func(name string) *usagePlan {
+		if plans[name] == nil {
+			plans[name] = &usagePlan{Plan: name}
+		}
+		return plans[name]
+	}
+	addHours := func(name string, start, end time.Time) {
+		if start.Before(since) {
+			start = since
+		}
+		if end.After(start) {
+			plan(name).AgentHours += end.Sub(start).Hours()
+		}
+	}
+
+	open := make(map[string]time.Time) // unfinished launch per agent
+	tokensSoFar := make(map[string]int64)
+	for _, e := range events {
+		switch e.Kind {
+		case eventLaunched:
+			// An unfinished launch that was relaunched has no heartbeat left to end it
+			open[e.Agent] = e.Time
+			if !e.Time.Before(since) {
+				plan(e.Agent).Launches++
+			}
+		case eventDone, eventFailed, eventStopped:
+			if start, ok := open[e.Agent]; ok {
+				addHours(e.Agent, start, e.Time)
+				delete(open, e.Agent)
+			}
+			if e.Tokens == nil {
+				continue
+			}
+			// Fewer tokens than before means the transcripts were deleted
+			total := e.Tokens.total()
+			added := total - tokensSoFar[e.Agent]
+			if added < 0 {
+				added = total
+			}
+			tokensSoFar[e.Agent] = total
+			if !e.Time.Before(since) && added > 0 {
+				plan(e.Agent).Tokens += added
+			}
+		}
+	}
+	for agent, start := range open {
+		addHours(agent, start, lastBeat(agent))
+	}
+
+	p := usageProject{Project: project, Plans: []usagePlan{}}
+	for _, u := range plans {
+		if u.Launches == 0 && u.Tokens == 0 && u.AgentHours == 0 {
+			continue
+		}
+		u.Cost = float64(u.Tokens) / 1_000_000 * price
+		p.Plans = append(p.Plans, *u)
+		p.add(u.usageTotals)
+	}
+	sort.Slice(p.Plans, func(i, j int) bool {
+		a, b := p.Plans[i], p.Plans[j]
+		if a.Tokens != b.Tokens {
+			return a.Tokens > b.Tokens
+		}
+		return a.Plan < b.Plan
+	})
+	return p
+}
+
+// printUsage prints a usage report for humans
+func printUsage(report *usageReport) {
+	if len(report.Projects) == 0 {
+		fmt.Printf("No agents ran in the last %d days.\n", usageDays)
+		return
+	}
+
+	fmt.Printf("Usage since %s (last %d days)\n\n", report.Since.Local().Format("2006-01-02"), usageDays)
+	row := func(label string, u usageTotals) {
+		line := fmt.Sprintf("%-32s %8d %11s %8s", label, u.Launches, formatDuration(time.Duration(u.AgentHours*float64(time.Hour))), formatCount(u.Tokens))
+		if usagePrice > 0 {
+			line += fmt.Sprintf(" %9s", fmt.Sprintf("$%.2f", u.Cost))
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+	header := fmt.Sprintf("%-32s %8s %11s %8s", "PROJECT / PLAN", "LAUNCHES", "AGENT-HOURS", "TOKENS")
+	if usagePrice > 0 {
+		header += fmt.Sprintf(" %9s", "EST. COST")
+	}
+	fmt.Println(header)
+	for _, p := range report.Projects {
+		row(p.Project, p.usageTotals)
+		for _, u := range p.Plans {
+			row("  "+u.Plan, u.usageTotals)
+		}
+	}
+	fmt.Println()
+	row("Total", report.Total)
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -14,22 +15,144 @@ var planValidateCmd = &cobra.Command{
 	Use:   "validate",
 	Short: "Validate plan dependency graph",
 	Long: `Validates that all plan dependencies are satisfiable:
-- Every channel waited on has exactly one plan that signals it
+- Every channel waited on has exactly one plan that signals it, or is listed
+  under **External:** (signaled by a human or outside system)
 - No cycles exist in the dependency graph
-- No channel is signaled by multiple plans`,
+- No channel is signaled by multiple plans
+
+Channels under **Waits on (optional):** are soft edges: they are excluded from
+these checks, and an optional channel no plan signals is only a warning.
+
+Plans in a group (plans/<group>/) are validated separately from other
+groups; use --group to check just one.
+
+In workspace mode, also reports the order repositories must be integrated in
+(upstream first) and warns about cycles between repositories.`,
 	RunE: runPlanValidate,
 }
 
+var validateGroup string
+
 func init() {
 	planCmd.AddCommand(planValidateCmd)
+	planValidateCmd.Flags().StringVar(&validateGroup, "group", "", "Validate only the plans in this group")
 }
 
 // PlanDependencies represents the dependency information extracted from a plan
 type PlanDependencies struct {
 	Name       string
+	Group      string   // Plan group (subdirectory of plans/), "" if ungrouped
+	Phase      int      // **Phase:** number, 0 if unphased, -1 if invalid
 	Repository string   // Target repository (required in workspace mode)
+	Component  string   // Target component directory (required in monorepo mode)
+	InScope    []string // Paths listed under **In scope:**
 	WaitsOn    []string
+	Optional   []string // Channels under **Waits on (optional):** - waited on with a timeout, then skipped
 	Signals    []string
+	External   []string              // Channels signaled outside the plan set (by a human or another system)
+	WaitPolicy map[string]WaitPolicy // Timeout/fallback annotations on waited channels, by channel
+	Consumes   []ConsumeSpec         // Upstream repo paths copied in after a wait (workspace mode)
+	Context    []string              // **Context files:** names, resolved in the context/ directory
+	CommitFmt  string                // **Commit format:** subject template, e.g. "<type>({plan}): <summary>"
+}
+
+// ConsumeSpec is one **Consumes:** entry, e.g. "schema:protos/gen/" or
+// "schema:protos/gen/ -> internal/gen/". After a wait on a channel signaled from
+// Repo, Path at the signaled commit is copied into the consumer's worktree at Dest.
+type ConsumeSpec struct {
+	Repo string
+	Path string
+	Dest string
+}
+
+func (c ConsumeSpec) String() string {
+	if c.Dest != c.Path {
+		return fmt.Sprintf("%s:%s -> %s", c.Repo, c.Path, c.Dest)
+	}
+	return c.Repo + ":" + c.Path
+}
+
+// WaitPolicy is the bounded-wait annotation on a **Waits on:** entry, e.g.
+// "- `schema-ready` (timeout: 30m, fallback: proceed) - ..."
+type WaitPolicy struct {
+	Timeout  string // Duration string passed to 'air agent wait --timeout'
+	Fallback string // What to do on timeout: fail, proceed, or ask
+}
+
+// waitFallbacks are the supported WaitPolicy.Fallback values
+var waitFallbacks = []string{"fail", "proceed", "ask"}
+
+// waitAnnotationRegex matches the parenthesized annotation after a channel name
+var waitAnnotationRegex = regexp.MustCompile("^`[^`]+`\\s*\\(([^)]*)\\)")
+
+// parseWaitPolicy reads "timeout: 30m, fallback: proceed" from a wait list item.
+// Returns false if the item has no timeout or fallback annotation.
+func parseWaitPolicy(item string) (WaitPolicy, bool) {
+	matches := waitAnnotationRegex.FindStringSubmatch(strings.TrimSpace(strings.TrimPrefix(item, "- ")))
+	if len(matches) < 2 {
+		return WaitPolicy{}, false
+	}
+	var policy WaitPolicy
+	found := false
+	for _, kv := range strings.Split(matches[1], ",") {
+		key, value, ok := strings.Cut(kv, ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "timeout":
+			policy.Timeout, found = strings.TrimSpace(value), true
+		case "fallback":
+			policy.Fallback, found = strings.ToLower(strings.TrimSpace(value)), true
+		}
+	}
+	return policy, found
+}
+
+// waitPolicyFor returns the effective policy for a waited channel. Optional
+// waits default to a 10 minute timeout with fallback proceed; an annotated
+// timeout without a fallback fails.
+func (p PlanDependencies) waitPolicyFor(channel string) (WaitPolicy, bool) {
+	policy, ok := p.WaitPolicy[channel]
+	if contains(p.Optional, channel) {
+		if policy.Timeout == "" {
+			policy.Timeout = "10m"
+		}
+		if policy.Fallback == "" {
+			policy.Fallback = "proceed"
+		}
+		return policy, true
+	}
+	if ok && policy.Fallback == "" {
+		policy.Fallback = "fail"
+	}
+	return policy, ok
+}
+
+// parseConsumeSpecs parses a comma-separated list of consume entries.
+// Entries without a "repo:" prefix keep an empty Repo so validation can report them.
+func parseConsumeSpecs(value string) []ConsumeSpec {
+	var specs []ConsumeSpec
+	for _, item := range strings.Split(value, ",") {
+		item = strings.Trim(strings.TrimSpace(item), "`")
+		if item == "" {
+			continue
+		}
+		var spec ConsumeSpec
+		src, dest, hasDest := strings.Cut(item, "->")
+		src = strings.Trim(strings.TrimSpace(src), "`")
+		if repo, path, ok := strings.Cut(src, ":"); ok {
+			spec.Repo, spec.Path = strings.TrimSpace(repo), strings.TrimSpace(path)
+		} else {
+			spec.Path = src
+		}
+		spec.Dest = spec.Path
+		if hasDest {
+			spec.Dest = strings.Trim(strings.TrimSpace(dest), "`")
+		}
+		specs = append(specs, spec)
+	}
+	return specs
 }
 
 // channelRegex matches backtick-wrapped channel names like `setup-complete`
@@ -38,6 +161,18 @@ var channelRegex = regexp.MustCompile("`([^`]+)`")
 // repositoryRegex matches **Repository:** field value
 var repositoryRegex = regexp.MustCompile(`^\*\*Repository:\*\*\s*(.+)$`)
 
+// componentRegex matches **Component:** field value
+var componentRegex = regexp.MustCompile(`^\*\*Component:\*\*\s*(.+)$`)
+
+// consumesRegex matches **Consumes:** field value
+var consumesRegex = regexp.MustCompile(`^\*\*Consumes:\*\*\s*(.+)$`)
+
+// contextFilesRegex matches **Context files:** field value
+var contextFilesRegex = regexp.MustCompile(`^\*\*Context files:\*\*\s*(.+)$`)
+
+// commitFormatRegex matches **Commit format:** field value
+var commitFormatRegex = regexp.MustCompile(`^\*\*Commit format:\*\*\s*(.+)$`)
+
 // parsePlanDependencies extracts dependency information from plan markdown content
 func parsePlanDependencies(name, content string) PlanDependencies {
 	deps := PlanDependencies{Name: name}
@@ -54,15 +189,61 @@ func parsePlanDependencies(name, content string) PlanDependencies {
 			continue
 		}
 
+		// Check for Component field
+		if matches := componentRegex.FindStringSubmatch(trimmed); len(matches) >= 2 {
+			deps.Component = strings.Trim(strings.TrimSpace(matches[1]), "`/")
+			continue
+		}
+
+		// Check for Phase field
+		if matches := phaseRegex.FindStringSubmatch(trimmed); len(matches) >= 2 {
+			deps.Phase = parsePhase(matches[1])
+			continue
+		}
+
+		// Check for Consumes field
+		if matches := consumesRegex.FindStringSubmatch(trimmed); len(matches) >= 2 {
+			deps.Consumes = append(deps.Consumes, parseConsumeSpecs(matches[1])...)
+			continue
+		}
+
+		// Check for Context files field
+		if matches := contextFilesRegex.FindStringSubmatch(trimmed); len(matches) >= 2 {
+			for _, name := range strings.Split(matches[1], ",") {
+				if name = strings.Trim(strings.TrimSpace(name), "`"); name != "" {
+					deps.Context = append(deps.Context, name)
+				}
+			}
+			continue
+		}
+
+		// Check for Commit format field
+		if matches := commitFormatRegex.FindStringSubmatch(trimmed); len(matches) >= 2 {
+			deps.CommitFmt = strings.Trim(strings.TrimSpace(matches[1]), "`")
+			continue
+		}
+
 		// Detect section headers
 		if strings.HasPrefix(trimmed, "**Waits on:**") {
 			currentSection = "waits"
 			continue
 		}
+		if strings.HasPrefix(trimmed, "**Waits on (optional):**") {
+			currentSection = "optional"
+			continue
+		}
 		if strings.HasPrefix(trimmed, "**Signals:**") {
 			currentSection = "signals"
 			continue
 		}
+		if strings.HasPrefix(trimmed, "**External:**") {
+			currentSection = "external"
+			continue
+		}
+		if strings.HasPrefix(trimmed, "**In scope:**") {
+			currentSection = "scope"
+			continue
+		}
 
 		// End section on other bold headers or section headers
 		if strings.HasPrefix(trimmed, "**") || strings.HasPrefix(trimmed, "##") {
@@ -70,6 +251,14 @@ func parsePlanDependencies(name, content string) PlanDependencies {
 			continue
 		}
 
+		// Scope items are paths, optionally backtick-wrapped and followed by a description
+		if currentSection == "scope" && strings.HasPrefix(trimmed, "- ") {
+			if path := parseScopePath(trimmed); path != "" {
+				deps.InScope = append(deps.InScope, path)
+			}
+			continue
+		}
+
 		// Parse list items in current section
 		if currentSection != "" && strings.HasPrefix(trimmed, "- ") {
 			matches := channelRegex.FindStringSubmatch(trimmed)
@@ -79,6 +268,16 @@ func parsePlanDependencies(name, content string) PlanDependencies {
 					deps.WaitsOn = append(deps.WaitsOn, channel)
 				} else if currentSection == "signals" {
 					deps.Signals = append(deps.Signals, channel)
+				} else if currentSection == "optional" {
+					deps.Optional = append(deps.Optional, channel)
+				} else if currentSection == "external" {
+					deps.External = append(deps.External, channel)
+				}
+				if policy, ok := parseWaitPolicy(trimmed); ok && (currentSection == "waits" || currentSection == "optional") {
+					if deps.WaitPolicy == nil {
+						deps.WaitPolicy = make(map[string]WaitPolicy)
+					}
+					deps.WaitPolicy[channel] = policy
 				}
 			}
 		}
@@ -87,6 +286,19 @@ func parsePlanDependencies(name, content string) PlanDependencies {
 	return deps
 }
 
+// parseScopePath extracts the path from an **In scope:** list item such as
+// "- `pkg/resp/` - parser" or "- src/simple.go"
+func parseScopePath(item string) string {
+	item = strings.TrimSpace(strings.TrimPrefix(item, "- "))
+	if matches := channelRegex.FindStringSubmatch(item); len(matches) >= 2 {
+		return matches[1]
+	}
+	if fields := strings.Fields(item); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
 // ValidationError represents a single validation error
 type ValidationError struct {
 	Message string
@@ -104,6 +316,8 @@ func validateDependencyGraph(plans []PlanDependencies) []error {
 	signaled := make(map[string]string) // channel -> signaling plan
 	// Track which plans wait on which channel
 	waited := make(map[string][]string) // channel -> waiting plans
+	// Track channels declared as signaled outside the plan set
+	external := make(map[string]string) // channel -> declaring plan
 
 	// First pass: collect all signals and waits
 	for _, p := range plans {
@@ -118,13 +332,26 @@ func validateDependencyGraph(plans []PlanDependencies) []error {
 		for _, ch := range p.WaitsOn {
 			waited[ch] = append(waited[ch], p.Name)
 		}
+		for _, ch := range p.External {
+			external[ch] = p.Name
+		}
 	}
 
-	// Check every waited channel has a signaler
+	// External channels must not also be signaled by a plan
+	for ch, decl := range external {
+		if signaler, ok := signaled[ch]; ok {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("channel '%s' is declared external by '%s' but is signaled by plan '%s'", ch, decl, signaler),
+			})
+		}
+	}
+
+	// Check every waited channel has a signaler (or is declared external)
 	for ch, waiters := range waited {
-		if _, ok := signaled[ch]; !ok {
+		_, isSignaled := signaled[ch]
+		if _, isExternal := external[ch]; !isSignaled && !isExternal {
 			errs = append(errs, ValidationError{
-				Message: fmt.Sprintf("channel '%s' is waited on by [%s] but no plan signals it", ch, strings.Join(waiters, ", ")),
+				Message: fmt.Sprintf("channel '%s' is waited on by [%s] but no plan signals it (list it under **External:** if it is signaled with 'air signal')", ch, strings.Join(waiters, ", ")),
 			})
 		}
 	}
@@ -218,31 +445,22 @@ func detectCycles(plans []PlanDependencies, signaled map[string]string) []error
 	return nil
 }
 
-// loadAllPlanDependencies reads all plans and extracts their dependencies
+// loadAllPlanDependencies reads all plans, in every group, and extracts their dependencies
 func loadAllPlanDependencies() ([]PlanDependencies, error) {
-	plansDir := getPlansDir()
-
-	entries, err := os.ReadDir(plansDir)
+	files, err := listPlanFiles(getPlansDir())
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to read plans directory: %w", err)
+		return nil, err
 	}
 
 	var plans []PlanDependencies
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
-			continue
-		}
-
-		name := strings.TrimSuffix(entry.Name(), ".md")
-		content, err := os.ReadFile(filepath.Join(plansDir, entry.Name()))
+	for _, f := range files {
+		content, err := os.ReadFile(f.path)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read plan %s: %w", name, err)
+			return nil, fmt.Errorf("failed to read plan %s: %w", f.name, err)
 		}
 
-		deps := parsePlanDependencies(name, string(content))
+		deps := parsePlanDependencies(f.name, string(content))
+		deps.Group = f.group
 		plans = append(plans, deps)
 	}
 
@@ -254,7 +472,8 @@ func ValidatePlans() ([]PlanDependencies, []error) {
 	return ValidatePlansWithMode(nil)
 }
 
-// ValidatePlansWithMode loads all plans and validates them with mode awareness
+// ValidatePlansWithMode loads all plans and validates them with mode awareness.
+// Each plan group is validated on its own: its channels form a separate graph.
 func ValidatePlansWithMode(info *WorkspaceInfo) ([]PlanDependencies, []error) {
 	plans, err := loadAllPlanDependencies()
 	if err != nil {
@@ -265,6 +484,30 @@ func ValidatePlansWithMode(info *WorkspaceInfo) ([]PlanDependencies, []error) {
 		return nil, nil
 	}
 
+	errs := duplicatePlanErrors(plans)
+	errs = append(errs, sharedChannelErrors(plans, "", false)...)
+	for _, group := range planGroups(plans) {
+		errs = append(errs, validatePlanGroup(plansInGroup(plans, group), group, info)...)
+	}
+	return plans, errs
+}
+
+// ValidatePlanGroup loads all plans and validates only those in group
+func ValidatePlanGroup(info *WorkspaceInfo, group string) ([]PlanDependencies, []error) {
+	plans, err := loadAllPlanDependencies()
+	if err != nil {
+		return nil, []error{err}
+	}
+	shared := sharedChannelErrors(plans, group, true)
+	plans = plansInGroup(plans, group)
+	if len(plans) == 0 {
+		return nil, nil
+	}
+	return plans, append(shared, validatePlanGroup(plans, group, info)...)
+}
+
+// validatePlanGroup validates one group's plans, prefixing errors with the group's name
+func validatePlanGroup(plans []PlanDependencies, group string, info *WorkspaceInfo) []error {
 	var errs []error
 
 	// If workspace info provided, validate repository references
@@ -273,11 +516,30 @@ func ValidatePlansWithMode(info *WorkspaceInfo) ([]PlanDependencies, []error) {
 		errs = append(errs, repoErrs...)
 	}
 
+	// Consumes entries need an upstream repo and a wait that triggers the copy
+	if info != nil {
+		errs = append(errs, validateConsumes(plans, info)...)
+	}
+
+	// In monorepo mode, validate component references and scope boundaries
+	if info != nil && info.Mode == ModeMonorepo {
+		errs = append(errs, validateComponentReferences(plans, info)...)
+	}
+
+	errs = append(errs, validateWaitPolicies(plans)...)
+	errs = append(errs, validateContextFiles(plans, getContextDir())...)
+	errs = append(errs, validatePhases(plans)...)
+
 	// Validate dependency graph
 	graphErrs := validateDependencyGraph(plans)
 	errs = append(errs, graphErrs...)
 
-	return plans, errs
+	if group != "" {
+		for i, err := range errs {
+			errs[i] = ValidationError{Message: fmt.Sprintf("group '%s': %s", group, err)}
+		}
+	}
+	return errs
 }
 
 // validateRepositoryReferences checks that all plans have valid repository references
@@ -310,9 +572,267 @@ func validateRepositoryReferences(plans []PlanDependencies, info *WorkspaceInfo)
 	return errs
 }
 
+// validateWaitPolicies checks that wait annotations have a valid timeout and a
+// known fallback
+func validateWaitPolicies(plans []PlanDependencies) []error {
+	var errs []error
+	for _, p := range plans {
+		for _, ch := range append(append([]string{}, p.WaitsOn...), p.Optional...) {
+			policy, ok := p.waitPolicyFor(ch)
+			if !ok {
+				continue
+			}
+			if policy.Timeout == "" {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' sets a fallback for '%s' without a timeout", p.Name, ch),
+				})
+			} else if d, err := time.ParseDuration(policy.Timeout); err != nil || d <= 0 {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' has invalid timeout '%s' for '%s' (e.g. 30m, 2h)", p.Name, policy.Timeout, ch),
+				})
+			}
+			if !contains(waitFallbacks, policy.Fallback) {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' has unknown fallback '%s' for '%s' (expected %s)", p.Name, policy.Fallback, ch, strings.Join(waitFallbacks, ", ")),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// contextFilePath resolves a **Context files:** name in contextDir; ".md" is
+// implied when the name has no extension
+func contextFilePath(contextDir, name string) string {
+	if filepath.Ext(name) == "" {
+		name += ".md"
+	}
+	return filepath.Join(contextDir, name)
+}
+
+// validateContextFiles checks each **Context files:** entry exists in contextDir
+func validateContextFiles(plans []PlanDependencies, contextDir string) []error {
+	var errs []error
+	for _, p := range plans {
+		for _, name := range p.Context {
+			if !filepath.IsLocal(name) {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' lists context file '%s', which is outside %s", p.Name, name, contextDir),
+				})
+				continue
+			}
+			if _, err := os.Stat(contextFilePath(contextDir, name)); err != nil {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' lists context file '%s', but %s does not exist", p.Name, name, contextFilePath(contextDir, name)),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// validateConsumes checks each **Consumes:** entry names another workspace repo,
+// stays inside the worktree, and is triggered by a wait on a channel signaled
+// by a plan in that repo
+func validateConsumes(plans []PlanDependencies, info *WorkspaceInfo) []error {
+	var errs []error
+
+	signalerRepo := make(map[string]string) // channel -> repo of signaling plan
+	for _, p := range plans {
+		for _, ch := range p.Signals {
+			signalerRepo[ch] = p.Repository
+		}
+	}
+
+	for _, p := range plans {
+		for _, c := range p.Consumes {
+			if info.Mode != ModeWorkspace {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' uses **Consumes:**, which is only supported in workspace mode", p.Name),
+				})
+				break
+			}
+			if c.Repo == "" || c.Path == "" {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' has invalid **Consumes:** entry '%s' (expected <repo>:<path>)", p.Name, strings.TrimPrefix(c.String(), ":")),
+				})
+				continue
+			}
+			if !contains(info.Repos, c.Repo) {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' consumes from unknown repository '%s' (available: %v)", p.Name, c.Repo, info.Repos),
+				})
+				continue
+			}
+			if c.Repo == p.Repository {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' consumes from its own repository '%s' (use 'air agent merge' instead)", p.Name, c.Repo),
+				})
+				continue
+			}
+			if filepath.IsAbs(c.Dest) || !filepath.IsLocal(c.Dest) {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' consumes into '%s', which is outside its worktree", p.Name, c.Dest),
+				})
+				continue
+			}
+			triggered := false
+			for _, ch := range p.WaitsOn {
+				if signalerRepo[ch] == c.Repo {
+					triggered = true
+					break
+				}
+			}
+			if !triggered {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' consumes '%s' but waits on no channel signaled in repository '%s'", p.Name, c, c.Repo),
+				})
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateComponentReferences checks that every plan targets a known component
+// and that its in-scope paths stay inside that component's directory
+func validateComponentReferences(plans []PlanDependencies, info *WorkspaceInfo) []error {
+	var errs []error
+
+	for _, p := range plans {
+		if p.Component == "" {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("plan '%s' is missing required **Component:** field (monorepo mode)", p.Name),
+			})
+			continue
+		}
+
+		if !contains(info.Components, p.Component) {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("plan '%s' references unknown component '%s' (available: %v)", p.Name, p.Component, info.Components),
+			})
+			continue
+		}
+
+		for _, scope := range p.InScope {
+			if !isWithinComponent(scope, p.Component) {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("plan '%s' has in-scope path '%s' outside its component '%s'", p.Name, scope, p.Component),
+				})
+			}
+		}
+	}
+
+	return errs
+}
+
+// isWithinComponent reports whether a repo-relative path lies inside component
+func isWithinComponent(path, component string) bool {
+	cleaned := filepath.ToSlash(filepath.Clean(path))
+	return cleaned == component || strings.HasPrefix(cleaned, component+"/")
+}
+
+// repoDependencies derives repo-level edges from channel dependencies: a repo
+// depends on every other repo with a plan signaling a channel one of its plans waits on
+func repoDependencies(plans []PlanDependencies) map[string][]string {
+	signalerRepo := make(map[string]string)
+	for _, p := range plans {
+		for _, ch := range p.Signals {
+			signalerRepo[ch] = p.Repository
+		}
+	}
+
+	deps := make(map[string][]string)
+	for _, p := range plans {
+		for _, ch := range p.WaitsOn {
+			from, ok := signalerRepo[ch]
+			if !ok || from == "" || from == p.Repository || contains(deps[p.Repository], from) {
+				continue
+			}
+			deps[p.Repository] = append(deps[p.Repository], from)
+		}
+	}
+	return deps
+}
+
+// printRepoOrder reports the order repos must be integrated in (upstream
+// first), or warns if cross-repo dependencies form a cycle
+func printRepoOrder(plans []PlanDependencies, info *WorkspaceInfo) {
+	deps := repoDependencies(plans)
+	order, err := topoSort(info.Repos, deps)
+	if err != nil {
+		fmt.Printf("\nWarnings:\n  ⚠ repository %s; 'air integrate --auto' cannot order these repos\n", err)
+		return
+	}
+
+	fmt.Println("\nRepository order:")
+	for i, repo := range order {
+		if len(deps[repo]) > 0 {
+			fmt.Printf("  %d. %s (after %s)\n", i+1, repo, strings.Join(deps[repo], ", "))
+		} else {
+			fmt.Printf("  %d. %s\n", i+1, repo)
+		}
+	}
+}
+
+// optionalWaitWarnings returns a warning for each optional wait no plan in its
+// group signals or declares external. Optional waits are soft edges: they never fail
+// validation or form cycles, but an unsignaled one always times out.
+func optionalWaitWarnings(plans []PlanDependencies) []string {
+	type groupChannel struct{ group, channel string }
+	known := make(map[groupChannel]bool)
+	for _, p := range plans {
+		for _, ch := range p.Signals {
+			known[groupChannel{p.Group, ch}] = true
+		}
+		for _, ch := range p.External {
+			known[groupChannel{p.Group, ch}] = true
+		}
+	}
+
+	var warnings []string
+	for _, p := range plans {
+		for _, ch := range p.Optional {
+			if !known[groupChannel{p.Group, ch}] {
+				warnings = append(warnings, fmt.Sprintf("optional channel '%s' (waited on by '%s') is not signaled by any plan; its wait will always time out", ch, p.Name))
+			}
+		}
+	}
+	return warnings
+}
+
+// staleSignalWarnings returns a warning for each channel the plans wait on that
+// is already signaled from before runStart, since those waits would return
+// immediately. A zero runStart treats every existing signal as stale.
+func staleSignalWarnings(plans []PlanDependencies, runStart time.Time) []string {
+	var warnings []string
+	seen := make(map[string]bool)
+	now := time.Now()
+	for _, p := range plans {
+		for _, ch := range p.WaitsOn {
+			if seen[ch] {
+				continue
+			}
+			seen[ch] = true
+
+			payload, err := readChannel(ch)
+			if err != nil || payload.expired(now) {
+				continue
+			}
+			if !runStart.IsZero() && !payload.Timestamp.Before(runStart) {
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf(
+				"channel '%s' was already signaled by '%s' at %s (before the current run); waits on it will return immediately. Run 'air clean' or delete %s",
+				ch, payload.Agent, payload.Timestamp.Local().Format(time.RFC822), getChannelPath(ch)))
+		}
+	}
+	return warnings
+}
+
 func runPlanValidate(cmd *cobra.Command, args []string) error {
 	if !isInitialized() {
-		return fmt.Errorf("not initialized (run 'air init' first)")
+		return errNotInitialized()
 	}
 
 	// Detect mode for workspace-aware validation
@@ -321,9 +841,25 @@ func runPlanValidate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to detect mode: %w", err)
 	}
 
-	plans, errs := ValidatePlansWithMode(info)
+	return printPlanGraph(info, validateGroup)
+}
+
+// printPlanGraph validates the plans (only group's, if set) and prints their
+// dependency graph and any problems, failing if the plans aren't valid
+func printPlanGraph(info *WorkspaceInfo, group string) error {
+	var plans []PlanDependencies
+	var errs []error
+	if group != "" {
+		plans, errs = ValidatePlanGroup(info, group)
+	} else {
+		plans, errs = ValidatePlansWithMode(info)
+	}
 
 	if len(plans) == 0 {
+		if group != "" {
+			fmt.Printf("No plans found in group '%s'.\n", group)
+			return nil
+		}
 		fmt.Println("No plans found.")
 		return nil
 	}
@@ -331,22 +867,48 @@ func runPlanValidate(cmd *cobra.Command, args []string) error {
 	// Print mode info
 	if info.Mode == ModeWorkspace {
 		fmt.Printf("Workspace: %s (%d repos)\n\n", info.Name, len(info.Repos))
+	} else if info.Mode == ModeMonorepo {
+		fmt.Printf("Monorepo: %s (%d components)\n\n", info.Name, len(info.Components))
 	}
 
 	// Print dependency summary
 	fmt.Println("Plans:")
 	for _, p := range plans {
+		label := p.Name
+		if p.Group != "" {
+			label = p.Group + "/" + p.Name
+		}
 		if info.Mode == ModeWorkspace && p.Repository != "" {
-			fmt.Printf("  %s [repo: %s]\n", p.Name, p.Repository)
+			fmt.Printf("  %s [repo: %s]\n", label, p.Repository)
+		} else if info.Mode == ModeMonorepo && p.Component != "" {
+			fmt.Printf("  %s [component: %s]\n", label, p.Component)
 		} else {
-			fmt.Printf("  %s\n", p.Name)
+			fmt.Printf("  %s\n", label)
 		}
 		if len(p.WaitsOn) > 0 {
 			fmt.Printf("    waits on: %s\n", strings.Join(p.WaitsOn, ", "))
 		}
+		if len(p.Optional) > 0 {
+			fmt.Printf("    optional: %s\n", strings.Join(p.Optional, ", "))
+		}
 		if len(p.Signals) > 0 {
 			fmt.Printf("    signals:  %s\n", strings.Join(p.Signals, ", "))
 		}
+		if len(p.External) > 0 {
+			fmt.Printf("    external: %s\n", strings.Join(p.External, ", "))
+		}
+	}
+
+	if info.Mode == ModeWorkspace {
+		printRepoOrder(plans, info)
+	}
+
+	warnings := append(optionalWaitWarnings(plans), staleSignalWarnings(plans, readRunStarted())...)
+	if len(warnings) > 0 {
+		fmt.Println("\nWarnings:")
+		for _, w := range warnings {
+			fmt.Printf("  ⚠ %s\n", w)
+		}
 	}
 
 	if len(errs) > 0 {
@@ -354,7 +916,7 @@ func runPlanValidate(cmd *cobra.Command, args []string) error {
 		for _, err := range errs {
 			fmt.Printf("  ✗ %s\n", err)
 		}
-		return fmt.Errorf("validation failed with %d error(s)", len(errs))
+		return withCode(codeValidationFailed, fmt.Errorf("validation failed with %d error(s)", len(errs)))
 	}
 
 	fmt.Println("\n✓ All dependencies valid")
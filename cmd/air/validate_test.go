@@ -244,6 +244,164 @@ func TestValidateRepositoryReferences_UnknownRepository(t *testing.T) {
 	}
 }
 
+func TestParsePlanDependencies_Consumes(t *testing.T) {
+	t.Parallel()
+
+	content := `# Plan: usersvc-feature
+
+**Repository:** usersvc
+
+**Consumes:** schema:protos/gen/, ` + "`schema:api.yaml -> third_party/api.yaml`" + `
+`
+
+	deps := parsePlanDependencies("usersvc-feature", content)
+
+	want := []ConsumeSpec{
+		{Repo: "schema", Path: "protos/gen/", Dest: "protos/gen/"},
+		{Repo: "schema", Path: "api.yaml", Dest: "third_party/api.yaml"},
+	}
+	if len(deps.Consumes) != len(want) {
+		t.Fatalf("expected %d consumes entries, got %v", len(want), deps.Consumes)
+	}
+	for i, c := range deps.Consumes {
+		if c != want[i] {
+			t.Errorf("entry %d: expected %+v, got %+v", i, want[i], c)
+		}
+	}
+}
+
+func TestParsePlanDependencies_ContextFiles(t *testing.T) {
+	t.Parallel()
+
+	content := "# Plan: api\n\n**Context files:** `api-conventions`, testing.md\n"
+	deps := parsePlanDependencies("api", content)
+
+	if len(deps.Context) != 2 || deps.Context[0] != "api-conventions" || deps.Context[1] != "testing.md" {
+		t.Errorf("expected [api-conventions testing.md], got %v", deps.Context)
+	}
+}
+
+func TestValidateConsumes(t *testing.T) {
+	t.Parallel()
+
+	info := &WorkspaceInfo{
+		Mode:  ModeWorkspace,
+		Name:  "platform",
+		Repos: []string{"schema", "usersvc"},
+	}
+
+	plans := []PlanDependencies{
+		{Name: "schema-update", Repository: "schema", Signals: []string{"schema-ready"}},
+		{Name: "ok", Repository: "usersvc", WaitsOn: []string{"schema-ready"}, Consumes: []ConsumeSpec{{Repo: "schema", Path: "gen/", Dest: "gen/"}}},
+		{Name: "untriggered", Repository: "usersvc", Consumes: []ConsumeSpec{{Repo: "schema", Path: "gen/", Dest: "gen/"}}},
+		{Name: "unknown", Repository: "usersvc", Consumes: []ConsumeSpec{{Repo: "billing", Path: "gen/", Dest: "gen/"}}},
+		{Name: "escaping", Repository: "usersvc", WaitsOn: []string{"schema-ready"}, Consumes: []ConsumeSpec{{Repo: "schema", Path: "gen/", Dest: "../gen/"}}},
+	}
+
+	errs := validateConsumes(plans, info)
+
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "waits on no channel signaled in repository 'schema'") {
+		t.Errorf("expected untriggered consume error, got %v", errs[0])
+	}
+	if !strings.Contains(errs[1].Error(), "unknown repository 'billing'") {
+		t.Errorf("expected unknown repository error, got %v", errs[1])
+	}
+	if !strings.Contains(errs[2].Error(), "outside its worktree") {
+		t.Errorf("expected escaping destination error, got %v", errs[2])
+	}
+}
+
+func TestRepoDependencies(t *testing.T) {
+	t.Parallel()
+
+	plans := []PlanDependencies{
+		{Name: "protos", Repository: "schema", Signals: []string{"schema-ready"}},
+		{Name: "users", Repository: "usersvc", WaitsOn: []string{"schema-ready"}, Signals: []string{"users-ready"}},
+		{Name: "users-2", Repository: "usersvc", WaitsOn: []string{"schema-ready", "users-ready"}},
+		{Name: "login", Repository: "authapi", WaitsOn: []string{"users-ready"}},
+	}
+
+	deps := repoDependencies(plans)
+
+	if len(deps["usersvc"]) != 1 || deps["usersvc"][0] != "schema" {
+		t.Errorf("expected usersvc to depend only on schema, got %v", deps["usersvc"])
+	}
+	if len(deps["authapi"]) != 1 || deps["authapi"][0] != "usersvc" {
+		t.Errorf("expected authapi to depend on usersvc, got %v", deps["authapi"])
+	}
+	if len(deps["schema"]) != 0 {
+		t.Errorf("expected schema to have no dependencies, got %v", deps["schema"])
+	}
+}
+
+// ============================================================================
+// validateComponentReferences tests (monorepo mode)
+// ============================================================================
+
+func TestParsePlanDependencies_ComponentAndScope(t *testing.T) {
+	t.Parallel()
+
+	content := `# Plan: auth-tokens
+
+**Component:** services/auth
+
+**Objective:** Add token refresh
+
+## Boundaries
+
+**In scope:**
+- ` + "`services/auth/tokens/`" + ` - refresh logic
+- services/auth/cmd/main.go
+
+**Out of scope:**
+- services/billing/
+`
+
+	deps := parsePlanDependencies("auth-tokens", content)
+
+	if deps.Component != "services/auth" {
+		t.Errorf("expected component 'services/auth', got %q", deps.Component)
+	}
+	if len(deps.InScope) != 2 || deps.InScope[0] != "services/auth/tokens/" || deps.InScope[1] != "services/auth/cmd/main.go" {
+		t.Errorf("expected two in-scope paths, got %v", deps.InScope)
+	}
+}
+
+func TestValidateComponentReferences(t *testing.T) {
+	t.Parallel()
+
+	info := &WorkspaceInfo{
+		Mode:       ModeMonorepo,
+		Name:       "platform",
+		Components: []string{"services/auth", "services/billing"},
+	}
+
+	plans := []PlanDependencies{
+		{Name: "ok", Component: "services/auth", InScope: []string{"services/auth/tokens/"}},
+		{Name: "missing"},
+		{Name: "unknown", Component: "services/search"},
+		{Name: "leaky", Component: "services/billing", InScope: []string{"services/billing/", "services/auth/shared.go"}},
+	}
+
+	errs := validateComponentReferences(plans, info)
+
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "missing required **Component:**") {
+		t.Errorf("expected missing component error, got %v", errs[0])
+	}
+	if !strings.Contains(errs[1].Error(), "unknown component 'services/search'") {
+		t.Errorf("expected unknown component error, got %v", errs[1])
+	}
+	if !strings.Contains(errs[2].Error(), "outside its component") || !strings.Contains(errs[2].Error(), "services/auth/shared.go") {
+		t.Errorf("expected scope boundary error, got %v", errs[2])
+	}
+}
+
 func TestValidateDependencyGraph_MissingSignaler(t *testing.T) {
 	t.Parallel()
 
@@ -264,6 +422,164 @@ func TestValidateDependencyGraph_MissingSignaler(t *testing.T) {
 	}
 }
 
+func TestValidateDependencyGraph_ExternalChannels(t *testing.T) {
+	t.Parallel()
+
+	content := `# Plan: ui
+
+## Dependencies
+
+**Waits on:**
+- ` + "`design-approved`" + ` - Sign-off from design
+- ` + "`design-aproved`" + ` - Typo
+
+**External:**
+- ` + "`design-approved`" + ` - Signaled by the design lead
+`
+	ui := parsePlanDependencies("ui", content)
+	if len(ui.External) != 1 || ui.External[0] != "design-approved" {
+		t.Fatalf("expected external channel, got %v", ui.External)
+	}
+
+	errs := validateDependencyGraph([]PlanDependencies{ui})
+
+	// The declared channel is accepted; the typo is still caught
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "'design-aproved'") {
+		t.Fatalf("expected only the typo to be reported, got %v", errs)
+	}
+
+	// Declaring a channel external that a plan signals is a conflict
+	errs = validateDependencyGraph([]PlanDependencies{
+		{Name: "ui", WaitsOn: []string{"design-approved"}, External: []string{"design-approved"}},
+		{Name: "design", Signals: []string{"design-approved"}},
+	})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "declared external") {
+		t.Errorf("expected external/signaled conflict, got %v", errs)
+	}
+}
+
+func TestValidateDependencyGraph_OptionalWaitsAreSoftEdges(t *testing.T) {
+	t.Parallel()
+
+	content := `# Plan: api
+
+## Dependencies
+
+**Waits on (optional):**
+- ` + "`fixtures-ready`" + ` - Nicer fixtures
+- ` + "`docs-ready`" + ` - Nobody signals this
+
+**Signals:**
+- ` + "`api-ready`" + `
+`
+	api := parsePlanDependencies("api", content)
+	if len(api.Optional) != 2 || len(api.WaitsOn) != 0 {
+		t.Fatalf("expected two optional waits and no hard waits, got optional=%v waits=%v", api.Optional, api.WaitsOn)
+	}
+
+	// fixtures waits hard on api while api waits optionally on fixtures: not a cycle
+	plans := []PlanDependencies{api, {Name: "fixtures", WaitsOn: []string{"api-ready"}, Signals: []string{"fixtures-ready"}}}
+	if errs := validateDependencyGraph(plans); len(errs) != 0 {
+		t.Errorf("expected optional waits to be soft edges, got %v", errs)
+	}
+
+	warnings := optionalWaitWarnings(plans)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "'docs-ready'") {
+		t.Errorf("expected a warning for the unsignaled optional channel, got %v", warnings)
+	}
+}
+
+func TestParsePlanDependencies_WaitPolicies(t *testing.T) {
+	t.Parallel()
+
+	content := `# Plan: users
+
+## Dependencies
+
+**Waits on:**
+- ` + "`schema-ready`" + ` (timeout: 45m, fallback: ask) - Generated protos
+- ` + "`setup-complete`" + ` - Scaffolding
+- ` + "`auth-ready`" + ` (timeout: soon) - Bad duration
+
+**Waits on (optional):**
+- ` + "`fixtures-ready`" + `
+`
+	deps := parsePlanDependencies("users", content)
+
+	if policy, ok := deps.waitPolicyFor("schema-ready"); !ok || policy != (WaitPolicy{Timeout: "45m", Fallback: "ask"}) {
+		t.Errorf("expected annotated policy, got %+v (%v)", policy, ok)
+	}
+	if _, ok := deps.waitPolicyFor("setup-complete"); ok {
+		t.Error("unannotated wait should have no policy")
+	}
+	if policy, _ := deps.waitPolicyFor("fixtures-ready"); policy != (WaitPolicy{Timeout: "10m", Fallback: "proceed"}) {
+		t.Errorf("expected optional default policy, got %+v", policy)
+	}
+
+	errs := validateWaitPolicies([]PlanDependencies{deps})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "invalid timeout 'soon'") {
+		t.Errorf("expected invalid timeout error, got %v", errs)
+	}
+
+	instructions := buildCoordinationSteps(deps, nil, ModeSingle)
+	if !strings.Contains(instructions, "air agent wait --timeout 45m --on-timeout ask schema-ready") {
+		t.Errorf("expected generated wait command, got: %s", instructions)
+	}
+	if !strings.Contains(instructions, "`air agent wait setup-complete`") {
+		t.Errorf("unannotated waits should get a plain wait command, got: %s", instructions)
+	}
+}
+
+func TestBuildCoordinationSteps(t *testing.T) {
+	t.Parallel()
+
+	plans := []PlanDependencies{
+		{Name: "core", Repository: "api", Signals: []string{"core-ready"}},
+		{Name: "schema", Repository: "protos", Signals: []string{"schema-ready"}},
+		{Name: "docs", Repository: "web", Signals: []string{"docs-ready"}},
+		{
+			Name:       "auth",
+			Repository: "api",
+			WaitsOn:    []string{"core-ready", "schema-ready", "design-approved"},
+			Optional:   []string{"docs-ready"},
+			Signals:    []string{"auth-ready"},
+			Consumes:   []ConsumeSpec{{Repo: "protos", Path: "gen/", Dest: "gen/"}},
+		},
+	}
+
+	steps := buildCoordinationSteps(plans[3], plans, ModeWorkspace)
+	for _, want := range []string{
+		"1. Run `air agent wait core-ready`, then `air agent merge core-ready`",
+		"2. Run `air agent wait schema-ready` (schema works in repo `protos`, so don't merge it; the wait copies protos:gen/ into your worktree",
+		"3. Run `air agent wait design-approved` (signaled outside the plan set",
+		"4. Run `air agent wait --timeout 10m --on-timeout proceed docs-ready` (docs works in repo `web`, so don't merge it; read its work with `air agent merge --fetch docs-ready`",
+		"5. Implement your assignment and commit your changes",
+		"6. Run `air agent signal auth-ready`",
+		"7. Run `air agent done` when complete",
+		"On timeout:",
+	} {
+		if !strings.Contains(steps, want) {
+			t.Errorf("expected %q in steps, got:\n%s", want, steps)
+		}
+	}
+
+	// In a single repo every producer can be merged; optional ones only if signaled
+	plans[3].Consumes = nil
+	steps = buildCoordinationSteps(plans[3], plans, ModeSingle)
+	if !strings.Contains(steps, "then if it was signaled, `air agent merge docs-ready`") {
+		t.Errorf("expected conditional merge for optional wait, got:\n%s", steps)
+	}
+	if strings.Contains(steps, "--fetch") {
+		t.Errorf("single-repo steps should not fetch, got:\n%s", steps)
+	}
+
+	// No dependencies: just work and finish
+	steps = buildCoordinationSteps(PlanDependencies{Name: "solo"}, nil, ModeSingle)
+	if !strings.Contains(steps, "1. Implement your assignment") || !strings.Contains(steps, "2. Run `air agent done`") || strings.Contains(steps, "On timeout") {
+		t.Errorf("unexpected steps for independent plan:\n%s", steps)
+	}
+}
+
 func TestValidateDependencyGraph_DuplicateSignaler(t *testing.T) {
 	t.Parallel()
 
@@ -417,3 +733,30 @@ func TestPlanValidate_InvalidGraph(t *testing.T) {
 	}
 }
 
+
+func TestPlanValidate_ReportsRepositoryOrder(t *testing.T) {
+	t.Parallel()
+	env := setupTestWorkspace(t)
+	defer env.cleanup()
+
+	env.run(t, nil, "init")
+
+	plansDir := filepath.Join(env.airDir(), "plans")
+	os.WriteFile(filepath.Join(plansDir, "users.md"), []byte("# Plan: users\n\n**Repository:** usersvc\n\n**Waits on:**\n- `schema-ready`\n\n**Signals:**\n- `users-ready`\n"), 0644)
+	os.WriteFile(filepath.Join(plansDir, "protos.md"), []byte("# Plan: protos\n\n**Repository:** schema\n\n**Signals:**\n- `schema-ready`\n"), 0644)
+
+	out, err := env.run(t, nil, "plan", "validate")
+	if err != nil {
+		t.Fatalf("validate failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "usersvc (after schema)") {
+		t.Errorf("expected repository order, got: %s", out)
+	}
+
+	// A plan in schema waiting on usersvc creates a repo-level cycle
+	os.WriteFile(filepath.Join(plansDir, "docs.md"), []byte("# Plan: docs\n\n**Repository:** schema\n\n**Waits on:**\n- `users-ready`\n"), 0644)
+	out, _ = env.run(t, nil, "plan", "validate")
+	if !strings.Contains(out, "repository dependency cycle") {
+		t.Errorf("expected repo cycle warning, got: %s", out)
+	}
+}
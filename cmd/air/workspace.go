@@ -0,0 +1,544 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manage multi-repo workspaces",
+}
+
+var workspaceCloneCmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Clone workspace repos from a manifest or GitHub query",
+	Long: `Clones the repos listed in air.workspace.yaml (each entry needs a 'url') into
+the current directory. Repos that are already present are skipped.
+
+Alternatively, clone every repo of a GitHub organization (optionally limited to
+a team) using the gh CLI:
+
+  air workspace clone --github-org acme
+  air workspace clone --github-org acme --github-team platform`,
+	Args: cobra.NoArgs,
+	RunE: runWorkspaceClone,
+}
+
+var cloneManifestPath string
+var cloneGitHubOrg string
+var cloneGitHubTeam string
+
+func init() {
+	workspaceCmd.AddCommand(workspaceCloneCmd)
+	workspaceCloneCmd.Flags().StringVar(&cloneManifestPath, "manifest", workspaceManifestFile, "Manifest to read repos from")
+	workspaceCloneCmd.Flags().StringVar(&cloneGitHubOrg, "github-org", "", "Clone all repos of this GitHub organization (requires gh)")
+	workspaceCloneCmd.Flags().StringVar(&cloneGitHubTeam, "github-team", "", "Limit --github-org to repos of this team")
+}
+
+// workspaceManifestFile is the optional manifest at the workspace root that
+// lists member repos explicitly instead of relying on directory scanning.
+const workspaceManifestFile = "air.workspace.yaml"
+
+// WorkspaceManifest is the parsed contents of air.workspace.yaml
+type WorkspaceManifest struct {
+	Repos    []ManifestRepo  `yaml:"repos"`              // Explicit repo list (overrides discovery)
+	Discover DiscoveryConfig `yaml:"discover,omitempty"` // Scanning settings when repos is empty
+
+	// Components are glob patterns (e.g. "services/*") selecting directories of a
+	// single repo that plans target like workspace repos (monorepo mode)
+	Components []string `yaml:"components,omitempty"`
+}
+
+// DiscoveryConfig controls how repos are found by scanning the workspace root
+type DiscoveryConfig struct {
+	Depth   int      `yaml:"depth,omitempty"`   // Directory levels below the root to search (default 1)
+	Include []string `yaml:"include,omitempty"` // Glob patterns a repo's relative path must match (e.g. "org/*/*")
+	Exclude []string `yaml:"exclude,omitempty"` // Glob patterns for directories to skip (matched against relative path and name)
+}
+
+// ManifestRepo describes a single member repo in the workspace manifest
+type ManifestRepo struct {
+	Path  string `yaml:"path"`            // Directory relative to the workspace root
+	URL   string `yaml:"url,omitempty"`   // Clone URL (used by 'air workspace clone')
+	Alias string `yaml:"alias,omitempty"` // Name used in plans (defaults to the path basename)
+	Base  string `yaml:"base,omitempty"`  // Base branch for new worktrees (defaults to the repo's HEAD)
+}
+
+// WorkspaceRepo holds the resolved configuration for a repo in the workspace
+type WorkspaceRepo struct {
+	Name string // Name used in plans, channels, and worktree paths
+	Path string // Absolute path to the repo
+	Base string // Base branch for new worktrees (empty = current HEAD)
+}
+
+// loadWorkspaceManifest reads air.workspace.yaml from dir.
+// Returns nil (and no error) if the manifest does not exist.
+func loadWorkspaceManifest(dir string) (*WorkspaceManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, workspaceManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", workspaceManifestFile, err)
+	}
+
+	var manifest WorkspaceManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", workspaceManifestFile, err)
+	}
+	return &manifest, nil
+}
+
+// resolveManifestRepos validates the manifest entries against the filesystem
+// and returns the resolved repos in manifest order.
+func resolveManifestRepos(root string, manifest *WorkspaceManifest) ([]WorkspaceRepo, error) {
+	seen := make(map[string]bool)
+	var repos []WorkspaceRepo
+	for _, r := range manifest.Repos {
+		if r.Path == "" {
+			return nil, fmt.Errorf("%s: repo entry is missing 'path'", workspaceManifestFile)
+		}
+		path := r.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		if !isGitRepo(path) {
+			return nil, fmt.Errorf("%s: %s is not a git repository", workspaceManifestFile, r.Path)
+		}
+
+		name := r.Alias
+		if name == "" {
+			name = filepath.Base(path)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s: duplicate repo name %q (use 'alias' to disambiguate)", workspaceManifestFile, name)
+		}
+		seen[name] = true
+
+		repos = append(repos, WorkspaceRepo{Name: name, Path: path, Base: r.Base})
+	}
+	return repos, nil
+}
+
+// selectManifestRepos writes air.workspace.yaml at info's root to list only
+// the named repos of the workspace, keeping the entries (and comments) of any
+// already listed, and returns the workspace as it then is. Names may be any
+// repo the workspace has, not only those the manifest lists now, so a
+// selection can be widened again.
+func selectManifestRepos(info *WorkspaceInfo, names []string) (*WorkspaceInfo, error) {
+	if info.Mode != ModeWorkspace {
+		return nil, withCode(codeUsage, fmt.Errorf("--repos only applies to a workspace of several repos; %s is a %s project", info.Root, info.Mode))
+	}
+	manifest, err := loadWorkspaceManifest(info.Root)
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		manifest = &WorkspaceManifest{}
+	}
+
+	// Every repo on disk, as discovery finds them with the manifest's list ignored
+	found, err := discoverRepos(info.Root, manifest.Discover)
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[string]string)
+	for _, r := range found {
+		paths[r.Name] = r.Path
+	}
+	for _, name := range info.Repos {
+		paths[name] = info.repoDir(name)
+	}
+	var available []string
+	for name := range paths {
+		available = append(available, name)
+	}
+	sort.Strings(available)
+
+	// Edit the repos list of the file as written, so its comments survive
+	path := filepath.Join(info.Root, workspaceManifestFile)
+	var doc yaml.Node
+	if data, err := os.ReadFile(path); err == nil {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", workspaceManifestFile, err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: expected a mapping at the top level", workspaceManifestFile)
+	}
+	var reposNode *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "repos" {
+			reposNode = root.Content[i+1]
+		}
+	}
+	if reposNode == nil {
+		reposNode = &yaml.Node{}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "repos"}, reposNode)
+	}
+	listed := make(map[string]*yaml.Node)
+	for _, entry := range reposNode.Content {
+		var r ManifestRepo
+		if entry.Decode(&r) != nil {
+			continue
+		}
+		name := r.Alias
+		if name == "" {
+			name = filepath.Base(r.Path)
+		}
+		listed[name] = entry
+	}
+
+	selected := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, name := range names {
+		if entry, ok := listed[name]; ok {
+			selected.Content = append(selected.Content, entry)
+			continue
+		}
+		repoPath, ok := paths[name]
+		if !ok {
+			return nil, withCode(codeUsage, fmt.Errorf("no repo named '%s' in this workspace (found: %s)", name, strings.Join(available, ", ")))
+		}
+		if rel, err := filepath.Rel(info.Root, repoPath); err == nil && filepath.IsLocal(rel) {
+			repoPath = filepath.ToSlash(rel)
+		}
+		var entry yaml.Node
+		if err := entry.Encode(ManifestRepo{Path: repoPath}); err != nil {
+			return nil, err
+		}
+		selected.Content = append(selected.Content, &entry)
+	}
+	selected.HeadComment, selected.LineComment, selected.FootComment = reposNode.HeadComment, reposNode.LineComment, reposNode.FootComment
+	*reposNode = *selected
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	enc.Close()
+	if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", workspaceManifestFile, err)
+	}
+	fmt.Printf("Wrote %s with %d of the %d repos found\n", workspaceManifestFile, len(names), len(available))
+	return detectMode()
+}
+
+// isGitRepo reports whether dir is the root of a git repository: a checkout
+// with a .git directory, a bare repository, or a bare "hub" (a .git file
+// pointing at a bare repository, with worktrees checked out beside it)
+func isGitRepo(dir string) bool {
+	stat, err := os.Stat(filepath.Join(dir, ".git"))
+	if err == nil && stat.IsDir() {
+		return true
+	}
+	// A .git file is usually a linked worktree or submodule; only hubs count
+	if err == nil || looksLikeBareRepo(dir) {
+		return isBareRepo(dir)
+	}
+	return false
+}
+
+// looksLikeBareRepo checks for the layout of a bare repository without running git
+func looksLikeBareRepo(dir string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// isBareRepo reports whether dir is a bare repository or a hub pointing at one.
+// Agent worktrees are created from it as usual; merges need a checkout.
+func isBareRepo(dir string) bool {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--is-bare-repository").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// checkoutFor returns the directory to run merges into repoPath's branch in:
+// repoPath itself, or for a bare repository the worktree that has branch
+// checked out (HEAD's branch if empty)
+func checkoutFor(repoPath, branch string) (string, error) {
+	if !isBareRepo(repoPath) {
+		return repoPath, nil
+	}
+	if branch == "" {
+		out, _ := exec.Command("git", "-C", repoPath, "symbolic-ref", "--short", "HEAD").Output()
+		branch = strings.TrimSpace(string(out))
+	}
+	out, err := exec.Command("git", "-C", repoPath, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees of %s: %w", repoPath, err)
+	}
+	var path string
+	for _, line := range strings.Split(string(out), "\n") {
+		if p, ok := strings.CutPrefix(line, "worktree "); ok {
+			path = p
+		} else if line == "branch refs/heads/"+branch {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s is a bare repository with no worktree on %s; check one out to merge into (git -C %s worktree add <path> %s)",
+		repoPath, branch, repoPath, branch)
+}
+
+// discovery returns the scanning settings, tolerating a nil manifest
+func (m *WorkspaceManifest) discovery() DiscoveryConfig {
+	if m == nil {
+		return DiscoveryConfig{}
+	}
+	return m.Discover
+}
+
+// maxDepth returns how many levels to scan. Without an explicit depth,
+// include patterns imply the depth needed to reach them.
+func (c DiscoveryConfig) maxDepth() int {
+	if c.Depth > 0 {
+		return c.Depth
+	}
+	depth := 1
+	for _, p := range c.Include {
+		if n := len(strings.Split(p, "/")); n > depth {
+			depth = n
+		}
+	}
+	return depth
+}
+
+// matches reports whether a repo at relPath (slash-separated) should be included
+func (c DiscoveryConfig) matches(relPath string) bool {
+	if c.excluded(relPath) {
+		return false
+	}
+	if len(c.Include) == 0 {
+		return true
+	}
+	for _, p := range c.Include {
+		if ok, _ := filepath.Match(p, relPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded reports whether a directory at relPath matches an exclude pattern.
+// Patterns match either the full relative path ("archive/*") or the directory
+// name alone ("*-fork"), so scratch clones can be skipped wherever they live.
+func (c DiscoveryConfig) excluded(relPath string) bool {
+	name := relPath[strings.LastIndex(relPath, "/")+1:]
+	for _, p := range c.Exclude {
+		if ok, _ := filepath.Match(p, relPath); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// discoverRepos scans root for git repos up to the configured depth.
+// Hidden directories are skipped, and the search does not descend into repos.
+// Repos are named by their directory basename and returned sorted by name.
+func discoverRepos(root string, cfg DiscoveryConfig) ([]WorkspaceRepo, error) {
+	if _, err := os.ReadDir(root); err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	depth := cfg.maxDepth()
+	var repos []WorkspaceRepo
+	var walk func(dir, rel string, level int)
+	walk = func(dir, rel string, level int) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if !e.IsDir() || e.Name()[0] == '.' {
+				continue
+			}
+			childPath := filepath.Join(dir, e.Name())
+			childRel := e.Name()
+			if rel != "" {
+				childRel = rel + "/" + e.Name()
+			}
+			if isGitRepo(childPath) {
+				if cfg.matches(childRel) {
+					repos = append(repos, WorkspaceRepo{Name: e.Name(), Path: childPath})
+				}
+				continue
+			}
+			if level < depth && !cfg.excluded(childRel) {
+				walk(childPath, childRel, level+1)
+			}
+		}
+	}
+	walk(root, "", 1)
+
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Name < repos[j].Name })
+
+	for i := 1; i < len(repos); i++ {
+		if repos[i].Name == repos[i-1].Name {
+			return nil, fmt.Errorf("multiple repos named %q found (%s, %s); list them in %s with aliases",
+				repos[i].Name, repos[i-1].Path, repos[i].Path, workspaceManifestFile)
+		}
+	}
+	return repos, nil
+}
+
+// resolveComponents expands component glob patterns into a sorted list of
+// directories relative to root. Hidden directories are skipped.
+func resolveComponents(root string, patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var components []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid component pattern %q: %w", workspaceManifestFile, pattern, err)
+		}
+		for _, m := range matches {
+			if stat, err := os.Stat(m); err != nil || !stat.IsDir() || filepath.Base(m)[0] == '.' {
+				continue
+			}
+			rel, err := filepath.Rel(root, m)
+			if err != nil {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+			if !seen[rel] {
+				seen[rel] = true
+				components = append(components, rel)
+			}
+		}
+	}
+
+	if len(components) == 0 {
+		return nil, fmt.Errorf("%s: component patterns %v match no directories", workspaceManifestFile, patterns)
+	}
+	sort.Strings(components)
+	return components, nil
+}
+
+func runWorkspaceClone(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	var repos []ManifestRepo
+	if cloneGitHubOrg != "" {
+		repos, err = listGitHubRepos(cloneGitHubOrg, cloneGitHubTeam)
+		if err != nil {
+			return err
+		}
+	} else {
+		if cloneGitHubTeam != "" {
+			return fmt.Errorf("--github-team requires --github-org")
+		}
+		data, err := os.ReadFile(cloneManifestPath)
+		if err != nil {
+			return fmt.Errorf("failed to read manifest: %w", err)
+		}
+		var manifest WorkspaceManifest
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", cloneManifestPath, err)
+		}
+		repos = manifest.Repos
+	}
+
+	if len(repos) == 0 {
+		fmt.Println("No repos to clone.")
+		return nil
+	}
+
+	var cloned, skipped, failed int
+	for _, r := range repos {
+		if r.Path == "" {
+			return fmt.Errorf("manifest repo entry is missing 'path'")
+		}
+		dest := r.Path
+		if !filepath.IsAbs(dest) {
+			dest = filepath.Join(cwd, dest)
+		}
+
+		if _, err := os.Stat(dest); err == nil {
+			fmt.Printf("  - %s (already present)\n", r.Path)
+			skipped++
+			continue
+		}
+		if r.URL == "" {
+			fmt.Printf("  ✗ %s (no url in manifest)\n", r.Path)
+			failed++
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", r.Path, err)
+		}
+		cloneArgs := []string{"clone", r.URL, dest}
+		if r.Base != "" {
+			cloneArgs = []string{"clone", "--branch", r.Base, r.URL, dest}
+		}
+		cloneCmd := exec.Command("git", cloneArgs...)
+		cloneCmd.Stderr = os.Stderr
+		if err := cloneCmd.Run(); err != nil {
+			fmt.Printf("  ✗ %s (clone failed: %v)\n", r.Path, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  ✓ %s\n", r.Path)
+		cloned++
+	}
+
+	fmt.Printf("\nCloned %d, skipped %d, failed %d.\n", cloned, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d repo(s) failed to clone", failed)
+	}
+	if cloned > 0 {
+		fmt.Println("Run 'air init' to initialize the workspace.")
+	}
+	return nil
+}
+
+// listGitHubRepos queries GitHub (via gh) for the repos of an org or team
+func listGitHubRepos(org, team string) ([]ManifestRepo, error) {
+	var ghArgs []string
+	if team != "" {
+		ghArgs = []string{"api", "--paginate", fmt.Sprintf("orgs/%s/teams/%s/repos", org, team),
+			"--jq", ".[] | select(.archived | not) | [.name, .ssh_url] | @tsv"}
+	} else {
+		ghArgs = []string{"repo", "list", org, "--no-archived", "--limit", "1000",
+			"--json", "name,sshUrl", "--jq", ".[] | [.name, .sshUrl] | @tsv"}
+	}
+
+	out, err := exec.Command("gh", ghArgs...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GitHub repos (is gh installed and authenticated?): %w", err)
+	}
+
+	var repos []ManifestRepo
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 {
+			continue
+		}
+		repos = append(repos, ManifestRepo{Path: fields[0], URL: fields[1]})
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Path < repos[j].Path })
+	return repos, nil
+}
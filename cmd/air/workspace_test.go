@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// air workspace clone tests
+// ============================================================================
+
+func TestWorkspaceClone_ClonesManifestRepos(t *testing.T) {
+	t.Parallel()
+	// Source repo to clone from (a regular repo works as a local clone URL)
+	src := setupTestRepo(t)
+	defer src.cleanup()
+
+	env := setupTestDir(t)
+	defer env.cleanup()
+
+	// One repo to clone, one already present
+	os.MkdirAll(filepath.Join(env.dir, "present", ".git"), 0755)
+	manifest := "repos:\n  - path: svc/api\n    url: " + src.dir + "\n  - path: present\n    url: " + src.dir + "\n"
+	os.WriteFile(filepath.Join(env.dir, "air.workspace.yaml"), []byte(manifest), 0644)
+
+	out, err := env.run(t, nil, "workspace", "clone")
+	if err != nil {
+		t.Fatalf("air workspace clone failed: %v\n%s", err, out)
+	}
+
+	if _, err := os.Stat(filepath.Join(env.dir, "svc", "api", "README.md")); err != nil {
+		t.Errorf("expected repo to be cloned into svc/api: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "present (already present)") {
+		t.Errorf("expected existing repo to be skipped, got: %s", out)
+	}
+	if !strings.Contains(out, "Cloned 1, skipped 1, failed 0") {
+		t.Errorf("expected summary, got: %s", out)
+	}
+}
+
+func TestWorkspaceClone_FailsOnMissingURL(t *testing.T) {
+	t.Parallel()
+	env := setupTestDir(t)
+	defer env.cleanup()
+
+	os.WriteFile(filepath.Join(env.dir, "air.workspace.yaml"), []byte("repos:\n  - path: nourl\n"), 0644)
+
+	out, err := env.run(t, nil, "workspace", "clone")
+	if err == nil {
+		t.Fatalf("expected clone to fail for entry without url, got: %s", out)
+	}
+	if !strings.Contains(out, "no url in manifest") {
+		t.Errorf("expected missing url to be reported, got: %s", out)
+	}
+}